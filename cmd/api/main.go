@@ -1,22 +1,58 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/enzo010/email-filter/internal/application/services"
 	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/auth"
+	"github.com/enzo010/email-filter/internal/infrastructure/calendar"
+	"github.com/enzo010/email-filter/internal/infrastructure/database"
+	"github.com/enzo010/email-filter/internal/infrastructure/inbound"
 	"github.com/enzo010/email-filter/internal/infrastructure/middleware"
+	"github.com/enzo010/email-filter/internal/infrastructure/notify"
+	"github.com/enzo010/email-filter/internal/infrastructure/safehttp"
+	"github.com/enzo010/email-filter/internal/infrastructure/storage"
+	webhookpkg "github.com/enzo010/email-filter/internal/infrastructure/webhook"
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// maxRawUploadBytes limite do corpo de upload de mensagens .eml
+const maxRawUploadBytes = 25 << 20
+
 type Server struct {
 	emailClassifier *services.EmailClassifier
-	router         *mux.Router
+	storage         *storage.Storage
+	emailRepo       entities.EmailRepository
+	tenantRepo      entities.TenantRepository
+	userRepo        entities.UserRepository
+	taskRepo        entities.TaskRepository
+	backfillRepo    entities.BackfillRepository
+	backfillOwner   string // "tenant/usuário" da caixa IMAP atendida pelo backfill
+	webhookRepo     entities.WebhookRepository
+	webhooks        *services.WebhookService
+	integrationRepo entities.IntegrationRepository
+	integrations    *services.IntegrationService
+	outbox          *services.OutboxDispatcher
+	inbound         *services.InboundService
+	inboundProvider map[string]inbound.Provider
+	router          *mux.Router
 }
 
 func NewServer() (*Server, error) {
@@ -28,15 +64,72 @@ func NewServer() (*Server, error) {
 	// Inicializar classificador
 	emailClassifier := services.NewEmailClassifier()
 
+	// Inicializar armazenamento conforme STORAGE_DRIVER
+	store, err := storage.Open(context.Background(), os.Getenv("STORAGE_DRIVER"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Webhooks de inbound habilitados conforme as credenciais configuradas
+	providers, err := inboundProviders()
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+
+	// Os eventos gravados no outbox pelos repositórios de emails e tarefas são
+	// publicados nos webhooks e nas integrações de Slack e Teams
+	webhooks := services.NewWebhookService(store.Webhooks, services.SystemClock{}, webhookConfig())
+	integrations := services.NewIntegrationService(store.Integrations, strings.TrimSuffix(os.Getenv("APP_URL"), "/"))
+	outbox := services.NewOutboxDispatcher(store.Outbox, services.SystemClock{}, outboxConfig())
+	outbox.Register("webhooks", webhooks)
+	outbox.Register("integrations", integrations)
+
 	// Inicializar router
 	router := mux.NewRouter()
 
 	return &Server{
 		emailClassifier: emailClassifier,
-		router:         router,
+		storage:         store,
+		emailRepo:       store.Emails,
+		tenantRepo:      store.Tenants,
+		userRepo:        store.Users,
+		taskRepo:        store.Tasks,
+		backfillRepo:    store.Backfill,
+		webhookRepo:     store.Webhooks,
+		webhooks:        webhooks,
+		integrationRepo: store.Integrations,
+		integrations:    integrations,
+		outbox:          outbox,
+		inbound:         services.NewInboundService(os.Getenv("INBOUND_DOMAIN"), emailClassifier, store.Emails, store.Tenants, store.Users),
+		inboundProvider: providers,
+		router:          router,
 	}, nil
 }
 
+// inboundProviders cria os provedores de webhook de inbound configurados via ambiente
+func inboundProviders() (map[string]inbound.Provider, error) {
+	providers := make(map[string]inbound.Provider)
+
+	if key := os.Getenv("SENDGRID_WEBHOOK_PUBLIC_KEY"); key != "" {
+		sendgrid, err := inbound.NewSendGrid(key)
+		if err != nil {
+			return nil, err
+		}
+		providers[sendgrid.Name()] = sendgrid
+	}
+	if key := os.Getenv("MAILGUN_WEBHOOK_SIGNING_KEY"); key != "" {
+		mailgun := inbound.NewMailgun(key)
+		providers[mailgun.Name()] = mailgun
+	}
+	if user := os.Getenv("POSTMARK_WEBHOOK_USER"); user != "" {
+		postmark := inbound.NewPostmark(user, os.Getenv("POSTMARK_WEBHOOK_PASSWORD"))
+		providers[postmark.Name()] = postmark
+	}
+
+	return providers, nil
+}
+
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		origin := r.Header.Get("Origin")
@@ -77,6 +170,62 @@ func (s *Server) setupRoutes() {
 
 	// Endpoint de classificação
 	api.HandleFunc("/classify", s.handleClassifyEmail).Methods("POST")
+
+	// Webhooks de inbound (autenticados pela assinatura do provedor)
+	api.HandleFunc("/inbound/{provider}", s.handleInboundWebhook).Methods("POST")
+
+	// Feed iCalendar das tarefas (autenticado pelo token da URL)
+	api.HandleFunc("/calendar/{tenant_id}/{user_id}/tasks.ics", s.handleCalendarFeed).Methods("GET")
+
+	// Endpoints autenticados, escopados pelo tenant do token
+	protected := api.PathPrefix("").Subrouter()
+	protected.Use(middleware.AuthMiddleware)
+	protected.Use(tenantScope)
+
+	// Classificação de mensagens RFC 822 (.eml)
+	protected.HandleFunc("/classify/raw", s.handleClassifyRaw).Methods("POST")
+
+	// Listagem e busca textual
+	protected.HandleFunc("/emails", s.handleListEmails).Methods("GET")
+	protected.HandleFunc("/emails/search", s.handleSearchEmails).Methods("GET")
+
+	// Importação histórica (backfill)
+	protected.HandleFunc("/backfill", s.handleCreateBackfill).Methods("POST")
+	protected.HandleFunc("/backfill/{id}", s.handleGetBackfill).Methods("GET")
+	protected.HandleFunc("/backfill/{id}/pause", s.handleSetBackfillStatus(entities.BackfillPaused)).Methods("POST")
+	protected.HandleFunc("/backfill/{id}/resume", s.handleSetBackfillStatus(entities.BackfillPending)).Methods("POST")
+
+	// Ciclo de vida das tarefas
+	protected.HandleFunc("/tasks", s.handleListTasks).Methods("GET")
+	protected.HandleFunc("/tasks", s.handleCreateTask).Methods("POST")
+	protected.HandleFunc("/tasks/{id}", s.handleGetTask).Methods("GET")
+	protected.HandleFunc("/tasks/{id}", s.handleUpdateTask).Methods("PATCH")
+	protected.HandleFunc("/tasks/{id}/history", s.handleTaskHistory).Methods("GET")
+	protected.HandleFunc("/tasks/{id}/assign", s.handleAssignTask).Methods("POST")
+	protected.HandleFunc("/tasks/{id}/start", s.handleTaskTransition(entities.TaskInProgress)).Methods("POST")
+	protected.HandleFunc("/tasks/{id}/snooze", s.handleTaskTransition(entities.TaskSnoozed)).Methods("POST")
+	protected.HandleFunc("/tasks/{id}/complete", s.handleTaskTransition(entities.TaskCompleted)).Methods("POST")
+	protected.HandleFunc("/tasks/{id}/reopen", s.handleTaskTransition(entities.TaskPending)).Methods("POST")
+	protected.HandleFunc("/tasks/{id}/cancel", s.handleTaskTransition(entities.TaskCancelled)).Methods("POST")
+
+	// Link do feed de calendário do usuário autenticado; rotate revoga os links anteriores
+	protected.HandleFunc("/calendar/feed", s.handleCalendarFeedURL).Methods("GET")
+	protected.HandleFunc("/calendar/feed/rotate", s.handleRotateCalendarFeed).Methods("POST")
+
+	// Webhooks de saída e registro de entregas (administradores do tenant)
+	protected.HandleFunc("/webhooks", s.handleListWebhooks).Methods("GET")
+	protected.HandleFunc("/webhooks", s.handleCreateWebhook).Methods("POST")
+	protected.HandleFunc("/webhooks/{id}", s.handleGetWebhook).Methods("GET")
+	protected.HandleFunc("/webhooks/{id}", s.handleUpdateWebhook).Methods("PATCH")
+	protected.HandleFunc("/webhooks/{id}", s.handleDeleteWebhook).Methods("DELETE")
+	protected.HandleFunc("/webhooks/{id}/deliveries", s.handleListWebhookDeliveries).Methods("GET")
+	protected.HandleFunc("/webhooks/{id}/deliveries/{delivery_id}/redeliver", s.handleRedeliverWebhook).Methods("POST")
+	protected.HandleFunc("/integrations", s.handleListIntegrations).Methods("GET")
+	protected.HandleFunc("/integrations", s.handleCreateIntegration).Methods("POST")
+	protected.HandleFunc("/integrations/{id}", s.handleGetIntegration).Methods("GET")
+	protected.HandleFunc("/integrations/{id}", s.handleUpdateIntegration).Methods("PATCH")
+	protected.HandleFunc("/integrations/{id}", s.handleDeleteIntegration).Methods("DELETE")
+	protected.HandleFunc("/integrations/{id}/test", s.handleTestIntegration).Methods("POST")
 }
 
 func (s *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
@@ -96,17 +245,17 @@ func (s *Server) handleClassifyEmail(w http.ResponseWriter, r *http.Request) {
 	result := &entities.ClassificationResult{
 		Priority: "high",
 		Category: "meeting",
-		Labels: []string{"urgent", "report", "meeting"},
-		SuggestedTasks: []*entities.Task{
+		Labels:   []string{"urgent", "report", "meeting"},
+		SuggestedTasks: []*entities.SuggestedTask{
 			{
-				Title: "Review quarterly report",
+				Title:       "Review quarterly report",
 				Description: "Review the quarterly report before tomorrow's meeting",
-				Priority: "high",
+				Priority:    "high",
 			},
 			{
-				Title: "Prepare meeting notes",
+				Title:       "Prepare meeting notes",
 				Description: "Prepare notes for tomorrow's urgent meeting",
-				Priority: "medium",
+				Priority:    "medium",
 			},
 		},
 	}
@@ -114,6 +263,1410 @@ func (s *Server) handleClassifyEmail(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(result)
 }
 
+func (s *Server) handleClassifyRaw(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRawUploadBytes)
+	store := r.URL.Query().Get("store") == "true"
+
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		respondError(w, http.StatusUnsupportedMediaType, "Content-Type inválido")
+		return
+	}
+
+	// Todas as mensagens são interpretadas e classificadas antes de qualquer
+	// gravação: um arquivo inválido recusa o envio inteiro sem salvar os demais
+	var uploads []*rawUpload
+	switch mediaType {
+	case "message/rfc822":
+		upload, err := s.classifyRaw(r, r.Body)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		uploads = append(uploads, upload)
+
+	case "multipart/form-data":
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if part.FileName() == "" {
+				continue
+			}
+
+			upload, err := s.classifyRaw(r, part)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, fmt.Sprintf("%s: %v", part.FileName(), err))
+				return
+			}
+			uploads = append(uploads, upload)
+		}
+
+	default:
+		respondError(w, http.StatusUnsupportedMediaType, "use message/rfc822 ou multipart/form-data")
+		return
+	}
+
+	// As mensagens são salvas em uma única transação: uma falha não deixa parte
+	// do envio gravada, e o cliente pode repetir o envio inteiro
+	if store {
+		emails := make([]*entities.Email, len(uploads))
+		for i, upload := range uploads {
+			emails[i] = rawEmail(r, upload)
+		}
+		if _, err := s.emailRepo.CreateAll(r.Context(), emails); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	results := make([]*entities.ClassificationResult, 0, len(uploads))
+	for _, upload := range uploads {
+		results = append(results, toClassificationResponse(upload.result))
+	}
+
+	if mediaType == "message/rfc822" {
+		respondJSON(w, http.StatusOK, results[0])
+		return
+	}
+	respondJSON(w, http.StatusOK, results)
+}
+
+// rawUpload mensagem enviada em handleClassifyRaw, já classificada
+type rawUpload struct {
+	email  *entities.Email
+	result *services.ClassificationResult
+}
+
+// classifyRaw interpreta e classifica uma mensagem RFC 822
+func (s *Server) classifyRaw(r *http.Request, body io.Reader) (*rawUpload, error) {
+	email, err := services.ParseMessage(body)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.emailClassifier.ClassifyEmail(r.Context(), email)
+	if err != nil {
+		return nil, err
+	}
+	return &rawUpload{email: email, result: result}, nil
+}
+
+// rawEmail prepara a mensagem classificada para a pasta "upload" do usuário
+func rawEmail(r *http.Request, upload *rawUpload) *entities.Email {
+	email := upload.email
+	email.TenantID, email.UserID = requestOwner(r)
+	email.Folder = "upload"
+	services.ApplyClassification(email, upload.result)
+	return email
+}
+
+func toClassificationResponse(result *services.ClassificationResult) *entities.ClassificationResult {
+	response := &entities.ClassificationResult{
+		Priority:       string(result.Priority),
+		Category:       result.Category,
+		Labels:         result.Labels,
+		SuggestedTasks: []*entities.SuggestedTask{},
+	}
+	for _, task := range result.SuggestedTasks {
+		response.SuggestedTasks = append(response.SuggestedTasks, &entities.SuggestedTask{
+			Title:       task.Description,
+			Description: task.Description,
+			Priority:    string(task.Priority),
+		})
+	}
+	if response.Labels == nil {
+		response.Labels = []string{}
+	}
+	return response
+}
+
+// handleListEmails lista os emails do tenant.
+// Filtros com vários valores podem ser repetidos: ?category=work&category=finance
+func (s *Server) handleListEmails(w http.ResponseWriter, r *http.Request) {
+	filter, err := emailFilterFromQuery(r.URL.Query())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenantID, _ := requestOwner(r)
+	page, err := s.emailRepo.ListByTenant(r.Context(), tenantID, filter)
+	if errors.Is(err, entities.ErrInvalidFilter) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao listar emails: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao listar emails")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, page)
+}
+
+// emailFilterFromQuery converte os parâmetros da URL no filtro de emails
+func emailFilterFromQuery(q url.Values) (*entities.EmailFilter, error) {
+	filter := &entities.EmailFilter{
+		Categories: queryList(q, "category"),
+		Labels:     queryList(q, "label"),
+		Folders:    queryList(q, "folder"),
+		Sort:       q.Get("sort"),
+		Order:      entities.SortOrder(q.Get("order")),
+	}
+	for _, p := range queryList(q, "priority") {
+		filter.Priorities = append(filter.Priorities, entities.Priority(p))
+	}
+
+	var err error
+	if filter.StartDate, err = queryTime(q, "start_date"); err != nil {
+		return nil, err
+	}
+	if filter.EndDate, err = queryTime(q, "end_date"); err != nil {
+		return nil, err
+	}
+	if filter.Page, err = pageFromQuery(q); err != nil {
+		return nil, err
+	}
+
+	return filter, filter.Validate()
+}
+
+// pageFromQuery lê a paginação: page_size, after/before (cursores devolvidos
+// em next_cursor/prev_cursor) e total=true para incluir a contagem
+func pageFromQuery(q url.Values) (entities.Page, error) {
+	page := entities.Page{
+		After:     q.Get("after"),
+		Before:    q.Get("before"),
+		WithTotal: q.Get("total") == "true",
+	}
+	var err error
+	page.PageSize, err = queryInt(q, "page_size")
+	return page, err
+}
+
+// queryList retorna os valores do parâmetro, aceitando repetição ou vírgulas
+func queryList(q url.Values, key string) []string {
+	var values []string
+	for _, v := range q[key] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// queryInt lê um parâmetro inteiro opcional
+func queryInt(q url.Values, key string) (int, error) {
+	v := q.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s deve ser um número", entities.ErrInvalidFilter, key)
+	}
+	return n, nil
+}
+
+// queryTime lê uma data opcional em RFC 3339 ou AAAA-MM-DD
+func queryTime(q url.Values, key string) (*time.Time, error) {
+	v := q.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s deve estar no formato AAAA-MM-DD ou RFC 3339", entities.ErrInvalidFilter, key)
+}
+
+// handleSearchEmails busca emails do tenant. Ex: q=relatório "prazo final" from:ana label:urgent
+func (s *Server) handleSearchEmails(w http.ResponseWriter, r *http.Request) {
+	query := entities.ParseSearchQuery(r.URL.Query().Get("q"))
+	if query.IsEmpty() {
+		respondError(w, http.StatusBadRequest, "parâmetro q é obrigatório")
+		return
+	}
+
+	page, err := pageFromQuery(r.URL.Query())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenantID, _ := requestOwner(r)
+	results, err := s.emailRepo.Search(r.Context(), tenantID, query, page)
+	if errors.Is(err, entities.ErrInvalidFilter) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Erro na busca de emails: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao buscar emails")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, results)
+}
+
+func (s *Server) handleCreateBackfill(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Days   int    `json:"days"`
+		Folder string `json:"folder"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Days <= 0 || req.Days > 3650 {
+		respondError(w, http.StatusBadRequest, "days deve estar entre 1 e 3650")
+		return
+	}
+
+	// Jobs só são executados para a caixa IMAP configurada no servidor
+	tenantID, userID := requestOwner(r)
+	if s.backfillOwner != tenantID+"/"+userID {
+		respondError(w, http.StatusConflict, "nenhuma caixa IMAP configurada para o usuário")
+		return
+	}
+
+	job := services.NewBackfillJob(tenantID, userID, req.Folder, req.Days, time.Now())
+	if err := s.backfillRepo.Create(r.Context(), job); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+func (s *Server) handleGetBackfill(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.loadBackfill(w, r)
+	if !ok {
+		return
+	}
+	respondJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) handleSetBackfillStatus(status entities.BackfillStatus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, ok := s.loadBackfill(w, r)
+		if !ok {
+			return
+		}
+
+		// Somente jobs em andamento podem ser pausados, e somente pausados retomados
+		switch {
+		case status == entities.BackfillPaused && (job.Status == entities.BackfillPending || job.Status == entities.BackfillRunning):
+		case status == entities.BackfillPending && job.Status == entities.BackfillPaused:
+		default:
+			respondError(w, http.StatusConflict, "transição inválida a partir do status "+string(job.Status))
+			return
+		}
+
+		if err := s.backfillRepo.UpdateStatus(r.Context(), job.ID, status); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		job.Status = status
+		respondJSON(w, http.StatusOK, job)
+	}
+}
+
+// loadBackfill busca o job da URL garantindo que pertence ao tenant autenticado
+func (s *Server) loadBackfill(w http.ResponseWriter, r *http.Request) (*entities.BackfillJob, bool) {
+	job, err := s.backfillRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	tenantID, _ := requestOwner(r)
+	if err != nil || job.TenantID != tenantID {
+		respondError(w, http.StatusNotFound, "job de backfill não encontrado")
+		return nil, false
+	}
+	return job, true
+}
+
+// handleListTasks lista as tarefas do tenant. Filtros: status, priority,
+// assignee (id ou "me"), email_id, start_date/end_date (prazo), sort e order
+func (s *Server) handleListTasks(w http.ResponseWriter, r *http.Request) {
+	filter, err := taskFilterFromQuery(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var page *entities.PageResult[*entities.Task]
+	if emailID := r.URL.Query().Get("email_id"); emailID != "" {
+		page, err = s.taskRepo.ListByEmail(r.Context(), emailID, filter)
+	} else {
+		tenantID, _ := requestOwner(r)
+		page, err = s.taskRepo.ListByTenant(r.Context(), tenantID, filter)
+	}
+	if errors.Is(err, entities.ErrInvalidFilter) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao listar tarefas: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao listar tarefas")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, page)
+}
+
+// taskFilterFromQuery converte os parâmetros da URL no filtro de tarefas
+func taskFilterFromQuery(r *http.Request) (*entities.TaskFilter, error) {
+	q := r.URL.Query()
+	filter := &entities.TaskFilter{
+		Statuses:   queryList(q, "status"),
+		AssigneeID: q.Get("assignee"),
+		Sort:       q.Get("sort"),
+		Order:      entities.SortOrder(q.Get("order")),
+	}
+	if filter.AssigneeID == "me" {
+		_, filter.AssigneeID = requestOwner(r)
+	}
+	for _, p := range queryList(q, "priority") {
+		filter.Priorities = append(filter.Priorities, entities.Priority(p))
+	}
+
+	var err error
+	if filter.StartDate, err = queryTime(q, "start_date"); err != nil {
+		return nil, err
+	}
+	if filter.EndDate, err = queryTime(q, "end_date"); err != nil {
+		return nil, err
+	}
+	if filter.Page, err = pageFromQuery(q); err != nil {
+		return nil, err
+	}
+
+	return filter, filter.Validate()
+}
+
+// handleCreateTask cria manualmente uma tarefa pendente vinculada a um email do tenant
+func (s *Server) handleCreateTask(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		EmailID     string            `json:"email_id"`
+		Description string            `json:"description"`
+		DueDate     time.Time         `json:"due_date"`
+		Priority    entities.Priority `json:"priority"`
+		AssigneeID  string            `json:"assignee_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task := &entities.Task{
+		EmailID:     req.EmailID,
+		Description: strings.TrimSpace(req.Description),
+		DueDate:     req.DueDate,
+		Priority:    req.Priority,
+		Status:      entities.TaskPending,
+		AssigneeID:  req.AssigneeID,
+	}
+	if task.Priority == "" {
+		task.Priority = entities.PriorityMedium
+	}
+	if msg := validateTask(task); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if _, err := s.emailRepo.GetByID(r.Context(), task.EmailID); err != nil {
+		respondError(w, http.StatusBadRequest, "email não encontrado")
+		return
+	}
+	if !s.validAssignee(r, task.AssigneeID) {
+		respondError(w, http.StatusBadRequest, "responsável não encontrado no tenant")
+		return
+	}
+
+	if err := s.taskRepo.Create(r.Context(), task); err != nil {
+		log.Printf("Erro ao criar tarefa: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao criar tarefa")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, task)
+}
+
+func (s *Server) handleGetTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.loadTask(w, r)
+	if !ok {
+		return
+	}
+	respondJSON(w, http.StatusOK, task)
+}
+
+// handleUpdateTask altera descrição, prazo e prioridade; campos omitidos são mantidos
+func (s *Server) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.loadTask(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Description *string            `json:"description"`
+		DueDate     *time.Time         `json:"due_date"`
+		Priority    *entities.Priority `json:"priority"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Description != nil {
+		task.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.DueDate != nil {
+		task.DueDate = *req.DueDate
+	}
+	if req.Priority != nil {
+		task.Priority = *req.Priority
+	}
+	if msg := validateTask(task); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	s.saveTask(w, r, task)
+}
+
+// handleAssignTask define o responsável pela tarefa; assignee_id vazio remove
+func (s *Server) handleAssignTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.loadTask(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		AssigneeID string `json:"assignee_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !s.validAssignee(r, req.AssigneeID) {
+		respondError(w, http.StatusBadRequest, "responsável não encontrado no tenant")
+		return
+	}
+
+	task.AssigneeID = req.AssigneeID
+	s.saveTask(w, r, task)
+}
+
+// saveTask grava as alterações da tarefa e responde com a tarefa atualizada
+func (s *Server) saveTask(w http.ResponseWriter, r *http.Request, task *entities.Task) {
+	if err := s.taskRepo.Update(r.Context(), task); err != nil {
+		log.Printf("Erro ao atualizar tarefa: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao atualizar tarefa")
+		return
+	}
+	respondJSON(w, http.StatusOK, task)
+}
+
+// handleTaskTransition muda o status da tarefa e registra a mudança no
+// histórico. O corpo é opcional: {"note": "..."}; ao adiar, "until" é obrigatório.
+func (s *Server) handleTaskTransition(to entities.TaskStatus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		task, ok := s.loadTask(w, r)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Note  string     `json:"note"`
+			Until *time.Time `json:"until"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if to == entities.TaskSnoozed {
+			if req.Until == nil || !req.Until.After(time.Now()) {
+				respondError(w, http.StatusBadRequest, "until deve ser uma data futura")
+				return
+			}
+		} else {
+			req.Until = nil
+		}
+
+		_, userID := requestOwner(r)
+		change, err := task.Transition(to, req.Until, userID, strings.TrimSpace(req.Note))
+		if err == nil {
+			err = s.taskRepo.Transition(r.Context(), task, change)
+		}
+		if errors.Is(err, entities.ErrInvalidTransition) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if err != nil {
+			log.Printf("Erro ao alterar status da tarefa: %v", err)
+			respondError(w, http.StatusInternalServerError, "Erro ao alterar status da tarefa")
+			return
+		}
+
+		respondJSON(w, http.StatusOK, task)
+	}
+}
+
+// handleTaskHistory lista as mudanças de status da tarefa
+func (s *Server) handleTaskHistory(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.loadTask(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := s.taskRepo.History(r.Context(), task.ID)
+	if err != nil {
+		log.Printf("Erro ao listar histórico da tarefa: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao listar histórico da tarefa")
+		return
+	}
+	if history == nil {
+		history = []*entities.TaskStatusChange{}
+	}
+
+	respondJSON(w, http.StatusOK, history)
+}
+
+// loadTask busca a tarefa da URL; o repositório só encontra tarefas do tenant autenticado
+func (s *Server) loadTask(w http.ResponseWriter, r *http.Request) (*entities.Task, bool) {
+	task, err := s.taskRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusNotFound, "tarefa não encontrada")
+		return nil, false
+	}
+	return task, true
+}
+
+// validAssignee confirma que o responsável, se informado, é usuário do tenant
+func (s *Server) validAssignee(r *http.Request, assigneeID string) bool {
+	if assigneeID == "" {
+		return true
+	}
+	user, err := s.userRepo.GetByID(r.Context(), assigneeID)
+	tenantID, _ := requestOwner(r)
+	return err == nil && user.TenantID == tenantID
+}
+
+// validateTask retorna a mensagem de erro dos campos editáveis inválidos
+func validateTask(task *entities.Task) string {
+	switch {
+	case task.Description == "":
+		return "description é obrigatório"
+	case task.DueDate.IsZero():
+		return "due_date é obrigatório"
+	case task.Priority != entities.PriorityHigh && task.Priority != entities.PriorityMedium && task.Priority != entities.PriorityLow:
+		return "prioridade inválida: " + string(task.Priority)
+	}
+	return ""
+}
+
+// maxFeedTasks limite de tarefas incluídas no feed de calendário
+const maxFeedTasks = 500
+
+// handleCalendarFeedURL retorna o endereço do feed iCalendar do usuário, para
+// assinatura em aplicativos de calendário
+func (s *Server) handleCalendarFeedURL(w http.ResponseWriter, r *http.Request) {
+	_, userID := requestOwner(r)
+	user, err := s.userRepo.GetByID(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, "usuário não encontrado")
+		return
+	}
+	respondJSON(w, http.StatusOK, calendarFeedURLs(r, user.TenantID, user.ID, user.FeedTokenVersion))
+}
+
+// handleRotateCalendarFeed revoga os links do feed do usuário e retorna os novos
+func (s *Server) handleRotateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	tenantID, userID := requestOwner(r)
+	version, err := s.userRepo.RotateFeedToken(r.Context(), userID)
+	if err != nil {
+		log.Printf("Erro ao revogar feed de calendário: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao revogar feed")
+		return
+	}
+	respondJSON(w, http.StatusOK, calendarFeedURLs(r, tenantID, userID, version))
+}
+
+// calendarFeedURLs monta os endereços http(s) e webcal do feed
+func calendarFeedURLs(r *http.Request, tenantID, userID string, version int) map[string]string {
+	path := fmt.Sprintf("/api/v1/calendar/%s/%s/tasks.ics?token=%s",
+		url.PathEscape(tenantID), url.PathEscape(userID), auth.FeedToken(tenantID, userID, version))
+
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return map[string]string{
+		"url":        scheme + "://" + r.Host + path,
+		"webcal_url": "webcal://" + r.Host + path,
+	}
+}
+
+// handleCalendarFeed gera o feed iCalendar com as tarefas em aberto do usuário
+func (s *Server) handleCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenantID, userID := vars["tenant_id"], vars["user_id"]
+
+	// Usuário inexistente e token inválido respondem igual, sem revelar contas
+	ctx := database.SetTenantContext(r.Context(), tenantID)
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user.TenantID != tenantID || !user.Active ||
+		!auth.VerifyFeedToken(tenantID, userID, user.FeedTokenVersion, r.URL.Query().Get("token")) {
+		respondError(w, http.StatusForbidden, "token do feed inválido")
+		return
+	}
+
+	var tasks []*entities.Task
+	filter := &entities.TaskFilter{Page: entities.Page{PageSize: entities.MaxPageSize}}
+	for len(tasks) < maxFeedTasks {
+		page, err := s.taskRepo.ListPendingTasks(ctx, userID, filter)
+		if err != nil {
+			log.Printf("Erro ao listar tarefas do feed: %v", err)
+			respondError(w, http.StatusInternalServerError, "Erro ao gerar feed")
+			return
+		}
+		tasks = append(tasks, page.Items...)
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Page.After = page.NextCursor
+	}
+	if len(tasks) > maxFeedTasks {
+		tasks = tasks[:maxFeedTasks]
+	}
+
+	emails, err := s.feedEmails(ctx, tenantID, tasks)
+	if err != nil {
+		log.Printf("Erro ao buscar emails do feed: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao gerar feed")
+		return
+	}
+
+	appURL := strings.TrimSuffix(os.Getenv("APP_URL"), "/")
+	items := make([]calendar.Item, 0, len(tasks))
+	for _, task := range tasks {
+		var emailURL string
+		if appURL != "" {
+			emailURL = appURL + "/dashboard?email=" + url.QueryEscape(task.EmailID)
+		}
+		items = append(items, calendar.TaskItem(task, emails[task.EmailID], emailURL))
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="tasks.ics"`)
+	if err := calendar.Write(w, "Tarefas - "+user.Name, items, time.Now()); err != nil {
+		log.Printf("Erro ao gravar feed de calendário: %v", err)
+	}
+}
+
+// feedEmails carrega os emails de origem das tarefas em lotes de uma página
+func (s *Server) feedEmails(ctx context.Context, tenantID string, tasks []*entities.Task) (map[string]*entities.Email, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, task := range tasks {
+		if !seen[task.EmailID] {
+			seen[task.EmailID] = true
+			ids = append(ids, task.EmailID)
+		}
+	}
+
+	emails := make(map[string]*entities.Email, len(ids))
+	for len(ids) > 0 {
+		batch := ids[:min(len(ids), entities.MaxPageSize)]
+		ids = ids[len(batch):]
+
+		page, err := s.emailRepo.ListByTenant(ctx, tenantID, &entities.EmailFilter{
+			IDs:  batch,
+			Page: entities.Page{PageSize: entities.MaxPageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, email := range page.Items {
+			emails[email.ID] = email
+		}
+	}
+	return emails, nil
+}
+
+// requireAdmin responde 403 quando o usuário autenticado não é administrador do tenant
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if role, _ := r.Context().Value(middleware.RoleKey).(string); role != "admin" {
+		respondError(w, http.StatusForbidden, "operação restrita a administradores do tenant")
+		return false
+	}
+	return true
+}
+
+// handleListWebhooks lista os webhooks do tenant, sem os segredos
+func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	tenantID, _ := requestOwner(r)
+	webhooks, err := s.webhookRepo.ListByTenant(r.Context(), tenantID)
+	if err != nil {
+		log.Printf("Erro ao listar webhooks: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao listar webhooks")
+		return
+	}
+	if webhooks == nil {
+		webhooks = []*entities.Webhook{}
+	}
+	for _, wh := range webhooks {
+		wh.Secret = ""
+	}
+
+	respondJSON(w, http.StatusOK, webhooks)
+}
+
+// handleCreateWebhook registra um webhook; a resposta inclui o segredo de
+// assinatura, que não é exibido novamente
+func (s *Server) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var req struct {
+		URL    string               `json:"url"`
+		Events []entities.EventType `json:"events"`
+		Active *bool                `json:"active"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenantID, _ := requestOwner(r)
+	webhook := &entities.Webhook{
+		TenantID: tenantID,
+		URL:      strings.TrimSpace(req.URL),
+		Events:   req.Events,
+		Active:   req.Active == nil || *req.Active,
+	}
+	if msg := validateWebhook(r.Context(), webhook); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	var err error
+	if webhook.Secret, err = webhookpkg.NewSecret(); err == nil {
+		err = s.webhookRepo.Create(r.Context(), webhook)
+	}
+	if err != nil {
+		log.Printf("Erro ao criar webhook: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao criar webhook")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, webhook)
+}
+
+func (s *Server) handleGetWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := s.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+	respondJSON(w, http.StatusOK, webhook)
+}
+
+// handleUpdateWebhook altera URL, eventos e ativação; campos omitidos são mantidos
+func (s *Server) handleUpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := s.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		URL    *string               `json:"url"`
+		Events *[]entities.EventType `json:"events"`
+		Active *bool                 `json:"active"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.URL != nil {
+		webhook.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.Events != nil {
+		webhook.Events = *req.Events
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	if msg := validateWebhook(r.Context(), webhook); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if err := s.webhookRepo.Update(r.Context(), webhook); err != nil {
+		log.Printf("Erro ao atualizar webhook: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao atualizar webhook")
+		return
+	}
+	respondJSON(w, http.StatusOK, webhook)
+}
+
+// handleDeleteWebhook remove o webhook e o seu registro de entregas
+func (s *Server) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := s.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.webhookRepo.Delete(r.Context(), webhook.ID); err != nil {
+		log.Printf("Erro ao remover webhook: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao remover webhook")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListWebhookDeliveries lista as entregas do webhook, das mais recentes
+// para as mais antigas
+func (s *Server) handleListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := s.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := pageFromQuery(r.URL.Query())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deliveries, err := s.webhookRepo.ListDeliveries(r.Context(), webhook.ID, page)
+	if errors.Is(err, entities.ErrInvalidFilter) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao listar entregas do webhook: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao listar entregas do webhook")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, deliveries)
+}
+
+// handleRedeliverWebhook agenda o reenvio imediato de uma entrega
+func (s *Server) handleRedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := s.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	original, err := s.webhookRepo.GetDelivery(r.Context(), mux.Vars(r)["delivery_id"])
+	if err != nil || original.WebhookID != webhook.ID {
+		respondError(w, http.StatusNotFound, "entrega não encontrada")
+		return
+	}
+
+	delivery, err := s.webhooks.Redeliver(r.Context(), original)
+	if err != nil {
+		log.Printf("Erro ao reenviar entrega: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao reenviar entrega")
+		return
+	}
+	respondJSON(w, http.StatusAccepted, delivery)
+}
+
+// loadWebhook busca o webhook da URL, sem o segredo; o repositório só
+// encontra webhooks do tenant autenticado
+func (s *Server) loadWebhook(w http.ResponseWriter, r *http.Request) (*entities.Webhook, bool) {
+	if !requireAdmin(w, r) {
+		return nil, false
+	}
+	webhook, err := s.webhookRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusNotFound, "webhook não encontrado")
+		return nil, false
+	}
+	webhook.Secret = ""
+	return webhook, true
+}
+
+// validateWebhook retorna a mensagem de erro dos campos editáveis inválidos.
+// A URL não pode apontar para a rede interna.
+func validateWebhook(ctx context.Context, webhook *entities.Webhook) string {
+	if err := safehttp.CheckURL(ctx, webhook.URL); err != nil {
+		return err.Error()
+	}
+	if len(webhook.Events) == 0 {
+		return "events é obrigatório"
+	}
+	for _, event := range webhook.Events {
+		if !event.Valid() {
+			return "evento desconhecido: " + string(event)
+		}
+	}
+	return ""
+}
+
+// handleListIntegrations lista as integrações de notificações do tenant, sem as URLs
+func (s *Server) handleListIntegrations(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	tenantID, _ := requestOwner(r)
+	integrations, err := s.integrationRepo.ListByTenant(r.Context(), tenantID)
+	if err != nil {
+		log.Printf("Erro ao listar integrações: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao listar integrações")
+		return
+	}
+	if integrations == nil {
+		integrations = []*entities.Integration{}
+	}
+	for _, i := range integrations {
+		hideIntegrationURL(i)
+	}
+
+	respondJSON(w, http.StatusOK, integrations)
+}
+
+// integrationRequest corpo de criação e alteração de integrações; campos
+// omitidos são mantidos na alteração
+type integrationRequest struct {
+	Kind       entities.IntegrationKind `json:"kind"`
+	Name       *string                  `json:"name"`
+	WebhookURL *string                  `json:"webhook_url"`
+	Priorities *[]entities.Priority     `json:"priorities"`
+	Categories *[]string                `json:"categories"`
+	Active     *bool                    `json:"active"`
+}
+
+// apply copia os campos informados para a integração
+func (req *integrationRequest) apply(integration *entities.Integration) {
+	if req.Name != nil {
+		integration.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.WebhookURL != nil {
+		integration.WebhookURL = strings.TrimSpace(*req.WebhookURL)
+	}
+	if req.Priorities != nil {
+		integration.Criteria.Priorities = *req.Priorities
+	}
+	if req.Categories != nil {
+		integration.Criteria.Categories = []string{}
+		for _, c := range *req.Categories {
+			integration.Criteria.Categories = append(integration.Criteria.Categories, strings.ToLower(strings.TrimSpace(c)))
+		}
+	}
+	if req.Active != nil {
+		integration.Active = *req.Active
+	}
+}
+
+// handleCreateIntegration cadastra uma integração com o incoming webhook do
+// Slack ou do Teams; a URL só é exibida nesta resposta
+func (s *Server) handleCreateIntegration(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var req integrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !req.Kind.Valid() {
+		respondError(w, http.StatusBadRequest, "kind deve ser slack ou teams")
+		return
+	}
+
+	tenantID, _ := requestOwner(r)
+	integration := &entities.Integration{
+		TenantID: tenantID,
+		Kind:     req.Kind,
+		Criteria: entities.IntegrationCriteria{Priorities: []entities.Priority{}, Categories: []string{}},
+		Active:   true,
+	}
+	req.apply(integration)
+	if msg := validateIntegration(r.Context(), integration); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if err := s.integrationRepo.Create(r.Context(), integration); err != nil {
+		log.Printf("Erro ao criar integração: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao criar integração")
+		return
+	}
+	respondJSON(w, http.StatusCreated, integration)
+}
+
+func (s *Server) handleGetIntegration(w http.ResponseWriter, r *http.Request) {
+	integration, ok := s.loadIntegration(w, r)
+	if !ok {
+		return
+	}
+	hideIntegrationURL(integration)
+	respondJSON(w, http.StatusOK, integration)
+}
+
+// handleUpdateIntegration altera nome, URL, critérios e ativação; o tipo não muda
+func (s *Server) handleUpdateIntegration(w http.ResponseWriter, r *http.Request) {
+	integration, ok := s.loadIntegration(w, r)
+	if !ok {
+		return
+	}
+
+	var req integrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Kind != "" && req.Kind != integration.Kind {
+		respondError(w, http.StatusBadRequest, "o tipo da integração não pode ser alterado")
+		return
+	}
+	req.apply(integration)
+	if msg := validateIntegration(r.Context(), integration); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if err := s.integrationRepo.Update(r.Context(), integration); err != nil {
+		log.Printf("Erro ao atualizar integração: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao atualizar integração")
+		return
+	}
+	hideIntegrationURL(integration)
+	respondJSON(w, http.StatusOK, integration)
+}
+
+func (s *Server) handleDeleteIntegration(w http.ResponseWriter, r *http.Request) {
+	integration, ok := s.loadIntegration(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.integrationRepo.Delete(r.Context(), integration.ID); err != nil {
+		log.Printf("Erro ao remover integração: %v", err)
+		respondError(w, http.StatusInternalServerError, "Erro ao remover integração")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTestIntegration envia uma mensagem de exemplo ao canal da integração,
+// mesmo que ela esteja desativada
+func (s *Server) handleTestIntegration(w http.ResponseWriter, r *http.Request) {
+	integration, ok := s.loadIntegration(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.integrations.Test(r.Context(), integration); err != nil {
+		respondError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "sent"})
+}
+
+// loadIntegration busca a integração da URL; o repositório só encontra
+// integrações do tenant autenticado
+func (s *Server) loadIntegration(w http.ResponseWriter, r *http.Request) (*entities.Integration, bool) {
+	if !requireAdmin(w, r) {
+		return nil, false
+	}
+	integration, err := s.integrationRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusNotFound, "integração não encontrada")
+		return nil, false
+	}
+	return integration, true
+}
+
+// hideIntegrationURL remove a URL do incoming webhook, que dá acesso ao canal,
+// e normaliza os critérios vazios para listas
+func hideIntegrationURL(integration *entities.Integration) {
+	integration.WebhookURL = ""
+	if integration.Criteria.Priorities == nil {
+		integration.Criteria.Priorities = []entities.Priority{}
+	}
+	if integration.Criteria.Categories == nil {
+		integration.Criteria.Categories = []string{}
+	}
+}
+
+// validateIntegration retorna a mensagem de erro dos campos inválidos da
+// integração. A webhook_url não pode apontar para a rede interna.
+func validateIntegration(ctx context.Context, integration *entities.Integration) string {
+	u, err := url.Parse(integration.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "webhook_url deve ser um endereço http ou https"
+	}
+	if err := safehttp.CheckURL(ctx, integration.WebhookURL); err != nil {
+		return "webhook_url: " + err.Error()
+	}
+	if integration.Criteria.Empty() {
+		return "informe ao menos um critério em priorities ou categories"
+	}
+	for _, p := range integration.Criteria.Priorities {
+		if p != entities.PriorityHigh && p != entities.PriorityMedium && p != entities.PriorityLow {
+			return "prioridade inválida: " + string(p)
+		}
+	}
+	for _, c := range integration.Criteria.Categories {
+		if c == "" {
+			return "categories não pode conter valores vazios"
+		}
+	}
+	return ""
+}
+
+// requestOwner retorna o tenant e o usuário autenticados na requisição
+func requestOwner(r *http.Request) (tenantID, userID string) {
+	tenantID, _ = r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ = r.Context().Value(middleware.UserIDKey).(string)
+	return tenantID, userID
+}
+
+// handleInboundWebhook recebe emails entregues por provedores via webhook.
+// Erros 5xx fazem o provedor reenviar; 406 indica rejeição definitiva.
+func (s *Server) handleInboundWebhook(w http.ResponseWriter, r *http.Request) {
+	provider, ok := s.inboundProvider[mux.Vars(r)["provider"]]
+	if !ok {
+		respondError(w, http.StatusNotFound, "Provedor de inbound não configurado")
+		return
+	}
+
+	// A assinatura é calculada sobre o corpo original
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRawUploadBytes))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "Mensagem excede o tamanho máximo permitido")
+		return
+	}
+
+	if err := provider.Verify(r.Header, body); err != nil {
+		respondError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	msg, err := provider.Parse(r.Header, body)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	email := msg.Email
+	if msg.Raw != nil {
+		if email, err = services.ParseMessage(bytes.NewReader(msg.Raw)); err != nil {
+			respondError(w, http.StatusBadRequest, "Mensagem malformada")
+			return
+		}
+	}
+
+	delivered, err := s.inbound.Deliver(r.Context(), email, msg.Recipients)
+	if errors.Is(err, services.ErrUnknownRecipient) {
+		respondError(w, http.StatusNotAcceptable, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao processar webhook de inbound (%s): %v", provider.Name(), err)
+		respondError(w, http.StatusInternalServerError, "Erro ao processar email")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int{"delivered": delivered})
+}
+
+// startInboundReceivers inicia os receptores SMTP/LMTP configurados via ambiente
+func (s *Server) startInboundReceivers() {
+	receivers := []struct {
+		addr string
+		lmtp bool
+	}{
+		{os.Getenv("SMTP_INBOUND_ADDR"), false},
+		{os.Getenv("LMTP_INBOUND_ADDR"), true},
+	}
+
+	for _, rc := range receivers {
+		if rc.addr == "" {
+			continue
+		}
+
+		receiver := services.NewSMTPReceiver(&services.SMTPReceiverConfig{
+			Addr: rc.addr,
+			LMTP: rc.lmtp,
+		}, s.inbound)
+
+		go func(addr string, lmtp bool) {
+			log.Printf("Receptor de emails (lmtp=%v) escutando em %s", lmtp, addr)
+			if err := receiver.ListenAndServe(); err != nil {
+				log.Printf("Erro no receptor de emails em %s: %v", addr, err)
+			}
+		}(rc.addr, rc.lmtp)
+	}
+}
+
+// startReminderScheduler inicia o agendador de lembretes de tarefas, com os
+// canais habilitados conforme o ambiente. Sem canais, apenas marca as tarefas
+// atrasadas, o que também grava task.overdue no outbox.
+func (s *Server) startReminderScheduler() {
+	var notifiers []notify.Notifier
+	if addr := os.Getenv("REMINDER_SMTP_ADDR"); addr != "" {
+		notifiers = append(notifiers, notify.NewSMTPNotifier(notify.SMTPConfig{
+			Addr:     addr,
+			From:     os.Getenv("REMINDER_SMTP_FROM"),
+			Username: os.Getenv("REMINDER_SMTP_USERNAME"),
+			Password: os.Getenv("REMINDER_SMTP_PASSWORD"),
+		}))
+	}
+	if url := os.Getenv("REMINDER_WEBHOOK_URL"); url != "" {
+		notifiers = append(notifiers, notify.NewWebhookNotifier(url))
+	}
+
+	config := &services.ReminderConfig{}
+	for _, d := range []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"REMINDER_INTERVAL", &config.Interval},
+		{"REMINDER_LEAD", &config.Lead},
+	} {
+		if v := os.Getenv(d.env); v != "" {
+			var err error
+			if *d.dst, err = time.ParseDuration(v); err != nil {
+				log.Printf("Valor inválido em %s, usando o padrão: %v", d.env, err)
+			}
+		}
+	}
+
+	scheduler := services.NewReminderScheduler(s.storage.Reminders, notifiers, services.SystemClock{}, config)
+	go scheduler.Run(context.Background())
+}
+
+// startBackfillRunner inicia o executor dos jobs de backfill da caixa IMAP
+// configurada via EMAIL_*. A conta pertence ao usuário EMAIL_USER_ID do tenant
+// EMAIL_TENANT_ID; sem ela, a API recusa a criação de jobs.
+func (s *Server) startBackfillRunner() {
+	config := mailboxConfig()
+	if config == nil {
+		return
+	}
+
+	s.backfillOwner = config.TenantID + "/" + config.UserID
+	go services.NewBackfillRunner(config, s.emailClassifier, s.emailRepo, s.backfillRepo, nil).Run(context.Background())
+}
+
+// mailboxConfig lê a caixa IMAP do ambiente; retorna nil se não configurada
+func mailboxConfig() *services.EmailConfig {
+	server := os.Getenv("EMAIL_SERVER")
+	if server == "" {
+		return nil
+	}
+
+	config := &services.EmailConfig{
+		Server:   server,
+		Port:     993,
+		Username: os.Getenv("EMAIL_USERNAME"),
+		Password: os.Getenv("EMAIL_PASSWORD"),
+		Folder:   os.Getenv("EMAIL_FOLDER"),
+		SSL:      os.Getenv("EMAIL_SSL") != "false",
+		TenantID: os.Getenv("EMAIL_TENANT_ID"),
+		UserID:   os.Getenv("EMAIL_USER_ID"),
+	}
+	if config.TenantID == "" || config.UserID == "" {
+		log.Printf("AVISO: EMAIL_SERVER configurado sem EMAIL_TENANT_ID e EMAIL_USER_ID; caixa IMAP ignorada")
+		return nil
+	}
+	if v := os.Getenv("EMAIL_PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			log.Printf("Valor inválido em EMAIL_PORT, usando o padrão: %v", err)
+		} else {
+			config.Port = port
+		}
+	}
+	return config
+}
+
+// webhookConfig lê a configuração da entrega de webhooks do ambiente; valores
+// ausentes ou inválidos usam o padrão
+func webhookConfig() *services.WebhookConfig {
+	config := &services.WebhookConfig{}
+	for _, d := range []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"WEBHOOK_INTERVAL", &config.Interval},
+		{"WEBHOOK_RETRY_BASE", &config.RetryBase},
+		{"WEBHOOK_RETRY_MAX", &config.RetryMax},
+		{"WEBHOOK_TIMEOUT", &config.Timeout},
+	} {
+		if v := os.Getenv(d.env); v != "" {
+			var err error
+			if *d.dst, err = time.ParseDuration(v); err != nil {
+				log.Printf("Valor inválido em %s, usando o padrão: %v", d.env, err)
+			}
+		}
+	}
+	if v := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); v != "" {
+		var err error
+		if config.MaxAttempts, err = strconv.Atoi(v); err != nil {
+			log.Printf("Valor inválido em WEBHOOK_MAX_ATTEMPTS, usando o padrão: %v", err)
+		}
+	}
+	return config
+}
+
+// outboxConfig lê a configuração do dispatcher do outbox do ambiente; valores
+// ausentes ou inválidos usam o padrão
+func outboxConfig() *services.OutboxConfig {
+	config := &services.OutboxConfig{}
+	if v := os.Getenv("OUTBOX_INTERVAL"); v != "" {
+		var err error
+		if config.Interval, err = time.ParseDuration(v); err != nil {
+			log.Printf("Valor inválido em OUTBOX_INTERVAL, usando o padrão: %v", err)
+		}
+	}
+	if v := os.Getenv("OUTBOX_MAX_ATTEMPTS"); v != "" {
+		var err error
+		if config.MaxAttempts, err = strconv.Atoi(v); err != nil {
+			log.Printf("Valor inválido em OUTBOX_MAX_ATTEMPTS, usando o padrão: %v", err)
+		}
+	}
+	return config
+}
+
+// tenantScope restringe as operações de banco da requisição ao tenant do token
+func tenantScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID, _ := requestOwner(r)
+		ctx := database.SetTenantContext(r.Context(), tenantID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func respondJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func respondError(w http.ResponseWriter, status int, message string) {
+	respondJSON(w, status, map[string]string{"error": message})
+}
+
 func main() {
 	log.Printf("Iniciando serviço de classificação de emails...")
 
@@ -121,8 +1674,14 @@ func main() {
 	if err != nil {
 		log.Fatalf("Erro ao criar servidor: %v", err)
 	}
+	defer server.storage.Close()
 
 	server.setupRoutes()
+	server.startInboundReceivers()
+	server.startReminderScheduler()
+	server.startBackfillRunner()
+	go server.outbox.Run(context.Background())
+	go server.webhooks.Run(context.Background())
 
 	port := os.Getenv("PORT")
 	if port == "" {
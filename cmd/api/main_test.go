@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/application/services"
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/memory"
+	"github.com/enzo010/email-filter/internal/infrastructure/middleware"
+	"github.com/gorilla/mux"
+)
+
+// testServer servidor com armazenamento em memória e um usuário autenticado
+type testServer struct {
+	*Server
+	tenantID string
+	userID   string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	ctx := context.Background()
+	store := memory.NewStore()
+	tenants, users := memory.NewTenantRepository(store), memory.NewUserRepository(store)
+
+	tenant := &entities.Tenant{Name: "Acme", Plan: "free", Active: true}
+	if err := tenants.Create(ctx, tenant); err != nil {
+		t.Fatal(err)
+	}
+	user := &entities.User{TenantID: tenant.ID, Email: "ana@acme.test", Name: "Ana", Role: "admin", Active: true}
+	if err := users.Create(entities.WithTenant(ctx, tenant.ID), user); err != nil {
+		t.Fatal(err)
+	}
+
+	return &testServer{
+		Server: &Server{
+			emailClassifier: services.NewEmailClassifier(),
+			emailRepo:       memory.NewEmailRepository(store),
+			taskRepo:        memory.NewTaskRepository(store),
+			tenantRepo:      tenants,
+			userRepo:        users,
+		},
+		tenantID: tenant.ID,
+		userID:   user.ID,
+	}
+}
+
+// do executa o handler com o usuário do teste autenticado
+func (ts *testServer) do(handler http.HandlerFunc, r *http.Request) *httptest.ResponseRecorder {
+	ctx := context.WithValue(r.Context(), middleware.UserIDKey, ts.userID)
+	ctx = context.WithValue(ctx, middleware.TenantIDKey, ts.tenantID)
+	w := httptest.NewRecorder()
+	tenantScope(handler).ServeHTTP(w, r.WithContext(ctx))
+	return w
+}
+
+// listEmails retorna os emails salvos do tenant
+func (ts *testServer) listEmails(t *testing.T) []*entities.Email {
+	t.Helper()
+	result, err := ts.emailRepo.ListByTenant(entities.WithTenant(context.Background(), ts.tenantID), ts.tenantID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.Items
+}
+
+func rawMessage(id, subject string) string {
+	return "Message-ID: <" + id + "@example.com>\r\n" +
+		"From: cliente@example.com\r\n" +
+		"To: ana@acme.test\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" + subject + "\r\n"
+}
+
+// multipartUpload monta um envio multipart/form-data com os arquivos .eml
+func multipartUpload(t *testing.T, files map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, content := range files {
+		part, err := mw.CreateFormFile("file", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(content))
+	}
+	mw.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/classify/raw?store=true", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+// failingEmailRepo repositório que recusa gravações em lote
+type failingEmailRepo struct {
+	entities.EmailRepository
+}
+
+func (failingEmailRepo) CreateAll(ctx context.Context, emails []*entities.Email) ([]bool, error) {
+	return nil, errors.New("banco indisponível")
+}
+
+func TestClassifyRawSingleMessage(t *testing.T) {
+	ts := newTestServer(t)
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/classify/raw", strings.NewReader(rawMessage("m1", "Fatura vencida")))
+	r.Header.Set("Content-Type", "message/rfc822")
+
+	w := ts.do(ts.handleClassifyRaw, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var result entities.ClassificationResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil || result.Priority == "" {
+		t.Errorf("resposta = %s, %v", w.Body, err)
+	}
+	if emails := ts.listEmails(t); len(emails) != 0 {
+		t.Errorf("salvos %d emails sem store=true", len(emails))
+	}
+}
+
+func TestClassifyRawStoresAllParts(t *testing.T) {
+	ts := newTestServer(t)
+	files := map[string]string{
+		"fatura.eml":  rawMessage("m1", "Fatura vencida"),
+		"reuniao.eml": rawMessage("m2", "Reunião amanhã"),
+	}
+
+	w := ts.do(ts.handleClassifyRaw, multipartUpload(t, files))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var results []entities.ClassificationResult
+	if err := json.NewDecoder(w.Body).Decode(&results); err != nil || len(results) != 2 {
+		t.Fatalf("resposta = %s, %v", w.Body, err)
+	}
+	emails := ts.listEmails(t)
+	if len(emails) != 2 {
+		t.Fatalf("salvos %d emails, esperado 2", len(emails))
+	}
+	for _, e := range emails {
+		if e.Folder != "upload" || e.UserID != ts.userID {
+			t.Errorf("email %s na pasta %q do usuário %q", e.MessageID, e.Folder, e.UserID)
+		}
+	}
+
+	// Repetir o envio não duplica as mensagens
+	if w := ts.do(ts.handleClassifyRaw, multipartUpload(t, files)); w.Code != http.StatusOK {
+		t.Fatalf("reenvio: status %d: %s", w.Code, w.Body)
+	}
+	if emails := ts.listEmails(t); len(emails) != 2 {
+		t.Errorf("salvos %d emails após o reenvio, esperado 2", len(emails))
+	}
+}
+
+func TestClassifyRawStoreFailureSavesNothing(t *testing.T) {
+	ts := newTestServer(t)
+	repo := ts.emailRepo
+	ts.emailRepo = failingEmailRepo{repo}
+
+	files := map[string]string{
+		"fatura.eml":  rawMessage("m1", "Fatura vencida"),
+		"reuniao.eml": rawMessage("m2", "Reunião amanhã"),
+	}
+	if w := ts.do(ts.handleClassifyRaw, multipartUpload(t, files)); w.Code != http.StatusInternalServerError {
+		t.Fatalf("status %d, esperado 500", w.Code)
+	}
+	ts.emailRepo = repo
+	if emails := ts.listEmails(t); len(emails) != 0 {
+		t.Errorf("salvos %d emails após a falha", len(emails))
+	}
+}
+
+func TestClassifyRawRejectsUnsupportedContentType(t *testing.T) {
+	ts := newTestServer(t)
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/classify/raw", strings.NewReader("{}"))
+	r.Header.Set("Content-Type", "application/json")
+	if w := ts.do(ts.handleClassifyRaw, r); w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("status %d, esperado 415", w.Code)
+	}
+}
+
+// feedURL obtém o link do feed do usuário do teste
+func (ts *testServer) feedURL(t *testing.T, handler http.HandlerFunc, method string) string {
+	t.Helper()
+	w := ts.do(handler, httptest.NewRequest(method, "/api/v1/calendar/feed", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var urls map[string]string
+	if err := json.NewDecoder(w.Body).Decode(&urls); err != nil {
+		t.Fatal(err)
+	}
+	return urls["url"]
+}
+
+// getFeed requisita o feed, sem autenticação, pelo link informado
+func (ts *testServer) getFeed(link string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, link, nil)
+	r = mux.SetURLVars(r, map[string]string{"tenant_id": ts.tenantID, "user_id": ts.userID})
+	w := httptest.NewRecorder()
+	ts.handleCalendarFeed(w, r)
+	return w
+}
+
+func TestCalendarFeedListsOpenTasks(t *testing.T) {
+	ts := newTestServer(t)
+	due := time.Now().Add(24 * time.Hour)
+	email := &entities.Email{
+		TenantID: ts.tenantID, UserID: ts.userID, Subject: "Contrato", From: "cliente@example.com",
+		Priority: entities.PriorityHigh, Category: "work", ProcessedAt: time.Now(),
+		Tasks: []entities.Task{
+			{Description: "Revisar contrato", DueDate: due, Priority: entities.PriorityHigh, Status: entities.TaskPending},
+			{Description: "Assinar contrato", DueDate: due, Priority: entities.PriorityHigh, Status: entities.TaskCompleted},
+		},
+	}
+	if _, err := ts.emailRepo.CreateAll(entities.WithTenant(context.Background(), ts.tenantID), []*entities.Email{email}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := ts.getFeed(ts.feedURL(t, ts.handleCalendarFeedURL, http.MethodGet))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "SUMMARY:Revisar contrato") || strings.Contains(body, "Assinar contrato") {
+		t.Errorf("feed sem apenas a tarefa em aberto:\n%s", body)
+	}
+	if !strings.Contains(body, `DESCRIPTION:Email: Contrato\nDe: cliente@example.com`) {
+		t.Errorf("feed sem o email de origem:\n%s", body)
+	}
+}
+
+func TestCalendarFeedRotateRevokesLinks(t *testing.T) {
+	ts := newTestServer(t)
+	old := ts.feedURL(t, ts.handleCalendarFeedURL, http.MethodGet)
+	if w := ts.getFeed(old); w.Code != http.StatusOK {
+		t.Fatalf("status %d antes de revogar", w.Code)
+	}
+
+	rotated := ts.feedURL(t, ts.handleRotateCalendarFeed, http.MethodPost)
+	if rotated == old {
+		t.Fatal("rotate retornou o mesmo link")
+	}
+	if w := ts.getFeed(old); w.Code != http.StatusForbidden {
+		t.Errorf("link revogado: status %d, esperado 403", w.Code)
+	}
+	if w := ts.getFeed(rotated); w.Code != http.StatusOK {
+		t.Errorf("link novo: status %d, esperado 200", w.Code)
+	}
+	if current := ts.feedURL(t, ts.handleCalendarFeedURL, http.MethodGet); current != rotated {
+		t.Errorf("GET /calendar/feed = %s, esperado o link novo", current)
+	}
+}
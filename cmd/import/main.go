@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/enzo010/email-filter/internal/application/services"
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/mailbox"
+	"github.com/enzo010/email-filter/internal/infrastructure/storage"
+	"github.com/joho/godotenv"
+)
+
+// importState progresso persistido entre execuções, permitindo retomar a importação
+type importState struct {
+	Source     string          `json:"source"`
+	NextIndex  int             `json:"next_index"`
+	MessageIDs map[string]bool `json:"message_ids"`
+}
+
+// importSummary relatório final da importação
+type importSummary struct {
+	Scanned    int `json:"scanned"`
+	Imported   int `json:"imported"`
+	Duplicates int `json:"duplicates"`
+	Skipped    int `json:"skipped"` // Já importadas em execuções anteriores
+	Failed     int `json:"failed"`
+}
+
+func main() {
+	tenantID := flag.String("tenant", "", "ID do tenant de destino (obrigatório)")
+	userID := flag.String("user", "", "ID do usuário de destino (obrigatório)")
+	mboxPath := flag.String("mbox", "", "arquivo mbox a importar")
+	maildirPath := flag.String("maildir", "", "diretório Maildir a importar")
+	folder := flag.String("folder", "import", "pasta registrada nos emails importados")
+	statePath := flag.String("state", "", "arquivo de progresso (padrão: <origem>.import-state.json)")
+	events := flag.Bool("events", false, "gravar eventos das mensagens importadas para webhooks e integrações")
+	flag.Parse()
+
+	if *tenantID == "" || *userID == "" || (*mboxPath == "") == (*maildirPath == "") {
+		fmt.Fprintln(os.Stderr, "uso: import -tenant ID -user ID (-mbox ARQUIVO | -maildir DIRETÓRIO)")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Arquivo .env não encontrado: %v", err)
+	}
+
+	source := *mboxPath
+	var reader mailbox.Reader
+	var err error
+	if *mboxPath != "" {
+		reader, err = mailbox.OpenMbox(*mboxPath)
+	} else {
+		source = *maildirPath
+		reader, err = mailbox.OpenMaildir(*maildirPath)
+	}
+	if err != nil {
+		log.Fatalf("Erro ao abrir %s: %v", source, err)
+	}
+	defer reader.Close()
+
+	if *statePath == "" {
+		*statePath = source + ".import-state.json"
+	}
+	state, err := loadState(*statePath, source)
+	if err != nil {
+		log.Fatalf("Erro ao ler progresso: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx = entities.WithTenant(ctx, *tenantID)
+	if !*events {
+		// Mensagens antigas não devem disparar notificações
+		ctx = entities.WithoutEvents(ctx)
+	}
+
+	// Mesmo driver da API (STORAGE_DRIVER), para importar no banco em uso
+	store, err := storage.Open(ctx, os.Getenv("STORAGE_DRIVER"))
+	if err != nil {
+		log.Fatalf("Erro ao abrir armazenamento: %v", err)
+	}
+	defer store.Close()
+
+	classifier := services.NewEmailClassifier()
+	emailRepo := store.Emails
+
+	// O progresso só avança enquanto as mensagens anteriores foram importadas
+	// (ou já existiam): a próxima execução recomeça na primeira que falhou, e
+	// as seguintes já importadas são contadas como duplicadas
+	var summary importSummary
+	failed := false
+	for ctx.Err() == nil {
+		index, raw, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		summary.Scanned++
+		if err != nil {
+			log.Printf("Erro ao ler mensagem %d: %v", index, err)
+			summary.Failed++
+			failed = true
+			continue
+		}
+		if index < state.NextIndex {
+			summary.Skipped++
+			continue
+		}
+
+		// Sem Message-ID, o hash do conteúdo identifica a mensagem
+		email, err := services.ParseMessage(bytes.NewReader(raw))
+		if err == nil {
+			key := email.MessageID
+			if key == "" {
+				sum := sha256.Sum256(raw)
+				key = "sha256:" + hex.EncodeToString(sum[:])
+			}
+
+			if state.MessageIDs[key] {
+				summary.Duplicates++
+			} else {
+				email.TenantID = *tenantID
+				email.UserID = *userID
+				email.Folder = *folder
+
+				var result *services.ClassificationResult
+				var created bool
+				if result, err = classifier.ClassifyEmail(ctx, email); err == nil {
+					services.ApplyClassification(email, result)
+					created, err = emailRepo.Create(ctx, email)
+				}
+				if err == nil {
+					state.MessageIDs[key] = true
+					// Já armazenada por outra origem (IMAP, inbound, importação anterior)
+					if created {
+						summary.Imported++
+					} else {
+						summary.Duplicates++
+					}
+				}
+			}
+		}
+		if err != nil {
+			// Interrompida durante a mensagem: não é falha e será refeita
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("Erro ao importar mensagem %d: %v", index, err)
+			summary.Failed++
+			failed = true
+		}
+
+		if !failed {
+			state.NextIndex = index + 1
+		}
+		if summary.Scanned%100 == 0 {
+			if err := saveState(*statePath, state); err != nil {
+				log.Printf("Erro ao salvar progresso: %v", err)
+			}
+		}
+	}
+
+	if err := saveState(*statePath, state); err != nil {
+		log.Printf("Erro ao salvar progresso: %v", err)
+	}
+
+	report, _ := json.MarshalIndent(summary, "", "  ")
+	fmt.Println(string(report))
+	if ctx.Err() != nil {
+		log.Printf("Importação interrompida; execute novamente para continuar de onde parou")
+	}
+}
+
+// loadState lê o progresso anterior, iniciando um novo se o arquivo não existir
+func loadState(path, source string) (*importState, error) {
+	state := &importState{Source: source, MessageIDs: make(map[string]bool)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Source != source {
+		return nil, fmt.Errorf("arquivo de progresso pertence a outra origem: %s", state.Source)
+	}
+	if state.MessageIDs == nil {
+		state.MessageIDs = make(map[string]bool)
+	}
+	return state, nil
+}
+
+// saveState grava o progresso de forma atômica
+func saveState(path string, state *importState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/enzo010/email-filter/internal/infrastructure/database"
+	"github.com/joho/godotenv"
+)
+
+const usage = "uso: migrate up | down [N] | status"
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Arquivo .env não encontrado: %v", err)
+	}
+
+	ctx := context.Background()
+	db, err := database.NewDatabase(ctx, nil)
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco: %v", err)
+	}
+	defer db.Close()
+
+	switch os.Args[1] {
+	case "up":
+		applied, err := db.MigrateUp(ctx)
+		for _, m := range applied {
+			fmt.Printf("aplicada %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Erro ao migrar: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema já está atualizado")
+		}
+
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			if steps, err = strconv.Atoi(os.Args[2]); err != nil || steps <= 0 {
+				log.Fatalf("Número de migrações inválido: %s", os.Args[2])
+			}
+		}
+		reverted, err := db.MigrateDown(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("revertida %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Erro ao reverter: %v", err)
+		}
+
+	case "status":
+		status, err := db.MigrationStatus(ctx)
+		if err != nil {
+			log.Fatalf("Erro ao consultar migrações: %v", err)
+		}
+		for _, s := range status {
+			applied := "pendente"
+			if s.AppliedAt != nil {
+				applied = "aplicada em " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%03d_%-30s %s\n", s.Version, s.Name, applied)
+		}
+
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
@@ -1,3 +1,6 @@
+-- As políticas de row-level security valem também para o dono das tabelas
+SET app.bypass_rls = 'on';
+
 -- Insert tenant
 INSERT INTO tenants (id, name, plan, active)
 VALUES (
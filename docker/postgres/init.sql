@@ -0,0 +1,6 @@
+-- Usuário da aplicação: dono do banco e das tabelas, mas sem superusuário nem
+-- BYPASSRLS, para que as políticas de row-level security valham para o servidor
+CREATE ROLE email_filter LOGIN PASSWORD 'email_filter';
+ALTER DATABASE email_filter OWNER TO email_filter;
+ALTER SCHEMA public OWNER TO email_filter;
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
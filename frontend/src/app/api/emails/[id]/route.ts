@@ -1,6 +1,6 @@
 import { NextRequest, NextResponse } from 'next/server';
 import { authenticateRequest, validateTenant, AuthenticatedRequest } from '../../middleware';
-import { prisma } from '@/lib/prisma';
+import { prisma, withLabelNames } from '@/lib/prisma';
 
 export async function GET(
   request: AuthenticatedRequest,
@@ -24,11 +24,12 @@ export async function GET(
         tenant_id: tenantId,
       },
       include: {
+        labels: { select: { label: true } },
         tasks: {
           select: {
             id: true,
-            title: true,
             description: true,
+            due_date: true,
             priority: true,
             status: true,
             created_at: true,
@@ -44,7 +45,7 @@ export async function GET(
       );
     }
 
-    return NextResponse.json(email);
+    return NextResponse.json(withLabelNames(email));
   } catch (error) {
     console.error('Error fetching email:', error);
     return NextResponse.json(
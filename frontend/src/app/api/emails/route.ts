@@ -1,6 +1,6 @@
 import { NextRequest, NextResponse } from 'next/server';
 import { authenticateRequest, validateTenant, AuthenticatedRequest } from '../middleware';
-import { prisma } from '@/lib/prisma';
+import { prisma, withLabelNames } from '@/lib/prisma';
 import config from '@/lib/config';
 
 export async function GET(request: AuthenticatedRequest) {
@@ -32,11 +32,12 @@ export async function GET(request: AuthenticatedRequest) {
       where,
       orderBy: { created_at: 'desc' },
       include: {
+        labels: { select: { label: true } },
         tasks: {
           select: {
             id: true,
-            title: true,
             description: true,
+            due_date: true,
             priority: true,
             status: true,
             created_at: true,
@@ -45,7 +46,7 @@ export async function GET(request: AuthenticatedRequest) {
       },
     });
 
-    return NextResponse.json(emails);
+    return NextResponse.json(emails.map(withLabelNames));
   } catch (error) {
     console.error('Error fetching emails:', error);
     return NextResponse.json(
@@ -99,13 +100,21 @@ export async function POST(request: AuthenticatedRequest) {
               id: tenantId
             }
           },
+          user: {
+            connect: {
+              id: request.user!.userId
+            }
+          },
           subject: emailData.subject,
           body: emailData.body,
           sender: emailData.sender,
           recipient: emailData.recipient,
           priority: classification.priority,
           category: classification.category,
-          labels: classification.labels || [],
+          processed_at: new Date(),
+          labels: {
+            create: (classification.labels || []).map((label: string) => ({ label })),
+          },
         },
       });
 
@@ -117,10 +126,6 @@ export async function POST(request: AuthenticatedRequest) {
               email: {
                 connect: { id: email.id }
               },
-              tenant: {
-                connect: { id: tenantId }
-              },
-              title: task.title,
               description: task.description,
               priority: task.priority,
               status: 'pending',
@@ -133,11 +138,12 @@ export async function POST(request: AuthenticatedRequest) {
       return tx.email.findUnique({
         where: { id: email.id },
         include: {
+          labels: { select: { label: true } },
           tasks: {
             select: {
               id: true,
-              title: true,
               description: true,
+              due_date: true,
               priority: true,
               status: true,
               created_at: true,
@@ -147,7 +153,7 @@ export async function POST(request: AuthenticatedRequest) {
       });
     });
 
-    return NextResponse.json(email);
+    return NextResponse.json(email && withLabelNames(email));
   } catch (error) {
     console.error('Error processing email:', error);
     return NextResponse.json(
@@ -16,13 +16,16 @@ export async function GET(request: AuthenticatedRequest) {
     const tenantId = request.headers.get('X-Tenant-ID')!;
 
     const page = parseInt(searchParams.get('page') || '1');
-    const pageSize = Math.min(parseInt(searchParams.get('page_size') || '10'), 100);
+    const pageSize = Math.min(parseInt(searchParams.get('page_size') || '20'), 100);
     const skip = (page - 1) * pageSize;
 
+    // Tarefas pertencem ao tenant e ao usuário do email de origem
     const where = {
-      tenant_id: tenantId,
+      email: {
+        tenant_id: tenantId,
+        ...(searchParams.get('user_id') && { user_id: searchParams.get('user_id')! }),
+      },
       ...(searchParams.get('email_id') && { email_id: searchParams.get('email_id') }),
-      ...(searchParams.get('user_id') && { assigned_to: searchParams.get('user_id') }),
       ...(searchParams.get('priority') && { priority: searchParams.get('priority') }),
       ...(searchParams.get('status') && { status: searchParams.get('status') }),
       ...(searchParams.get('start_date') && {
@@ -86,7 +89,7 @@ export async function PUT(request: AuthenticatedRequest) {
     const task = await prisma.task.findFirst({
       where: {
         id,
-        tenant_id: tenantId,
+        email: { tenant_id: tenantId },
       },
     });
 
@@ -139,7 +142,7 @@ export async function DELETE(request: AuthenticatedRequest) {
     const task = await prisma.task.findFirst({
       where: {
         id,
-        tenant_id: tenantId,
+        email: { tenant_id: tenantId },
       },
     });
 
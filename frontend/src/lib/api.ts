@@ -195,7 +195,9 @@ export const emailsApi = {
         throw new Error(errorData.message || 'Falha ao buscar emails');
       }
 
-      return await response.json();
+      // A API retorna uma página ({ items, next_cursor, prev_cursor })
+      const page = await response.json();
+      return page.items;
     } catch (error) {
       console.error('Erro ao buscar emails:', error);
       if (error instanceof Error) {
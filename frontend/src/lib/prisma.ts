@@ -9,3 +9,8 @@ export const prisma =
   });
 
 if (process.env.NODE_ENV !== 'production') globalForPrisma.prisma = prisma;
+
+// Labels ficam na tabela email_labels; a API expõe apenas os nomes
+export function withLabelNames<T extends { labels: { label: string }[] }>(email: T) {
+  return { ...email, labels: email.labels.map((l) => l.label) };
+}
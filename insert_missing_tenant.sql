@@ -1,8 +0,0 @@
--- Insert the missing tenant
-INSERT INTO tenants (id, name, plan, active)
-VALUES (
-    '9099ab91-5a91-49bd-b3ce-b02cc5a42353',
-    'Missing Tenant',
-    'free',
-    true
-);
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"golang.org/x/time/rate"
+)
+
+// BackfillConfig configuração da importação histórica de emails
+type BackfillConfig struct {
+	ChunkDays     int           // Tamanho de cada bloco de datas pesquisado no servidor
+	BatchSize     int           // Mensagens buscadas por FETCH
+	RatePerSecond float64       // Mensagens classificadas por segundo, por tenant
+	PollInterval  time.Duration // Intervalo de verificação de novos jobs
+}
+
+// BackfillRunner executa os jobs de importação histórica de uma conta
+type BackfillRunner struct {
+	processor *EmailProcessor
+	repo      entities.BackfillRepository
+	config    BackfillConfig
+}
+
+// errBackfillPaused interrompe o job pausado via API; o bloco atual é
+// refeito ao retomar, a partir do progresso salvo
+var errBackfillPaused = errors.New("job de backfill pausado")
+
+// Limitadores compartilhados entre todas as contas de um mesmo tenant
+var tenantLimiters = struct {
+	sync.Mutex
+	m map[string]*rate.Limiter
+}{m: make(map[string]*rate.Limiter)}
+
+// NewBackfillRunner cria uma nova instância do executor de backfill para a
+// caixa descrita em account. Cada job abre a própria conexão somente leitura.
+func NewBackfillRunner(account *EmailConfig, classifier *EmailClassifier, emailRepo entities.EmailRepository, repo entities.BackfillRepository, config *BackfillConfig) *BackfillRunner {
+	cfg := BackfillConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.ChunkDays <= 0 {
+		cfg.ChunkDays = 7
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.RatePerSecond <= 0 {
+		cfg.RatePerSecond = 5
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 30 * time.Second
+	}
+
+	// Processador sem conexão própria, usado apenas para montar e classificar
+	// as mensagens buscadas pelos jobs
+	processor := &EmailProcessor{
+		emailClassifier: classifier,
+		emailRepo:       emailRepo,
+		config:          account,
+		tenantID:        account.TenantID,
+		userID:          account.UserID,
+	}
+
+	return &BackfillRunner{
+		processor: processor,
+		repo:      repo,
+		config:    cfg,
+	}
+}
+
+// NewBackfillJob cria um job pendente cobrindo os últimos days dias
+func NewBackfillJob(tenantID, userID, folder string, days int, now time.Time) *entities.BackfillJob {
+	// SINCE/BEFORE do IMAP trabalham com datas, então os limites são alinhados ao dia
+	until := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	if folder == "" {
+		folder = "INBOX"
+	}
+
+	return &entities.BackfillJob{
+		TenantID: tenantID,
+		UserID:   userID,
+		Folder:   folder,
+		Since:    until.AddDate(0, 0, -days),
+		Until:    until,
+		Cursor:   until,
+		Status:   entities.BackfillPending,
+	}
+}
+
+// Run processa os jobs pendentes da conta até o contexto ser cancelado.
+// Jobs interrompidos por reinício do serviço continuam do último bloco salvo.
+func (br *BackfillRunner) Run(ctx context.Context) {
+	// Jobs e emails da conta pertencem ao tenant configurado
+	ctx = entities.WithTenant(ctx, br.processor.tenantID)
+
+	ticker := time.NewTicker(br.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		jobs, err := br.repo.ListUnfinished(ctx, br.processor.tenantID, br.processor.userID)
+		if err != nil {
+			log.Printf("Erro ao listar jobs de backfill: %v", err)
+		}
+
+		for _, job := range jobs {
+			if err := br.runJob(ctx, job); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("Erro no job de backfill %s: %v", job.ID, err)
+				br.saveError(ctx, job.ID, err)
+				if err := br.repo.UpdateStatus(ctx, job.ID, entities.BackfillFailed); err != nil {
+					log.Printf("Erro ao marcar job de backfill %s como falho: %v", job.ID, err)
+				}
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runJob processa os blocos restantes de um job, do mais recente para o mais antigo
+func (br *BackfillRunner) runJob(ctx context.Context, job *entities.BackfillJob) error {
+	if err := br.repo.UpdateStatus(ctx, job.ID, entities.BackfillRunning); err != nil {
+		return err
+	}
+
+	// Conexão dedicada e somente leitura: o backfill não altera flags nem pastas
+	c, err := dialIMAP(br.processor.config)
+	if err != nil {
+		return err
+	}
+	defer c.Logout()
+
+	if _, err := c.Select(job.Folder, true); err != nil {
+		return fmt.Errorf("erro ao selecionar pasta %s: %v", job.Folder, err)
+	}
+
+	limiter := tenantLimiter(job.TenantID, br.config.RatePerSecond)
+
+	for job.Cursor.After(job.Since) {
+		start := job.Cursor.AddDate(0, 0, -br.config.ChunkDays)
+		if start.Before(job.Since) {
+			start = job.Since
+		}
+
+		err := br.processChunk(ctx, c, limiter, job, start)
+		if errors.Is(err, errBackfillPaused) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Contadores e cursor são salvos juntos: ao retomar, o bloco
+		// interrompido é refeito sem contar as mensagens duas vezes
+		job.Cursor = start
+		br.saveProgress(ctx, job)
+	}
+
+	return br.repo.UpdateStatus(ctx, job.ID, entities.BackfillCompleted)
+}
+
+// processChunk classifica as mensagens recebidas entre start (inclusive) e o cursor do job
+func (br *BackfillRunner) processChunk(ctx context.Context, c *client.Client, limiter *rate.Limiter, job *entities.BackfillJob, start time.Time) error {
+	criteria := imap.NewSearchCriteria()
+	criteria.Since = start
+	criteria.Before = job.Cursor
+
+	uids, err := c.UidSearch(criteria)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar emails entre %s e %s: %v",
+			start.Format("2006-01-02"), job.Cursor.Format("2006-01-02"), err)
+	}
+
+	for i := 0; i < len(uids); i += br.config.BatchSize {
+		if paused, err := br.isPaused(ctx, job.ID); err != nil {
+			return err
+		} else if paused {
+			return errBackfillPaused
+		}
+
+		end := i + br.config.BatchSize
+		if end > len(uids) {
+			end = len(uids)
+		}
+
+		seqset := new(imap.SeqSet)
+		seqset.AddNum(uids[i:end]...)
+		messages, err := fetchMessages(c, seqset)
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range messages {
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+
+			job.Scanned++
+			if _, err := br.processor.classifyAndStore(ctx, br.processor.buildEmail(job.Folder, msg)); err != nil {
+				job.Failed++
+				job.LastError = err.Error()
+				log.Printf("Erro ao classificar mensagem %d no backfill %s: %v", msg.Uid, job.ID, err)
+				continue
+			}
+			job.Classified++
+		}
+	}
+
+	return nil
+}
+
+// isPaused verifica se o job foi pausado via API desde a última checagem
+func (br *BackfillRunner) isPaused(ctx context.Context, id string) (bool, error) {
+	current, err := br.repo.GetByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return current.Status == entities.BackfillPaused, nil
+}
+
+// saveProgress persiste os contadores e o cursor do job
+func (br *BackfillRunner) saveProgress(ctx context.Context, job *entities.BackfillJob) {
+	if err := br.repo.UpdateProgress(ctx, job); err != nil {
+		log.Printf("Erro ao salvar progresso do backfill %s: %v", job.ID, err)
+	}
+}
+
+// saveError registra o erro que interrompeu o job. Contadores e cursor ficam
+// como no último bloco concluído, já que o bloco interrompido é refeito ao
+// retomar.
+func (br *BackfillRunner) saveError(ctx context.Context, id string, cause error) {
+	job, err := br.repo.GetByID(ctx, id)
+	if err == nil {
+		job.LastError = cause.Error()
+		err = br.repo.UpdateProgress(ctx, job)
+	}
+	if err != nil {
+		log.Printf("Erro ao salvar falha do backfill %s: %v", id, err)
+	}
+}
+
+// tenantLimiter retorna o limitador de taxa compartilhado do tenant
+func tenantLimiter(tenantID string, perSecond float64) *rate.Limiter {
+	tenantLimiters.Lock()
+	defer tenantLimiters.Unlock()
+
+	limiter, ok := tenantLimiters.m[tenantID]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(perSecond), 1)
+		tenantLimiters.m[tenantID] = limiter
+	}
+	return limiter
+}
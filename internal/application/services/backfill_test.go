@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/memory"
+)
+
+// scriptedBackfillRepo repositório de jobs que pausa o job ou falha na
+// n-ésima leitura e registra os cursores salvos
+type scriptedBackfillRepo struct {
+	entities.BackfillRepository
+
+	mu      sync.Mutex
+	gets    int
+	pauseAt int
+	failAt  int
+	cursors []time.Time
+}
+
+func (r *scriptedBackfillRepo) GetByID(ctx context.Context, id string) (*entities.BackfillJob, error) {
+	r.mu.Lock()
+	r.gets++
+	gets := r.gets
+	r.mu.Unlock()
+
+	switch gets {
+	case r.failAt:
+		return nil, errors.New("banco indisponível")
+	case r.pauseAt:
+		// Pausa feita pela API entre dois lotes
+		if err := r.BackfillRepository.UpdateStatus(ctx, id, entities.BackfillPaused); err != nil {
+			return nil, err
+		}
+	}
+	return r.BackfillRepository.GetByID(ctx, id)
+}
+
+func (r *scriptedBackfillRepo) UpdateProgress(ctx context.Context, job *entities.BackfillJob) error {
+	r.mu.Lock()
+	r.cursors = append(r.cursors, job.Cursor)
+	r.mu.Unlock()
+	return r.BackfillRepository.UpdateProgress(ctx, job)
+}
+
+// backfillFixture servidor com mensagens espalhadas por três blocos de 7 dias
+type backfillFixture struct {
+	ts     *testStore
+	srv    *fakeIMAPServer
+	repo   *scriptedBackfillRepo
+	runner *BackfillRunner
+	job    *entities.BackfillJob
+}
+
+func newBackfillFixture(t *testing.T) *backfillFixture {
+	t.Helper()
+	ts := newTestStore(t)
+	srv := newFakeIMAPServer(t)
+	job := NewBackfillJob(ts.tenantID, ts.userID, "INBOX", 21, time.Now().UTC())
+
+	// Blocos: [-7, 0) com 2 mensagens, contando a já lida do backend, [-14, -7)
+	// com 1 e [-21, -14) com 2
+	for _, days := range []int{3, 10, 15, 20} {
+		srv.addMessage(t, "Mensagem de "+job.Until.AddDate(0, 0, -days).Format("2006-01-02"),
+			job.Until.AddDate(0, 0, -days).Add(12*time.Hour))
+	}
+
+	repo := &scriptedBackfillRepo{BackfillRepository: memory.NewBackfillRepository(ts.store)}
+	if err := repo.Create(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	runner := NewBackfillRunner(srv.config(ts), NewEmailClassifier(), ts.emails, repo, &BackfillConfig{
+		ChunkDays: 7, BatchSize: 1, RatePerSecond: 1000, PollInterval: time.Hour,
+	})
+	return &backfillFixture{ts: ts, srv: srv, repo: repo, runner: runner, job: job}
+}
+
+// stored lê o job salvo no repositório
+func (f *backfillFixture) stored(t *testing.T) *entities.BackfillJob {
+	t.Helper()
+	job, err := f.repo.BackfillRepository.GetByID(context.Background(), f.job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func (f *backfillFixture) runJob(t *testing.T) {
+	t.Helper()
+	if err := f.runner.runJob(context.Background(), f.stored(t)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackfillRunnerProcessesChunksNewestFirst(t *testing.T) {
+	f := newBackfillFixture(t)
+	f.runJob(t)
+
+	job := f.stored(t)
+	if job.Status != entities.BackfillCompleted || job.Scanned != 5 || job.Classified != 5 || job.Failed != 0 {
+		t.Errorf("job = %+v, esperado concluído com 5 mensagens", job)
+	}
+	want := []time.Time{f.job.Until.AddDate(0, 0, -7), f.job.Until.AddDate(0, 0, -14), f.job.Since}
+	if len(f.repo.cursors) != len(want) {
+		t.Fatalf("cursores salvos = %v, esperado %v", f.repo.cursors, want)
+	}
+	for i := range want {
+		if !f.repo.cursors[i].Equal(want[i]) {
+			t.Errorf("cursor %d = %s, esperado %s", i, f.repo.cursors[i], want[i])
+		}
+	}
+	if emails := f.ts.listEmails(t); len(emails) != 5 {
+		t.Errorf("salvos %d emails, esperado 5", len(emails))
+	}
+}
+
+func TestBackfillRunnerResumesPausedChunk(t *testing.T) {
+	f := newBackfillFixture(t)
+
+	// Leituras: 2 lotes no primeiro bloco, 1 no segundo, e a pausa depois do
+	// primeiro lote do terceiro
+	f.repo.pauseAt = 5
+	f.runJob(t)
+
+	job := f.stored(t)
+	if job.Status != entities.BackfillPaused || !job.Cursor.Equal(f.job.Until.AddDate(0, 0, -14)) || job.Scanned != 3 {
+		t.Fatalf("job pausado = %+v, esperado cursor no fim do segundo bloco e 3 mensagens", job)
+	}
+
+	// Retomar refaz o terceiro bloco sem contar a mensagem já lida duas vezes
+	if err := f.repo.UpdateStatus(context.Background(), job.ID, entities.BackfillPending); err != nil {
+		t.Fatal(err)
+	}
+	f.runJob(t)
+
+	job = f.stored(t)
+	if job.Status != entities.BackfillCompleted || !job.Cursor.Equal(job.Since) || job.Scanned != 5 || job.Classified != 5 {
+		t.Errorf("job retomado = %+v, esperado concluído com 5 mensagens", job)
+	}
+	if emails := f.ts.listEmails(t); len(emails) != 5 {
+		t.Errorf("salvos %d emails, esperado 5", len(emails))
+	}
+}
+
+func TestBackfillRunnerFailureKeepsCompletedProgress(t *testing.T) {
+	f := newBackfillFixture(t)
+	f.repo.failAt = 5
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		f.runner.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	waitFor(t, "falha do job", func() bool { return f.stored(t).Status == entities.BackfillFailed })
+
+	// O bloco interrompido não entra nos contadores: será refeito ao retomar
+	job := f.stored(t)
+	if !job.Cursor.Equal(f.job.Until.AddDate(0, 0, -14)) || job.Scanned != 3 || job.Classified != 3 {
+		t.Errorf("job com falha = %+v, esperado o progresso dos dois primeiros blocos", job)
+	}
+	if job.LastError != "banco indisponível" {
+		t.Errorf("LastError = %q", job.LastError)
+	}
+}
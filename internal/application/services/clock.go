@@ -0,0 +1,15 @@
+package services
+
+import "time"
+
+// Clock fonte do horário atual, substituível por um relógio controlado em testes
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock relógio do sistema
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/enzo010/email-filter/internal/application/services/nlp"
@@ -10,6 +11,7 @@ import (
 
 // EmailClassifier serviço responsável pela classificação de emails
 type EmailClassifier struct {
+	mu       sync.Mutex // O modelo guarda o documento analisado e não pode ser compartilhado
 	nlpModel *nlp.Model
 }
 
@@ -24,6 +26,9 @@ type ClassificationResult struct {
 
 // ClassifyEmail classifica um email usando NLP
 func (ec *EmailClassifier) ClassifyEmail(ctx context.Context, email *entities.Email) (*ClassificationResult, error) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
 	// Preparar texto para análise
 	text := email.Subject + "\n" + email.Content
 	if err := ec.nlpModel.AnalyzeText(text); err != nil {
@@ -39,8 +44,11 @@ func (ec *EmailClassifier) ClassifyEmail(ctx context.Context, email *entities.Em
 		SuggestedTasks: ec.nlpModel.ExtractTasks(email),
 	}
 
-	// Atualizar timestamps das tarefas sugeridas
+	// Convites de reunião geram a tarefa de preparação
 	now := time.Now()
+	applyMeeting(result, email.Meeting, now)
+
+	// Atualizar timestamps das tarefas sugeridas
 	for i := range result.SuggestedTasks {
 		result.SuggestedTasks[i].CreatedAt = now
 		result.SuggestedTasks[i].UpdatedAt = now
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/emersion/go-imap/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Estados da conexão IMAP reportados em logs e métricas
+const (
+	connStateConnecting   = "connecting"
+	connStateSyncing      = "syncing"
+	connStateIdling       = "idling"
+	connStateDisconnected = "disconnected"
+	connStateStopped      = "stopped"
+)
+
+const (
+	defaultIdleRefresh = 25 * time.Minute // Servidores encerram IDLE após 29 minutos
+	defaultMaxBackoff  = 5 * time.Minute
+	minBackoff         = time.Second
+	stableSession      = time.Minute // Sessões mais curtas mantêm a espera de reconexão
+	commandTimeout     = 30 * time.Second
+)
+
+var errConnectionClosed = errors.New("conexão IMAP encerrada pelo servidor")
+
+var (
+	imapStateTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "imap_connection_state_transitions_total",
+		Help: "Total number of IMAP connection state transitions.",
+	}, []string{"tenant_id", "role", "state"})
+
+	imapConnectionUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "imap_connection_up",
+		Help: "Whether the IMAP connection is currently established (1) or not (0).",
+	}, []string{"tenant_id", "role"})
+
+	imapReconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "imap_reconnects_total",
+		Help: "Total number of IMAP reconnection attempts.",
+	}, []string{"tenant_id", "role"})
+)
+
+// StartProcessing inicia o processamento de emails.
+// A pasta principal é acompanhada via IDLE e as demais por polling, cada uma
+// em sua própria conexão com reconexão automática.
+func (ep *EmailProcessor) StartProcessing(ctx context.Context) error {
+	folders := ep.config.folders()
+	primary := folders[0]
+
+	// Validar a pasta principal antes de iniciar o loop em segundo plano
+	if _, err := ep.imapClient.Select(primary, false); err != nil {
+		return fmt.Errorf("erro ao selecionar pasta: %v", err)
+	}
+
+	go ep.idleLoop(ctx, primary)
+
+	// Pastas secundárias são verificadas periodicamente em uma conexão dedicada,
+	// já que a conexão principal fica ocupada com o IDLE
+	if len(folders) > 1 {
+		go ep.pollLoop(ctx, folders[1:])
+	}
+
+	return nil
+}
+
+// idleLoop mantém a pasta principal sincronizada, reconectando quando necessário
+func (ep *EmailProcessor) idleLoop(ctx context.Context, folder string) {
+	ep.mu.Lock()
+	c := ep.imapClient
+	ep.mu.Unlock()
+
+	b := ep.newBackoff()
+	for {
+		started := time.Now()
+		err := ep.idleSession(ctx, c, folder)
+		if ctx.Err() != nil {
+			ep.setConnState("idle", connStateStopped)
+			return
+		}
+		ep.setConnState("idle", connStateDisconnected)
+		log.Printf("Conexão IDLE da pasta %s interrompida: %v", folder, err)
+		c.Terminate()
+
+		// Uma sessão que cai logo após conectar conta como falha, para que o
+		// servidor não receba logins em sequência
+		if time.Since(started) >= stableSession {
+			b.reset()
+		} else {
+			b.fail()
+		}
+
+		c, err = ep.reconnect(ctx, "idle", folder, b)
+		if err != nil {
+			ep.setConnState("idle", connStateStopped)
+			return
+		}
+
+		ep.mu.Lock()
+		ep.imapClient = c
+		ep.mu.Unlock()
+	}
+}
+
+// idleSession processa a pasta selecionada e alterna com IDLE até a conexão cair
+// ou o contexto ser cancelado. Falhas no processamento que não vêm da conexão,
+// como erros do banco, não encerram a sessão: a pasta é processada de novo na
+// próxima notificação ou no reinício periódico do IDLE.
+func (ep *EmailProcessor) idleSession(ctx context.Context, c *client.Client, folder string) error {
+	newMail := watchMailboxUpdates(c)
+
+	refresh := ep.config.IdleRefresh
+	if refresh <= 0 {
+		refresh = defaultIdleRefresh
+	}
+
+	for {
+		ep.setConnState("idle", connStateSyncing)
+		if err := ep.processNewEmails(ctx, c, folder); err != nil {
+			if connErr := checkConnection(c); connErr != nil {
+				return connErr
+			}
+			log.Printf("Erro ao processar pasta %s: %v", folder, err)
+		}
+
+		ep.setConnState("idle", connStateIdling)
+		stop := make(chan struct{})
+		done := make(chan error, 1)
+		go func() {
+			// O reinício periódico é controlado aqui para poder validar a conexão
+			done <- c.Idle(stop, &client.IdleOptions{LogoutTimeout: -1})
+		}()
+
+		timer := time.NewTimer(refresh)
+		var err error
+		select {
+		case <-newMail:
+			err = stopIdle(c, stop, done)
+		case <-timer.C:
+			// Sair do IDLE antes do timeout do servidor e confirmar que a conexão responde
+			if err = stopIdle(c, stop, done); err == nil {
+				err = withTimeout(c, c.Noop)
+			}
+		case err = <-done:
+			if err == nil {
+				err = errConnectionClosed
+			}
+		case <-c.LoggedOut():
+			close(stop)
+			err = errConnectionClosed
+		case <-ctx.Done():
+			stopIdle(c, stop, done)
+			err = ctx.Err()
+		}
+		timer.Stop()
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// pollLoop verifica periodicamente as pastas secundárias, reconectando quando necessário
+func (ep *EmailProcessor) pollLoop(ctx context.Context, folders []string) {
+	interval := ep.config.PollInterval
+	if interval <= 0 {
+		interval = 2 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	b := ep.newBackoff()
+	var c *client.Client
+	for {
+		if c == nil {
+			var err error
+			if c, err = ep.reconnect(ctx, "poll", "", b); err != nil {
+				ep.setConnState("poll", connStateStopped)
+				return
+			}
+			ep.mu.Lock()
+			ep.pollClient = c
+			ep.mu.Unlock()
+		}
+
+		ep.setConnState("poll", connStateSyncing)
+		for _, folder := range folders {
+			if err := ep.pollFolder(ctx, c, folder); err != nil {
+				log.Printf("Erro ao processar pasta %s: %v", folder, err)
+			}
+		}
+
+		// Conexão perdida durante a verificação: reconectar na próxima iteração
+		if err := withTimeout(c, c.Noop); err != nil {
+			ep.setConnState("poll", connStateDisconnected)
+			log.Printf("Conexão de polling interrompida: %v", err)
+			c.Terminate()
+			c = nil
+			b.fail()
+			continue
+		}
+		b.reset()
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			ep.setConnState("poll", connStateStopped)
+			return
+		}
+	}
+}
+
+// pollFolder seleciona e processa uma pasta secundária
+func (ep *EmailProcessor) pollFolder(ctx context.Context, c *client.Client, folder string) error {
+	if _, err := c.Select(folder, false); err != nil {
+		return fmt.Errorf("erro ao selecionar pasta: %v", err)
+	}
+	return ep.processNewEmails(ctx, c, folder)
+}
+
+// reconnect abre uma nova conexão até obter sucesso ou o contexto ser
+// cancelado. A espera entre tentativas vem de b, que guarda as falhas de
+// sessões anteriores. Se folder for informado, a pasta é selecionada.
+func (ep *EmailProcessor) reconnect(ctx context.Context, role, folder string, b *backoff) (*client.Client, error) {
+	for attempt := 1; ; attempt++ {
+		if wait := b.delay(); wait > 0 {
+			log.Printf("Nova tentativa de conexão IMAP (%s) em %s", role, wait)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		ep.setConnState(role, connStateConnecting)
+		imapReconnects.WithLabelValues(ep.tenantID, role).Inc()
+
+		c, err := dialIMAP(ep.config)
+		if err == nil && folder != "" {
+			if _, err = c.Select(folder, false); err != nil {
+				c.Terminate()
+				err = fmt.Errorf("erro ao selecionar pasta: %v", err)
+			}
+		}
+		if err == nil {
+			log.Printf("Conexão IMAP (%s) restabelecida após %d tentativa(s)", role, attempt)
+			return c, nil
+		}
+
+		log.Printf("Erro ao reconectar IMAP (%s): %v", role, err)
+		ep.setConnState(role, connStateDisconnected)
+		b.fail()
+	}
+}
+
+// backoff espera exponencial entre tentativas de conexão
+type backoff struct {
+	max     time.Duration
+	current time.Duration // Zero enquanto não há falhas
+}
+
+// newBackoff cria a espera de reconexão limitada por MaxBackoff
+func (ep *EmailProcessor) newBackoff() *backoff {
+	max := ep.config.MaxBackoff
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+	return &backoff{max: max}
+}
+
+// fail registra uma falha, dobrando a próxima espera até o máximo
+func (b *backoff) fail() {
+	if b.current == 0 {
+		b.current = minBackoff
+	} else {
+		b.current *= 2
+	}
+	if b.current > b.max {
+		b.current = b.max
+	}
+}
+
+// reset volta a conectar sem espera depois de uma sessão estável
+func (b *backoff) reset() {
+	b.current = 0
+}
+
+// delay espera antes da próxima tentativa, com jitter para evitar reconexões
+// simultâneas de várias contas
+func (b *backoff) delay() time.Duration {
+	if b.current == 0 {
+		return 0
+	}
+	return b.current/2 + time.Duration(rand.Int63n(int64(b.current/2)+1))
+}
+
+// setConnState registra a transição de estado de uma conexão
+func (ep *EmailProcessor) setConnState(role, state string) {
+	ep.mu.Lock()
+	if ep.connStates == nil {
+		ep.connStates = make(map[string]string)
+	}
+	previous := ep.connStates[role]
+	ep.connStates[role] = state
+	ep.mu.Unlock()
+
+	if previous == state {
+		return
+	}
+
+	imapStateTransitions.WithLabelValues(ep.tenantID, role, state).Inc()
+	up := 0.0
+	if state == connStateSyncing || state == connStateIdling {
+		up = 1
+	}
+	imapConnectionUp.WithLabelValues(ep.tenantID, role).Set(up)
+
+	log.Printf("Conexão IMAP (%s) do tenant %s: %s -> %s", role, ep.tenantID, previous, state)
+}
+
+// watchMailboxUpdates consome as atualizações não solicitadas do servidor e
+// sinaliza a chegada de novas mensagens. O canal de atualizações precisa ser
+// drenado continuamente para não bloquear a leitura da conexão.
+func watchMailboxUpdates(c *client.Client) <-chan struct{} {
+	updates := make(chan client.Update, 16)
+	newMail := make(chan struct{}, 1)
+	c.Updates = updates
+
+	go func() {
+		for {
+			select {
+			case update := <-updates:
+				if _, ok := update.(*client.MailboxUpdate); ok {
+					select {
+					case newMail <- struct{}{}:
+					default:
+					}
+				}
+			case <-c.LoggedOut():
+				return
+			}
+		}
+	}()
+
+	return newMail
+}
+
+// stopIdle encerra o IDLE e aguarda a confirmação do servidor
+func stopIdle(c *client.Client, stop chan struct{}, done <-chan error) error {
+	close(stop)
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(commandTimeout):
+		c.Terminate()
+		return errors.New("servidor não respondeu ao término do IDLE")
+	}
+}
+
+// checkConnection confirma que a conexão ainda responde
+func checkConnection(c *client.Client) error {
+	select {
+	case <-c.LoggedOut():
+		return errConnectionClosed
+	default:
+	}
+	return withTimeout(c, c.Noop)
+}
+
+// withTimeout executa um comando IMAP encerrando a conexão caso não haja resposta
+func withTimeout(c *client.Client, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(commandTimeout):
+		c.Terminate()
+		return errors.New("tempo esgotado aguardando resposta do servidor")
+	}
+}
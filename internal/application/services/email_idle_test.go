@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend"
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/server"
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// fakeIMAPServer servidor IMAP em memória, com uma mensagem já lida na INBOX,
+// que conta os logins e permite derrubar as conexões abertas
+type fakeIMAPServer struct {
+	backend.Backend
+	addr string
+
+	mu     sync.Mutex
+	logins int
+	conns  []net.Conn
+}
+
+func newFakeIMAPServer(t *testing.T) *fakeIMAPServer {
+	t.Helper()
+	s := &fakeIMAPServer{Backend: memory.New()}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.addr = l.Addr().String()
+
+	srv := server.New(s)
+	srv.AllowInsecureAuth = true
+	go srv.Serve(&trackingListener{Listener: l, server: s})
+	t.Cleanup(func() { srv.Close() })
+	return s
+}
+
+// addMessage adiciona uma mensagem não lida à INBOX; deve ser chamada antes
+// das conexões, já que o backend em memória não é seguro para concorrência
+func (s *fakeIMAPServer) addMessage(t *testing.T, subject string, date time.Time) {
+	t.Helper()
+	user, err := s.Backend.Login(nil, "username", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inbox, err := user.GetMailbox("INBOX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mbox := inbox.(*memory.Mailbox)
+	body := "From: cliente@example.com\r\n" +
+		"To: ana@acme.test\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Message-ID: <" + strconv.Itoa(len(mbox.Messages)) + "@example.com>\r\n" +
+		"Content-Type: text/plain\r\n" +
+		"\r\n" +
+		subject
+	mbox.Messages = append(mbox.Messages, &memory.Message{
+		Uid:  mbox.Messages[len(mbox.Messages)-1].Uid + 1,
+		Date: date,
+		Size: uint32(len(body)),
+		Body: []byte(body),
+	})
+}
+
+func (s *fakeIMAPServer) Login(info *imap.ConnInfo, username, password string) (backend.User, error) {
+	s.mu.Lock()
+	s.logins++
+	s.mu.Unlock()
+	return s.Backend.Login(info, username, password)
+}
+
+func (s *fakeIMAPServer) loginCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// dropConnections encerra as conexões abertas como uma queda de rede
+func (s *fakeIMAPServer) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+// config caixa do usuário do teste no servidor
+func (s *fakeIMAPServer) config(ts *testStore) *EmailConfig {
+	host, port, _ := net.SplitHostPort(s.addr)
+	p, _ := strconv.Atoi(port)
+	return &EmailConfig{
+		Server: host, Port: p, Username: "username", Password: "password", Folder: "INBOX",
+		TenantID: ts.tenantID, UserID: ts.userID,
+		IdleRefresh: 50 * time.Millisecond,
+		MaxBackoff:  50 * time.Millisecond,
+	}
+}
+
+// newProcessor conecta um EmailProcessor do usuário do teste ao servidor
+func (s *fakeIMAPServer) newProcessor(t *testing.T, ts *testStore, states entities.FolderStateRepository) *EmailProcessor {
+	t.Helper()
+	ep, err := NewEmailProcessor(s.config(ts), NewEmailClassifier(), ts.emails, states)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ep
+}
+
+// trackingListener guarda as conexões aceitas pelo servidor
+type trackingListener struct {
+	net.Listener
+	server *fakeIMAPServer
+}
+
+func (l *trackingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.server.mu.Lock()
+		l.server.conns = append(l.server.conns, conn)
+		l.server.mu.Unlock()
+	}
+	return conn, err
+}
+
+// failingStateRepo repositório de progresso sempre indisponível
+type failingStateRepo struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (r *failingStateRepo) GetFolderState(ctx context.Context, account, folder string) (*entities.FolderState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	return nil, errors.New("banco indisponível")
+}
+
+func (r *failingStateRepo) SaveFolderState(ctx context.Context, state *entities.FolderState) error {
+	return errors.New("banco indisponível")
+}
+
+func (r *failingStateRepo) callCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+// startIdleLoop inicia o processamento da pasta principal até o fim do teste
+func startIdleLoop(t *testing.T, ep *EmailProcessor) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := ep.StartProcessing(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		waitFor(t, "fim do loop IDLE", func() bool { return ep.connState("idle") == connStateStopped })
+	})
+}
+
+// waitFor aguarda a condição ou falha o teste após o tempo limite
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("tempo esgotado aguardando %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (ep *EmailProcessor) connState(role string) string {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return ep.connStates[role]
+}
+
+func TestIdleLoopKeepsConnectionOnProcessingError(t *testing.T) {
+	srv := newFakeIMAPServer(t)
+	srv.addMessage(t, "Proposta comercial", time.Now())
+	ts := newTestStore(t)
+	states := &failingStateRepo{}
+	ep := srv.newProcessor(t, ts, states)
+	startIdleLoop(t, ep)
+
+	// Cada reinício do IDLE tenta processar a pasta de novo na mesma conexão
+	waitFor(t, "novas tentativas de processamento", func() bool { return states.callCount() >= 3 })
+	if logins := srv.loginCount(); logins != 1 {
+		t.Errorf("erro do banco gerou %d logins, esperado 1", logins)
+	}
+	if len(ts.listEmails(t)) != 0 {
+		t.Error("email salvo sem o progresso da pasta")
+	}
+}
+
+func TestIdleLoopReconnectsAfterDrop(t *testing.T) {
+	srv := newFakeIMAPServer(t)
+	srv.addMessage(t, "Proposta comercial", time.Now())
+	ts := newTestStore(t)
+	ep := srv.newProcessor(t, ts, nil)
+	startIdleLoop(t, ep)
+
+	waitFor(t, "sincronização inicial", func() bool { return len(ts.listEmails(t)) == 1 })
+	waitFor(t, "IDLE", func() bool { return ep.connState("idle") == connStateIdling })
+
+	srv.dropConnections()
+	waitFor(t, "reconexão", func() bool { return srv.loginCount() == 2 })
+	waitFor(t, "IDLE após reconectar", func() bool { return ep.connState("idle") == connStateIdling })
+
+	// A mensagem já foi marcada como lida e não é salva de novo
+	if emails := ts.listEmails(t); len(emails) != 1 {
+		t.Errorf("salvos %d emails após reconectar, esperado 1", len(emails))
+	}
+}
+
+func TestBackoffPersistsAcrossFailures(t *testing.T) {
+	b := &backoff{max: 4 * time.Second}
+	if d := b.delay(); d != 0 {
+		t.Fatalf("primeira tentativa espera %s, esperado conectar sem espera", d)
+	}
+
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		b.fail()
+		if d := b.delay(); d < want/2 || d > want {
+			t.Errorf("espera %s fora de [%s, %s]", d, want/2, want)
+		}
+	}
+
+	b.reset()
+	if d := b.delay(); d != 0 {
+		t.Errorf("espera %s após sessão estável, esperado 0", d)
+	}
+}
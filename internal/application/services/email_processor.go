@@ -3,14 +3,13 @@ package services
 import (
 	"context"
 	"fmt"
-	"io"
 	"log"
-	"strings"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/emersion/go-imap"
 	"github.com/emersion/go-imap/client"
-	"github.com/emersion/go-message/mail"
 	"github.com/enzo010/email-filter/internal/domain/entities"
 )
 
@@ -19,9 +18,17 @@ type EmailProcessor struct {
 	imapClient      *client.Client
 	emailClassifier *EmailClassifier
 	emailRepo       entities.EmailRepository
+	stateRepo       entities.FolderStateRepository
 	config          *EmailConfig
 	tenantID        string
 	userID          string
+	pollClient      *client.Client // Conexão usada para as pastas secundárias
+	gmail           bool           // Servidor suporta extensões X-GM-EXT-1
+
+	mu           sync.Mutex
+	knownFolders map[string]bool                  // Pastas já verificadas/criadas no servidor
+	syncState    map[string]*entities.FolderState // Estado de sincronização por pasta
+	connStates   map[string]string                // Estado atual de cada conexão (idle, poll)
 }
 
 // EmailConfig configuração para conexão com servidor de email
@@ -30,14 +37,52 @@ type EmailConfig struct {
 	Port     int
 	Username string
 	Password string
-	Folder   string // Ex: "INBOX"
+	Folder   string // Pasta principal, acompanhada via IDLE. Ex: "INBOX"
 	SSL      bool
 	TenantID string // Adicionado campo TenantID
 	UserID   string // Adicionado campo UserID
+
+	// Folders pastas adicionais monitoradas por polling. Ex: "Suporte", "Financeiro"
+	Folders      []string
+	PollInterval time.Duration // Intervalo de polling das pastas adicionais
+
+	IdleRefresh time.Duration // Reinício periódico do IDLE (deve ser menor que 29 minutos)
+	MaxBackoff  time.Duration // Espera máxima entre tentativas de reconexão
+
+	// WriteBack controla o reflexo da classificação na própria caixa de correio
+	WriteBack WriteBackConfig
 }
 
-// NewEmailProcessor cria uma nova instância do processador de emails
-func NewEmailProcessor(config *EmailConfig, classifier *EmailClassifier, repo entities.EmailRepository) (*EmailProcessor, error) {
+// NewEmailProcessor cria uma nova instância do processador de emails. O
+// progresso de cada pasta é salvo em stateRepo para retomar após reinícios.
+func NewEmailProcessor(config *EmailConfig, classifier *EmailClassifier, repo entities.EmailRepository, stateRepo entities.FolderStateRepository) (*EmailProcessor, error) {
+	c, err := dialIMAP(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Detectar extensões do Gmail (X-GM-LABELS)
+	gmail, err := c.Support("X-GM-EXT-1")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar capacidades do servidor: %v", err)
+	}
+
+	return &EmailProcessor{
+		imapClient:      c,
+		emailClassifier: classifier,
+		emailRepo:       repo,
+		stateRepo:       stateRepo,
+		config:          config,
+		tenantID:        config.TenantID,
+		userID:          config.UserID,
+		gmail:           gmail,
+		knownFolders:    make(map[string]bool),
+		syncState:       make(map[string]*entities.FolderState),
+	}, nil
+}
+
+// dialIMAP conecta e autentica no servidor IMAP
+func dialIMAP(config *EmailConfig) (*client.Client, error) {
 	// Construir string de conexão
 	addr := fmt.Sprintf("%s:%d", config.Server, config.Port)
 
@@ -59,176 +104,173 @@ func NewEmailProcessor(config *EmailConfig, classifier *EmailClassifier, repo en
 		return nil, fmt.Errorf("erro no login: %v", err)
 	}
 
-	return &EmailProcessor{
-		imapClient:      c,
-		emailClassifier: classifier,
-		emailRepo:       repo,
-		config:          config,
-		tenantID:        config.TenantID,
-		userID:          config.UserID,
-	}, nil
+	return c, nil
+}
+
+// folders retorna a lista de pastas monitoradas, sem duplicatas.
+// A primeira pasta é a principal e é acompanhada via IDLE.
+func (cfg *EmailConfig) folders() []string {
+	var folders []string
+	seen := make(map[string]bool)
+	for _, f := range append([]string{cfg.Folder}, cfg.Folders...) {
+		if f == "" || seen[f] {
+			continue
+		}
+		seen[f] = true
+		folders = append(folders, f)
+	}
+	if len(folders) == 0 {
+		folders = []string{"INBOX"}
+	}
+	return folders
 }
 
-// StartProcessing inicia o processamento de emails
-func (ep *EmailProcessor) StartProcessing(ctx context.Context) error {
-	// Selecionar pasta
-	if _, err := ep.imapClient.Select(ep.config.Folder, false); err != nil {
-		return fmt.Errorf("erro ao selecionar pasta: %v", err)
+// processNewEmails processa emails não lidos da pasta selecionada em c
+func (ep *EmailProcessor) processNewEmails(ctx context.Context, c *client.Client, folder string) error {
+	state, err := ep.syncStateFor(ctx, folder, c.Mailbox())
+	if err != nil {
+		return err
 	}
 
-	// Configurar critérios de busca (apenas emails não lidos)
+	// Buscar emails não lidos ainda não vistos nesta pasta
 	criteria := imap.NewSearchCriteria()
 	criteria.WithoutFlags = []string{imap.SeenFlag}
+	if state.LastUID > 0 {
+		criteria.Uid = new(imap.SeqSet)
+		criteria.Uid.AddRange(state.LastUID+1, 0)
+	}
 
-	// Canal para receber atualizações
-	updates := make(chan client.Update)
-	ep.imapClient.Updates = updates
+	uids, err := c.UidSearch(criteria)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar emails: %v", err)
+	}
 
-	// Goroutine para processar atualizações
-	go func() {
-		for {
-			select {
-			case update := <-updates:
-				if _, ok := update.(*client.MailboxUpdate); ok {
-					// Processar novos emails quando houver atualização da caixa
-					ep.processNewEmails(ctx)
-				}
-			case <-ctx.Done():
-				return
-			}
+	// "UID n:*" sempre inclui a última mensagem, mesmo que já processada
+	seqset := new(imap.SeqSet)
+	for _, uid := range uids {
+		if uid > state.LastUID {
+			seqset.AddNum(uid)
 		}
-	}()
-
-	// Processar emails existentes não lidos
-	ep.processNewEmails(ctx)
-
-	// Iniciar modo IDLE para receber notificações de novos emails
-	done := make(chan error, 1)
-	stop := make(chan struct{})
-
-	go func() {
-		done <- ep.imapClient.Idle(stop, nil)
-	}()
-
-	// Parar IDLE quando o contexto for cancelado
-	go func() {
-		<-ctx.Done()
-		close(stop)
-	}()
-
-	return nil
-}
+	}
 
-// processNewEmails processa emails não lidos
-func (ep *EmailProcessor) processNewEmails(ctx context.Context) error {
-	// Buscar emails não lidos
-	criteria := imap.NewSearchCriteria()
-	criteria.WithoutFlags = []string{imap.SeenFlag}
+	if seqset.Empty() {
+		return nil
+	}
 
-	uids, err := ep.imapClient.Search(criteria)
+	fetched, err := fetchMessages(c, seqset)
 	if err != nil {
-		return fmt.Errorf("erro ao buscar emails: %v", err)
+		return err
 	}
 
-	if len(uids) == 0 {
-		return nil
+	// Processar mensagens em ordem de UID. Após uma falha o UID deixa de
+	// avançar, para que a mensagem seja tentada de novo na próxima verificação;
+	// as seguintes já processadas ficam marcadas como lidas e não se repetem.
+	sort.Slice(fetched, func(i, j int) bool { return fetched[i].Uid < fetched[j].Uid })
+	failed := false
+	for _, msg := range fetched {
+		if err := ep.processMessage(ctx, c, folder, msg); err != nil {
+			log.Printf("Erro ao processar mensagem %d da pasta %s: %v", msg.Uid, folder, err)
+			failed = true
+			continue
+		}
+		if !failed {
+			ep.advanceFolderState(ctx, folder, msg.Uid)
+		}
 	}
 
-	// Criar sequência de UIDs
-	seqset := new(imap.SeqSet)
-	seqset.AddNum(uids...)
+	return nil
+}
 
-	// Buscar mensagens
+// fetchMessages busca as mensagens indicadas por UID. Todas as mensagens são
+// lidas antes do retorno para que novos comandos possam ser emitidos na conexão.
+func fetchMessages(c *client.Client, uids *imap.SeqSet) ([]*imap.Message, error) {
+	// BODY.PEEK[] para não alterar a flag \Seen antes de salvar
+	bodySection := &imap.BodySectionName{Peek: true}
 	messages := make(chan *imap.Message, 10)
 	done := make(chan error, 1)
 
 	go func() {
-		done <- ep.imapClient.Fetch(seqset, []imap.FetchItem{
+		done <- c.UidFetch(uids, []imap.FetchItem{
+			imap.FetchUid,
 			imap.FetchEnvelope,
 			imap.FetchFlags,
-			imap.FetchBody,
 			imap.FetchBodyStructure,
+			bodySection.FetchItem(),
 		}, messages)
 	}()
 
-	// Processar mensagens
+	var fetched []*imap.Message
 	for msg := range messages {
-		if err := ep.processMessage(ctx, msg); err != nil {
-			log.Printf("Erro ao processar mensagem %d: %v", msg.Uid, err)
-		}
+		fetched = append(fetched, msg)
+	}
+	if err := <-done; err != nil {
+		return nil, fmt.Errorf("erro ao buscar mensagens: %v", err)
 	}
 
-	return <-done
+	return fetched, nil
 }
 
-// processMessage processa uma única mensagem
-func (ep *EmailProcessor) processMessage(ctx context.Context, msg *imap.Message) error {
-	// Extrair informações do email
-	var body string
-	var subject string
-	var from string
-	var to string
-
-	if msg.Envelope != nil {
-		subject = msg.Envelope.Subject
-		if len(msg.Envelope.From) > 0 {
-			from = msg.Envelope.From[0].Address()
+// syncStateFor retorna o estado de sincronização da pasta, carregando o
+// progresso salvo e reiniciando-o caso o UIDVALIDITY informado pelo servidor
+// tenha mudado
+func (ep *EmailProcessor) syncStateFor(ctx context.Context, folder string, mbox *imap.MailboxStatus) (entities.FolderState, error) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	state, ok := ep.syncState[folder]
+	if !ok {
+		if ep.stateRepo != nil {
+			saved, err := ep.stateRepo.GetFolderState(ctx, ep.account(), folder)
+			if err != nil {
+				return entities.FolderState{}, err
+			}
+			state = saved
 		}
-		if len(msg.Envelope.To) > 0 {
-			to = msg.Envelope.To[0].Address()
+		if state == nil {
+			state = &entities.FolderState{Account: ep.account(), Folder: folder}
 		}
+		ep.syncState[folder] = state
 	}
+	if mbox != nil && mbox.UidValidity != state.UIDValidity {
+		state.UIDValidity = mbox.UidValidity
+		state.LastUID = 0
+		ep.saveFolderState(ctx, state)
+	}
+	return *state, nil
+}
 
-	// Extrair corpo do email
-	for _, part := range msg.Body {
-		mr, err := mail.CreateReader(part)
-		if err != nil {
-			continue
-		}
-
-		for {
-			p, err := mr.NextPart()
-			if err != nil {
-				break
-			}
+// advanceFolderState registra o último UID processado na pasta
+func (ep *EmailProcessor) advanceFolderState(ctx context.Context, folder string, uid uint32) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
 
-			switch p.Header.Get("Content-Type") {
-			case "text/plain":
-				buf := new(strings.Builder)
-				if _, err := io.Copy(buf, p.Body); err != nil {
-					continue
-				}
-				body = buf.String()
-			}
-		}
+	if state, ok := ep.syncState[folder]; ok && uid > state.LastUID {
+		state.LastUID = uid
+		ep.saveFolderState(ctx, state)
 	}
+}
 
-	// Criar entidade de email
-	email := &entities.Email{
-		TenantID: ep.tenantID, // Definir TenantID
-		UserID:   ep.userID,   // Definir UserID
-		Subject:  subject,
-		From:     from,
-		To:       to,
-		Content:  body,
+// saveFolderState persiste o progresso da pasta; uma falha apenas faz com que
+// mensagens já processadas sejam lidas de novo após um reinício
+func (ep *EmailProcessor) saveFolderState(ctx context.Context, state *entities.FolderState) {
+	if ep.stateRepo == nil {
+		return
 	}
-
-	// Classificar email
-	result, err := ep.emailClassifier.ClassifyEmail(ctx, email)
-	if err != nil {
-		return fmt.Errorf("erro ao classificar email: %v", err)
+	if err := ep.stateRepo.SaveFolderState(ctx, state); err != nil {
+		log.Printf("Erro ao salvar estado da pasta %s: %v", state.Folder, err)
 	}
+}
 
-	// Atualizar email com resultados
-	email.Priority = result.Priority
-	email.Category = result.Category
-	email.Labels = result.Labels
-	email.Tasks = result.SuggestedTasks
-	email.ProcessedAt = time.Now()
+// account identifica a caixa para o registro do progresso das pastas
+func (ep *EmailProcessor) account() string {
+	return fmt.Sprintf("imap:%s:%s@%s", ep.tenantID, ep.config.Username, ep.config.Server)
+}
 
-	// Salvar no banco de dados
-	if err := ep.emailRepo.Create(ctx, email); err != nil {
-		return fmt.Errorf("erro ao salvar email: %v", err)
+// processMessage processa uma única mensagem
+func (ep *EmailProcessor) processMessage(ctx context.Context, c *client.Client, folder string, msg *imap.Message) error {
+	result, err := ep.classifyAndStore(ctx, ep.buildEmail(folder, msg))
+	if err != nil {
+		return err
 	}
 
 	// Marcar como lido
@@ -236,14 +278,64 @@ func (ep *EmailProcessor) processMessage(ctx context.Context, msg *imap.Message)
 	seqset.AddNum(msg.Uid)
 	item := imap.FormatFlagsOp(imap.AddFlags, true)
 	flags := []interface{}{imap.SeenFlag}
-	if err := ep.imapClient.UidStore(seqset, item, flags, nil); err != nil {
+	if err := c.UidStore(seqset, item, flags, nil); err != nil {
 		return fmt.Errorf("erro ao marcar email como lido: %v", err)
 	}
 
+	// Refletir a classificação na caixa de correio
+	if err := ep.writeBack(c, msg.Uid, result); err != nil {
+		return fmt.Errorf("erro ao aplicar classificação na caixa: %v", err)
+	}
+
 	return nil
 }
 
-// Close fecha a conexão com o servidor IMAP
+// buildEmail converte uma mensagem IMAP na entidade de email
+func (ep *EmailProcessor) buildEmail(folder string, msg *imap.Message) *entities.Email {
+	email := &entities.Email{}
+
+	// Extrair cabeçalhos e corpo da mensagem completa
+	for _, literal := range msg.Body {
+		if parsed, err := ParseMessage(literal); err == nil {
+			email = parsed
+		}
+	}
+
+	// O envelope já vem decodificado pelo servidor e tem precedência
+	if msg.Envelope != nil {
+		if msg.Envelope.Subject != "" {
+			email.Subject = msg.Envelope.Subject
+		}
+		if len(msg.Envelope.From) > 0 {
+			email.From = msg.Envelope.From[0].Address()
+		}
+		if len(msg.Envelope.To) > 0 {
+			email.To = msg.Envelope.To[0].Address()
+		}
+	}
+
+	email.TenantID = ep.tenantID
+	email.UserID = ep.userID
+	email.Folder = folder
+
+	return email
+}
+
+// classifyAndStore classifica o email e o salva no banco de dados
+func (ep *EmailProcessor) classifyAndStore(ctx context.Context, email *entities.Email) (*ClassificationResult, error) {
+	return classifyAndStore(ctx, ep.emailClassifier, ep.emailRepo, email)
+}
+
+// Close fecha as conexões com o servidor IMAP
 func (ep *EmailProcessor) Close() error {
-	return ep.imapClient.Logout()
+	ep.mu.Lock()
+	imapClient, pollClient := ep.imapClient, ep.pollClient
+	ep.mu.Unlock()
+
+	if pollClient != nil {
+		if err := pollClient.Logout(); err != nil {
+			log.Printf("Erro ao encerrar conexão de polling: %v", err)
+		}
+	}
+	return imapClient.Logout()
 }
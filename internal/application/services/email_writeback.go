@@ -0,0 +1,154 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	"github.com/emersion/go-imap/utf7"
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"golang.org/x/text/unicode/norm"
+)
+
+// WriteBackConfig define como o resultado da classificação é refletido na caixa de correio
+type WriteBackConfig struct {
+	Enabled              bool
+	MoveToCategoryFolder bool   // Move a mensagem para uma pasta por categoria
+	FolderPrefix         string // Ex: "EmailFilter/" resulta em "EmailFilter/financeiro"
+	SetKeywords          bool   // Define keywords IMAP a partir das labels
+	FlagHighPriority     bool   // Marca emails de prioridade alta com \Flagged
+	UseGmailLabels       bool   // Usa X-GM-LABELS quando o servidor for Gmail
+}
+
+// writeBack aplica flags, keywords, labels e pastas de acordo com a classificação.
+// A movimentação é feita por último, pois altera o UID da mensagem.
+func (ep *EmailProcessor) writeBack(c *client.Client, uid uint32, result *ClassificationResult) error {
+	cfg := ep.config.WriteBack
+	if !cfg.Enabled {
+		return nil
+	}
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uid)
+
+	// Flags e keywords
+	var flags []interface{}
+	if cfg.FlagHighPriority && result.Priority == entities.PriorityHigh {
+		flags = append(flags, imap.FlaggedFlag)
+	}
+	if cfg.SetKeywords {
+		for _, label := range result.Labels {
+			if keyword := imapKeyword(label); keyword != "" {
+				flags = append(flags, keyword)
+			}
+		}
+	}
+	if len(flags) > 0 {
+		item := imap.FormatFlagsOp(imap.AddFlags, true)
+		if err := c.UidStore(seqset, item, flags, nil); err != nil {
+			return fmt.Errorf("erro ao definir flags: %v", err)
+		}
+	}
+
+	// No Gmail pastas são labels: aplicar labels em vez de mover a mensagem
+	if cfg.UseGmailLabels && ep.gmail {
+		var labels []interface{}
+		if result.Category != "" {
+			labels = append(labels, gmailLabel(ep.categoryFolder(result.Category)))
+		}
+		for _, label := range result.Labels {
+			labels = append(labels, gmailLabel(cfg.FolderPrefix+label))
+		}
+		if len(labels) == 0 {
+			return nil
+		}
+		if err := c.UidStore(seqset, imap.StoreItem("+X-GM-LABELS.SILENT"), labels, nil); err != nil {
+			return fmt.Errorf("erro ao aplicar labels do Gmail: %v", err)
+		}
+		return nil
+	}
+
+	if cfg.MoveToCategoryFolder && result.Category != "" {
+		folder := ep.categoryFolder(result.Category)
+		if err := ep.ensureFolder(c, folder); err != nil {
+			return err
+		}
+		if err := c.UidMove(seqset, folder); err != nil {
+			return fmt.Errorf("erro ao mover email para %s: %v", folder, err)
+		}
+	}
+
+	return nil
+}
+
+// categoryFolder retorna o nome da pasta correspondente à categoria
+func (ep *EmailProcessor) categoryFolder(category string) string {
+	return ep.config.WriteBack.FolderPrefix + category
+}
+
+// ensureFolder cria a pasta no servidor caso ainda não exista
+func (ep *EmailProcessor) ensureFolder(c *client.Client, name string) error {
+	ep.mu.Lock()
+	known := ep.knownFolders[name]
+	ep.mu.Unlock()
+	if known {
+		return nil
+	}
+
+	mailboxes := make(chan *imap.MailboxInfo, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.List("", name, mailboxes)
+	}()
+
+	exists := false
+	for m := range mailboxes {
+		if m.Name == name {
+			exists = true
+		}
+	}
+	if err := <-done; err != nil {
+		return fmt.Errorf("erro ao listar pasta %s: %v", name, err)
+	}
+
+	if !exists {
+		if err := c.Create(name); err != nil {
+			return fmt.Errorf("erro ao criar pasta %s: %v", name, err)
+		}
+	}
+
+	ep.mu.Lock()
+	ep.knownFolders[name] = true
+	ep.mu.Unlock()
+	return nil
+}
+
+// imapKeyword converte uma label em um atom válido para keyword IMAP (RFC 3501),
+// removendo acentos e substituindo caracteres não permitidos
+func imapKeyword(label string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(label) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Descartar marcas de acentuação
+		case r > 0x20 && r < 0x7f && !strings.ContainsRune(`(){%*"\]`, r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// gmailLabel codifica a label em UTF-7 modificado e a envia como quoted string
+func gmailLabel(label string) imap.RawString {
+	encoded, err := utf7.Encoding.NewEncoder().String(label)
+	if err != nil {
+		encoded = label
+	}
+	encoded = strings.ReplaceAll(encoded, `\`, `\\`)
+	encoded = strings.ReplaceAll(encoded, `"`, `\"`)
+	return imap.RawString(`"` + encoded + `"`)
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// EventPublisher publica eventos de domínio para as integrações do tenant; os
+// publicadores são registrados como sinks no dispatcher do outbox
+type EventPublisher interface {
+	Publish(ctx context.Context, event *entities.Event) error
+}
+
+// TargetPublisher publicador que entrega cada evento a vários destinos, como os
+// canais de chat do tenant. O dispatcher do outbox registra na entrada os
+// destinos já concluídos, para que a nova tentativa após uma falha parcial
+// não repita o evento neles.
+type TargetPublisher interface {
+	EventPublisher
+	// PublishTargets entrega o evento aos destinos para os quais delivered
+	// retorna falso, chamando done ao concluir cada um, e retorna as falhas
+	// depois de tentar todos. delivered e done devem ser chamados durante a
+	// própria chamada.
+	PublishTargets(ctx context.Context, event *entities.Event, delivered func(target string) bool, done func(target string)) error
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/memory"
+)
+
+// testStore repositórios em memória com um tenant e um usuário já criados
+type testStore struct {
+	store    *memory.Store
+	tenantID string
+	userID   string
+	tenants  *memory.TenantRepository
+	users    *memory.UserRepository
+	emails   *memory.EmailRepository
+}
+
+func newTestStore(t *testing.T) *testStore {
+	t.Helper()
+	ctx := context.Background()
+	store := memory.NewStore()
+	tenants, users := memory.NewTenantRepository(store), memory.NewUserRepository(store)
+
+	tenant := &entities.Tenant{Name: "Acme", Plan: "free", Active: true}
+	if err := tenants.Create(ctx, tenant); err != nil {
+		t.Fatal(err)
+	}
+	user := &entities.User{TenantID: tenant.ID, Email: "ana@acme.test", Name: "Ana", Role: "admin", Active: true}
+	if err := users.Create(entities.WithTenant(ctx, tenant.ID), user); err != nil {
+		t.Fatal(err)
+	}
+
+	return &testStore{
+		store:    store,
+		tenantID: tenant.ID,
+		userID:   user.ID,
+		tenants:  tenants,
+		users:    users,
+		emails:   memory.NewEmailRepository(store),
+	}
+}
+
+// ctx retorna um contexto escopado ao tenant do teste
+func (ts *testStore) ctx() context.Context {
+	return entities.WithTenant(context.Background(), ts.tenantID)
+}
+
+// listEmails retorna os emails salvos do tenant
+func (ts *testStore) listEmails(t *testing.T) []*entities.Email {
+	t.Helper()
+	filter := &entities.EmailFilter{Page: entities.Page{PageSize: 100}}
+	result, err := ts.emails.ListByTenant(ts.ctx(), ts.tenantID, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.Items
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var (
+	ErrUnknownRecipient = errors.New("destinatário desconhecido")
+	ErrRecipientDomain  = errors.New("domínio de destino não atendido")
+)
+
+// TenantLookup consulta tenants para validação de destinatários
+type TenantLookup interface {
+	GetByID(ctx context.Context, id string) (*entities.Tenant, error)
+}
+
+// UserLookup consulta usuários para validação de destinatários
+type UserLookup interface {
+	GetByID(ctx context.Context, id string) (*entities.User, error)
+	ListByTenant(ctx context.Context, tenantID string) ([]*entities.User, error)
+}
+
+// InboundService recebe emails encaminhados para endereços por tenant
+// (<tenant>@domínio ou <tenant>+<usuário>@domínio), classifica e armazena.
+// É compartilhado pelo receptor SMTP/LMTP e pelos webhooks de provedores.
+type InboundService struct {
+	domain          string
+	emailClassifier *EmailClassifier
+	emailRepo       entities.EmailRepository
+	tenants         TenantLookup
+	users           UserLookup
+}
+
+// NewInboundService cria uma nova instância do serviço de recebimento
+func NewInboundService(domain string, classifier *EmailClassifier, repo entities.EmailRepository, tenants TenantLookup, users UserLookup) *InboundService {
+	if domain == "" {
+		domain = "in.emailfilter"
+	}
+	return &InboundService{
+		domain:          domain,
+		emailClassifier: classifier,
+		emailRepo:       repo,
+		tenants:         tenants,
+		users:           users,
+	}
+}
+
+// Domain retorna o domínio aceito nos destinatários
+func (s *InboundService) Domain() string {
+	return s.domain
+}
+
+// Deliver classifica e armazena uma cópia do email para cada destinatário válido.
+// Destinatários fora do domínio ou desconhecidos são ignorados.
+func (s *InboundService) Deliver(ctx context.Context, email *entities.Email, recipients []string) (int, error) {
+	delivered := 0
+	for _, address := range recipients {
+		tenantID, userID, err := s.ResolveRecipient(ctx, address)
+		if err != nil {
+			continue
+		}
+
+		msg := *email
+		if err := s.deliverTo(ctx, &msg, tenantID, userID); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+
+	if delivered == 0 {
+		return 0, ErrUnknownRecipient
+	}
+	return delivered, nil
+}
+
+// deliverTo classifica e armazena o email para um destinatário já validado
+func (s *InboundService) deliverTo(ctx context.Context, email *entities.Email, tenantID, userID string) error {
+	email.TenantID = tenantID
+	email.UserID = userID
+	if email.Folder == "" {
+		email.Folder = "INBOX"
+	}
+	_, err := classifyAndStore(ctx, s.emailClassifier, s.emailRepo, email)
+	return err
+}
+
+// ResolveRecipient valida o endereço e retorna o tenant e o usuário de destino.
+// Sem usuário explícito, a mensagem é entregue ao primeiro administrador ativo.
+func (s *InboundService) ResolveRecipient(ctx context.Context, address string) (tenantID, userID string, err error) {
+	at := strings.LastIndex(address, "@")
+	if at <= 0 {
+		return "", "", ErrUnknownRecipient
+	}
+	local, domain := address[:at], address[at+1:]
+	if !strings.EqualFold(domain, s.domain) {
+		return "", "", ErrRecipientDomain
+	}
+
+	tenantID, userID, _ = strings.Cut(local, "+")
+	tenant, err := s.tenants.GetByID(ctx, tenantID)
+	if err != nil || !tenant.Active {
+		return "", "", ErrUnknownRecipient
+	}
+	ctx = entities.WithTenant(ctx, tenant.ID)
+
+	if userID != "" {
+		user, err := s.users.GetByID(ctx, userID)
+		if err != nil || user.TenantID != tenant.ID || !user.Active {
+			return "", "", ErrUnknownRecipient
+		}
+		return tenant.ID, user.ID, nil
+	}
+
+	user, err := defaultRecipient(ctx, s.users, tenant.ID)
+	if err != nil {
+		log.Printf("Erro ao resolver destinatário %s: %v", address, err)
+		return "", "", ErrUnknownRecipient
+	}
+	return tenant.ID, user.ID, nil
+}
+
+// defaultRecipient escolhe o usuário que recebe emails endereçados ao tenant
+func defaultRecipient(ctx context.Context, users UserLookup, tenantID string) (*entities.User, error) {
+	list, err := users.ListByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fallback *entities.User
+	for _, u := range list {
+		if !u.Active {
+			continue
+		}
+		if u.Role == "admin" {
+			return u, nil
+		}
+		if fallback == nil {
+			fallback = u
+		}
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("tenant %s não possui usuários ativos", tenantID)
+	}
+	return fallback, nil
+}
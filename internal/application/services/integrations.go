@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/chat"
+)
+
+var _ TargetPublisher = (*IntegrationService)(nil)
+
+// chatSender envio das mensagens às integrações
+type chatSender interface {
+	Send(ctx context.Context, kind entities.IntegrationKind, url string, msg *chat.Message) error
+}
+
+// IntegrationService notifica as integrações de Slack e Teams do tenant sobre
+// os emails classificados que atendem aos critérios de cada uma
+type IntegrationService struct {
+	repo   entities.IntegrationRepository
+	client chatSender
+	appURL string
+}
+
+// NewIntegrationService cria o serviço de integrações; appURL é a base dos
+// links para os emails e pode ser vazia
+func NewIntegrationService(repo entities.IntegrationRepository, appURL string) *IntegrationService {
+	return &IntegrationService{
+		repo:   repo,
+		client: chat.NewClient(10 * time.Second),
+		appURL: appURL,
+	}
+}
+
+// Publish envia a notificação de email.classified a todas as integrações
+// ativas cujos critérios o email atende, retornando as falhas de envio
+func (s *IntegrationService) Publish(ctx context.Context, event *entities.Event) error {
+	return s.PublishTargets(ctx, event, func(string) bool { return false }, func(string) {})
+}
+
+// PublishTargets envia a notificação de email.classified às integrações
+// ativas cujos critérios o email atende e que ainda não a receberam, com o ID
+// da integração como destino; os demais eventos são ignorados. Uma falha não
+// impede o envio às demais integrações e é retornada ao final, para que o
+// outbox tente de novo apenas nos canais que não receberam a mensagem.
+func (s *IntegrationService) PublishTargets(ctx context.Context, event *entities.Event, delivered func(string) bool, done func(string)) error {
+	if event.Type != entities.EventEmailClassified {
+		return nil
+	}
+	var email entities.EmailSummary
+	if err := json.Unmarshal(event.Data, &email); err != nil {
+		return fmt.Errorf("erro ao ler email do evento %s: %v", event.ID, err)
+	}
+
+	ctx = entities.WithTenant(ctx, event.TenantID)
+	integrations, err := s.repo.ListActive(ctx, event.TenantID)
+	if err != nil {
+		return err
+	}
+
+	msg := chat.EmailMessage(&email, s.emailURL(email.ID))
+	var errs []error
+	for _, i := range integrations {
+		if delivered(i.ID) || !i.Criteria.Matches(&email) {
+			continue
+		}
+		if err := s.client.Send(ctx, i.Kind, i.WebhookURL, msg); err != nil {
+			errs = append(errs, fmt.Errorf("integração %s: %v", i.ID, err))
+			continue
+		}
+		done(i.ID)
+	}
+	return errors.Join(errs...)
+}
+
+// Test envia uma mensagem de exemplo para conferir a configuração da integração
+func (s *IntegrationService) Test(ctx context.Context, integration *entities.Integration) error {
+	msg := &chat.Message{
+		Title:    "Teste de integração",
+		Subject:  "Mensagem de teste do Email Filter",
+		From:     "email-filter",
+		Priority: entities.PriorityHigh,
+		Category: "suporte",
+		Tasks:    []entities.Task{{Description: "Conferir se esta mensagem chegou ao canal"}},
+		URL:      s.emailURL(""),
+	}
+	return s.client.Send(ctx, integration.Kind, integration.WebhookURL, msg)
+}
+
+// emailURL link para o email no dashboard, ou para o dashboard quando id é vazio
+func (s *IntegrationService) emailURL(id string) string {
+	if s.appURL == "" {
+		return ""
+	}
+	if id == "" {
+		return s.appURL + "/dashboard"
+	}
+	return s.appURL + "/dashboard?email=" + url.QueryEscape(id)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/chat"
+	"github.com/enzo010/email-filter/internal/infrastructure/memory"
+)
+
+// flakySender registra os envios por URL e recusa os destinados a down
+type flakySender struct {
+	mu   sync.Mutex
+	down string
+	sent map[string]int
+}
+
+func (s *flakySender) Send(ctx context.Context, kind entities.IntegrationKind, url string, msg *chat.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if url == s.down {
+		return errors.New("canal indisponível")
+	}
+	if s.sent == nil {
+		s.sent = make(map[string]int)
+	}
+	s.sent[url]++
+	return nil
+}
+
+// newIntegrationService cria o serviço com uma integração do Slack e uma do
+// Teams no tenant do teste, enviando por sender
+func (ts *testStore) newIntegrationService(t *testing.T, sender chatSender) (svc *IntegrationService, slack, teams *entities.Integration) {
+	t.Helper()
+	repo := memory.NewIntegrationRepository(ts.store)
+	slack = &entities.Integration{TenantID: ts.tenantID, Kind: entities.IntegrationSlack, Name: "vendas", WebhookURL: "https://hooks.slack.test/a", Active: true}
+	teams = &entities.Integration{TenantID: ts.tenantID, Kind: entities.IntegrationTeams, Name: "suporte", WebhookURL: "https://teams.test/b", Active: true}
+	for _, i := range []*entities.Integration{slack, teams} {
+		if err := repo.Create(ts.ctx(), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc = NewIntegrationService(repo, "")
+	svc.client = sender
+	return svc, slack, teams
+}
+
+func TestIntegrationServicePublishReturnsSendErrors(t *testing.T) {
+	ts := newTestStore(t)
+	sender := &flakySender{down: "https://teams.test/b"}
+	svc, slack, teams := ts.newIntegrationService(t, sender)
+
+	event, err := entities.NewEvent(ts.tenantID, entities.EventEmailClassified, entities.EmailSummary{ID: "e1", Subject: "Contrato"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var done []string
+	err = svc.PublishTargets(context.Background(), event, func(string) bool { return false }, func(id string) { done = append(done, id) })
+	if err == nil || !strings.Contains(err.Error(), teams.ID) {
+		t.Fatalf("err = %v, esperada a falha da integração %s", err, teams.ID)
+	}
+	if len(done) != 1 || done[0] != slack.ID {
+		t.Errorf("integrações concluídas = %v, esperado [%s]", done, slack.ID)
+	}
+	if err := svc.Publish(context.Background(), event); err == nil {
+		t.Error("Publish não retornou a falha de envio")
+	}
+}
+
+func TestOutboxDispatcherSkipsDeliveredIntegrations(t *testing.T) {
+	ts := newTestStore(t)
+	clock := newFakeClock(time.Now().UTC().Add(time.Minute))
+	ts.createTask(t, "Contrato", clock.Now().Add(time.Hour))
+
+	sender := &flakySender{down: "https://teams.test/b"}
+	svc, slack, teams := ts.newIntegrationService(t, sender)
+	d := NewOutboxDispatcher(memory.NewOutboxRepository(ts.store), clock, &OutboxConfig{RetryBase: time.Second, RetryMax: time.Minute})
+	d.Register("integrations", svc)
+
+	if published, err := d.RunOnce(context.Background()); err != nil || published != 0 {
+		t.Fatalf("primeira tentativa: publicadas %d, err %v; esperada a falha do Teams", published, err)
+	}
+
+	// Com o Teams de volta, a nova tentativa envia apenas a ele
+	sender.down = ""
+	clock.Advance(time.Minute)
+	if published, err := d.RunOnce(context.Background()); err != nil || published == 0 {
+		t.Fatalf("nova tentativa: publicadas %d, err %v", published, err)
+	}
+	if got := sender.sent[slack.WebhookURL]; got != 1 {
+		t.Errorf("Slack recebeu %d mensagens, esperado 1", got)
+	}
+	if got := sender.sent[teams.WebhookURL]; got != 1 {
+		t.Errorf("Teams recebeu %d mensagens, esperado 1", got)
+	}
+}
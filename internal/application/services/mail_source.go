@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// MailSource fonte de emails (IMAP, POP3, ...) que alimenta o fluxo comum de
+// classificação e armazenamento
+type MailSource interface {
+	StartProcessing(ctx context.Context) error
+	Close() error
+}
+
+var (
+	_ MailSource = (*EmailProcessor)(nil)
+	_ MailSource = (*POP3Processor)(nil)
+)
+
+// classifyAndStore classifica o email e o salva no repositório
+func classifyAndStore(ctx context.Context, classifier *EmailClassifier, repo entities.EmailRepository, email *entities.Email) (*ClassificationResult, error) {
+	// O repositório opera apenas dentro do tenant do contexto
+	ctx = entities.WithTenant(ctx, email.TenantID)
+
+	// Classificar email
+	result, err := classifier.ClassifyEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao classificar email: %v", err)
+	}
+
+	ApplyClassification(email, result)
+
+	// Salvar no banco de dados; mensagens já armazenadas são ignoradas
+	if _, err := repo.Create(ctx, email); err != nil {
+		return nil, fmt.Errorf("erro ao salvar email: %v", err)
+	}
+
+	return result, nil
+}
+
+// ApplyClassification copia o resultado da classificação para o email
+func ApplyClassification(email *entities.Email, result *ClassificationResult) {
+	email.Priority = result.Priority
+	email.Category = result.Category
+	email.Labels = result.Labels
+	email.Tasks = result.SuggestedTasks
+	email.ProcessedAt = time.Now()
+}
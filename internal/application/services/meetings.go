@@ -0,0 +1,57 @@
+package services
+
+import (
+	"slices"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+const (
+	// meetingLabel label dos emails com convite de reunião, a mesma do classificador
+	meetingLabel = "reunião"
+	// meetingSoon antecedência a partir da qual a reunião eleva a prioridade
+	meetingSoon = 24 * time.Hour
+	// meetingPrepLead antecedência do prazo da tarefa de preparação
+	meetingPrepLead = time.Hour
+)
+
+// applyMeeting ajusta a classificação de emails com convite de reunião: marca a
+// label, cria a tarefa de preparação para reuniões futuras e eleva a
+// prioridade quando a reunião é nas próximas 24h. Apenas convites (METHOD
+// REQUEST) geram a tarefa; cancelamentos recebem só a label e o repositório
+// cancela as tarefas das versões anteriores. Respostas são ignoradas.
+func applyMeeting(result *ClassificationResult, meeting *entities.Meeting, now time.Time) {
+	if meeting == nil || !meeting.Scheduling() {
+		return
+	}
+	if !slices.Contains(result.Labels, meetingLabel) {
+		result.Labels = append(result.Labels, meetingLabel)
+	}
+	if meeting.Method != entities.MeetingRequest || meeting.Cancelled() || !meeting.Start.After(now) {
+		return
+	}
+
+	priority := entities.PriorityMedium
+	if meeting.Start.Sub(now) <= meetingSoon {
+		priority = entities.PriorityHigh
+		result.Priority = entities.PriorityHigh
+	}
+
+	// Prazo uma hora antes do início ou, se já passou, no próprio início
+	due := meeting.Start.Add(-meetingPrepLead)
+	if due.Before(now) {
+		due = meeting.Start
+	}
+
+	summary := meeting.Summary
+	if summary == "" {
+		summary = "sem título"
+	}
+	result.SuggestedTasks = append(result.SuggestedTasks, entities.Task{
+		Description: entities.MeetingPrepTask + summary,
+		DueDate:     due,
+		Priority:    priority,
+		Status:      entities.TaskPending,
+	})
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+func TestApplyMeeting(t *testing.T) {
+	now := time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+	meeting := func(method entities.MeetingMethod, status entities.MeetingStatus, start time.Time) *entities.Meeting {
+		return &entities.Meeting{UID: "reuniao-1", Method: method, Status: status, Summary: "Planejamento", Start: start, End: start.Add(time.Hour)}
+	}
+
+	tests := []struct {
+		name     string
+		meeting  *entities.Meeting
+		label    bool
+		task     bool
+		due      time.Time
+		priority entities.Priority // Prioridade da tarefa e, se alta, do email
+	}{
+		{"sem convite", nil, false, false, time.Time{}, ""},
+		{"request em mais de 24h", meeting(entities.MeetingRequest, entities.MeetingConfirmed, now.Add(72*time.Hour)), true, true, now.Add(71 * time.Hour), entities.PriorityMedium},
+		{"request nas próximas 24h", meeting(entities.MeetingRequest, entities.MeetingConfirmed, now.Add(3*time.Hour)), true, true, now.Add(2 * time.Hour), entities.PriorityHigh},
+		{"request em menos de 1h", meeting(entities.MeetingRequest, entities.MeetingConfirmed, now.Add(30*time.Minute)), true, true, now.Add(30 * time.Minute), entities.PriorityHigh},
+		{"request já iniciada", meeting(entities.MeetingRequest, entities.MeetingConfirmed, now.Add(-time.Hour)), true, false, time.Time{}, ""},
+		{"request com status cancelado", meeting(entities.MeetingRequest, entities.MeetingCancelled, now.Add(3*time.Hour)), true, false, time.Time{}, ""},
+		{"cancel", meeting(entities.MeetingCancel, entities.MeetingCancelled, now.Add(3*time.Hour)), true, false, time.Time{}, ""},
+		{"reply", meeting("REPLY", entities.MeetingConfirmed, now.Add(3*time.Hour)), false, false, time.Time{}, ""},
+		{"counter", meeting("COUNTER", entities.MeetingConfirmed, now.Add(3*time.Hour)), false, false, time.Time{}, ""},
+		{"publish", meeting("PUBLISH", entities.MeetingConfirmed, now.Add(3*time.Hour)), false, false, time.Time{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ClassificationResult{Priority: entities.PriorityLow, Labels: []string{"trabalho"}}
+			applyMeeting(result, tt.meeting, now)
+
+			if got := len(result.Labels) == 2 && result.Labels[1] == meetingLabel; got != tt.label {
+				t.Errorf("Labels = %v, label de reunião esperada: %v", result.Labels, tt.label)
+			}
+			if !tt.task {
+				if len(result.SuggestedTasks) != 0 || result.Priority != entities.PriorityLow {
+					t.Errorf("tarefas = %v, prioridade = %s; esperado nenhuma alteração", result.SuggestedTasks, result.Priority)
+				}
+				return
+			}
+
+			if len(result.SuggestedTasks) != 1 {
+				t.Fatalf("tarefas = %v, esperada uma", result.SuggestedTasks)
+			}
+			task := result.SuggestedTasks[0]
+			if task.Description != entities.MeetingPrepTask+"Planejamento" || task.Status != entities.TaskPending {
+				t.Errorf("tarefa = %q (%s)", task.Description, task.Status)
+			}
+			if !task.DueDate.Equal(tt.due) || task.Priority != tt.priority {
+				t.Errorf("prazo/prioridade = %s/%s, esperado %s/%s", task.DueDate, task.Priority, tt.due, tt.priority)
+			}
+			wantPriority := entities.PriorityLow
+			if tt.priority == entities.PriorityHigh {
+				wantPriority = entities.PriorityHigh
+			}
+			if result.Priority != wantPriority {
+				t.Errorf("prioridade do email = %s, esperado %s", result.Priority, wantPriority)
+			}
+		})
+	}
+}
+
+func TestParseMessageMeetingMethods(t *testing.T) {
+	tests := []struct {
+		method  string
+		meeting bool
+	}{
+		{"REQUEST", true},
+		{"CANCEL", true},
+		{"REPLY", false},
+		{"COUNTER", false},
+		{"PUBLISH", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			raw := strings.Join([]string{
+				"From: bia@example.com",
+				"To: ana@acme.test",
+				"Subject: Planejamento",
+				"Content-Type: text/calendar; charset=utf-8; method=" + tt.method,
+				"",
+				"BEGIN:VCALENDAR",
+				"METHOD:" + tt.method,
+				"BEGIN:VEVENT",
+				"UID:reuniao-1@example.com",
+				"DTSTART:20240315T140000Z",
+				"END:VEVENT",
+				"END:VCALENDAR",
+				"",
+			}, "\r\n")
+
+			email, err := ParseMessage(strings.NewReader(raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := email.Meeting != nil; got != tt.meeting {
+				t.Errorf("Meeting = %+v, convite esperado: %v", email.Meeting, tt.meeting)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	_ "github.com/emersion/go-message/charset" // Decodificação de charsets além de UTF-8 (ex: ISO-8859-1)
+	"github.com/emersion/go-message/mail"
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/calendar"
+)
+
+var htmlTagPattern = regexp.MustCompile(`(?s)<[^>]*>`)
+
+// ParseMessage converte uma mensagem RFC 822 na entidade de email.
+// O corpo em text/plain é preferido; na ausência dele, o HTML é convertido em texto.
+// Convites de reunião (text/calendar) preenchem email.Meeting.
+func ParseMessage(r io.Reader) (*entities.Email, error) {
+	mr, err := mail.CreateReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler mensagem: %v", err)
+	}
+	defer mr.Close()
+
+	email := &entities.Email{}
+	email.Subject, _ = mr.Header.Subject()
+	email.MessageID, _ = mr.Header.MessageID()
+	if from, err := mr.Header.AddressList("From"); err == nil && len(from) > 0 {
+		email.From = from[0].Address
+	}
+	if to, err := mr.Header.AddressList("To"); err == nil && len(to) > 0 {
+		email.To = to[0].Address
+	}
+
+	var plain, html string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Partes malformadas não impedem a classificação do que já foi lido
+			break
+		}
+
+		var contentType string
+		var params map[string]string
+		var inline bool
+		switch h := p.Header.(type) {
+		case *mail.InlineHeader:
+			contentType, params, _ = h.ContentType()
+			inline = true
+		case *mail.AttachmentHeader:
+			contentType, params, _ = h.ContentType()
+		}
+
+		switch {
+		case inline && (contentType == "text/plain" || contentType == ""):
+			if plain == "" {
+				plain = readPart(p.Body)
+			}
+		case inline && contentType == "text/html":
+			if html == "" {
+				html = readPart(p.Body)
+			}
+		case contentType == "text/calendar" || contentType == "application/ics":
+			// Convites chegam como parte alternativa ou anexo .ics; respostas
+			// (REPLY, COUNTER) e publicações não alteram a agenda e são ignoradas
+			if email.Meeting == nil {
+				if meeting, err := calendar.ParseInvite(p.Body, params["method"]); err == nil && meeting.Scheduling() {
+					email.Meeting = meeting
+				}
+			}
+		}
+	}
+
+	email.Content = plain
+	if email.Content == "" && html != "" {
+		email.Content = htmlToText(html)
+	}
+
+	return email, nil
+}
+
+func readPart(r io.Reader) string {
+	buf := new(strings.Builder)
+	if _, err := io.Copy(buf, r); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// htmlToText remove tags HTML, mantendo apenas o texto
+func htmlToText(html string) string {
+	text := htmlTagPattern.ReplaceAllString(html, " ")
+	return strings.Join(strings.Fields(text), " ")
+}
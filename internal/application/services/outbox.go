@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// outboxLease tempo em que as entradas reservadas ficam fora das verificações;
+// se a instância parar durante a publicação, o tenant volta a ser publicado depois dele
+const outboxLease = 2 * time.Minute
+
+// OutboxConfig configuração do dispatcher do outbox
+type OutboxConfig struct {
+	Interval  time.Duration // Intervalo entre as verificações de entradas pendentes
+	BatchSize int           // Entradas reservadas por verificação
+	RetryBase time.Duration // Espera após a primeira falha; dobra a cada tentativa
+	RetryMax  time.Duration // Espera máxima entre tentativas
+	// MaxAttempts tentativas após as quais a entrada é estacionada, liberando
+	// as seguintes do tenant
+	MaxAttempts int
+}
+
+// outboxSink destino registrado no dispatcher
+type outboxSink struct {
+	name      string
+	publisher EventPublisher
+}
+
+// OutboxDispatcher publica os eventos gravados no outbox nos sinks registrados,
+// pelo menos uma vez. As entradas de um tenant são publicadas em ordem: uma
+// falha interrompe o tenant até a nova tentativa, com backoff exponencial, e
+// os sinks que já receberam a entrada não a recebem de novo. Depois de
+// MaxAttempts falhas a entrada é estacionada e o tenant segue com as demais.
+type OutboxDispatcher struct {
+	repo   entities.OutboxRepository
+	clock  Clock
+	config OutboxConfig
+	sinks  []outboxSink
+}
+
+// NewOutboxDispatcher cria uma nova instância do dispatcher do outbox
+func NewOutboxDispatcher(repo entities.OutboxRepository, clock Clock, config *OutboxConfig) *OutboxDispatcher {
+	cfg := OutboxConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.RetryBase <= 0 {
+		cfg.RetryBase = 5 * time.Second
+	}
+	if cfg.RetryMax <= 0 {
+		cfg.RetryMax = 10 * time.Minute
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 20
+	}
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	return &OutboxDispatcher{repo: repo, clock: clock, config: cfg}
+}
+
+// Register adiciona um sink; name identifica o sink no registro de entregas de
+// cada entrada e deve ser estável entre versões. Deve ser chamado antes de Run.
+func (d *OutboxDispatcher) Register(name string, publisher EventPublisher) {
+	d.sinks = append(d.sinks, outboxSink{name: name, publisher: publisher})
+}
+
+// Run publica as entradas pendentes periodicamente até o contexto ser cancelado
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.RunOnce(ctx); err != nil {
+			log.Printf("Erro na publicação do outbox: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunOnce reserva e publica um lote de entradas, com os tenants em paralelo e
+// as entradas de cada um em ordem, retornando quantas foram publicadas
+func (d *OutboxDispatcher) RunOnce(ctx context.Context) (int, error) {
+	entries, err := d.repo.ClaimDue(ctx, d.clock.Now(), outboxLease, d.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var tenants []string
+	byTenant := make(map[string][]*entities.OutboxEntry)
+	for _, e := range entries {
+		if _, ok := byTenant[e.Event.TenantID]; !ok {
+			tenants = append(tenants, e.Event.TenantID)
+		}
+		byTenant[e.Event.TenantID] = append(byTenant[e.Event.TenantID], e)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	published := 0
+	for _, tenantID := range tenants {
+		wg.Add(1)
+		go func(entries []*entities.OutboxEntry) {
+			defer wg.Done()
+			for _, e := range entries {
+				ok, next := d.publish(ctx, e)
+				if ok {
+					mu.Lock()
+					published++
+					mu.Unlock()
+				}
+				// As entradas seguintes esperam a nova tentativa desta
+				if !next {
+					return
+				}
+			}
+		}(byTenant[tenantID])
+	}
+	wg.Wait()
+	return published, nil
+}
+
+// publish entrega a entrada aos sinks pendentes e a remove do outbox; em caso
+// de falha, registra os sinks concluídos e agenda a nova tentativa ou, com as
+// tentativas esgotadas, estaciona a entrada. Retorna se a entrada foi
+// publicada e se as seguintes do tenant podem prosseguir.
+func (d *OutboxDispatcher) publish(ctx context.Context, e *entities.OutboxEntry) (published, next bool) {
+	var failure error
+	for _, sink := range d.sinks {
+		if !e.Pending(sink.name) {
+			continue
+		}
+		if err := d.publishSink(ctx, sink, e); err != nil {
+			failure = fmt.Errorf("%s: %v", sink.name, err)
+			break
+		}
+		e.Delivered = append(e.Delivered, sink.name)
+	}
+
+	// Registra mesmo com o contexto cancelado, para não repetir a publicação
+	ctx = context.WithoutCancel(ctx)
+	if failure == nil {
+		if err := d.repo.Complete(ctx, e.ID); err != nil {
+			log.Printf("Erro ao concluir evento %s do outbox: %v", e.Event.ID, err)
+			return false, false
+		}
+		return true, true
+	}
+
+	now := d.clock.Now()
+	e.Attempts++
+	e.LastError = failure.Error()
+	e.NextAttemptAt = now.Add(backoffDelay(d.config.RetryBase, d.config.RetryMax, e.Attempts))
+	if e.Attempts >= d.config.MaxAttempts {
+		e.ParkedAt = &now
+	}
+	if err := d.repo.RecordFailure(ctx, e); err != nil {
+		log.Printf("Erro ao registrar falha do evento %s do outbox: %v", e.Event.ID, err)
+		return false, false
+	}
+	if e.ParkedAt != nil {
+		log.Printf("Evento %s (%s) do tenant %s estacionado após %d tentativas: %v",
+			e.Event.ID, e.Event.Type, e.Event.TenantID, e.Attempts, failure)
+		return false, true
+	}
+	log.Printf("Falha ao publicar evento %s (%s) do tenant %s, tentativa %d: %v",
+		e.Event.ID, e.Event.Type, e.Event.TenantID, e.Attempts, failure)
+	return false, false
+}
+
+// publishSink entrega a entrada ao sink. Destinos concluídos de um
+// TargetPublisher ficam registrados como "sink:destino" na entrada, junto aos
+// sinks concluídos.
+func (d *OutboxDispatcher) publishSink(ctx context.Context, sink outboxSink, e *entities.OutboxEntry) error {
+	targets, ok := sink.publisher.(TargetPublisher)
+	if !ok {
+		return sink.publisher.Publish(ctx, e.Event)
+	}
+	key := func(target string) string { return sink.name + ":" + target }
+	return targets.PublishTargets(ctx, e.Event,
+		func(target string) bool { return !e.Pending(key(target)) },
+		func(target string) { e.Delivered = append(e.Delivered, key(target)) },
+	)
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/memory"
+)
+
+// stuckSink sink que sempre falha no primeiro evento recebido e aceita os demais
+type stuckSink struct {
+	mu        sync.Mutex
+	stuck     string
+	attempts  int
+	published []string
+}
+
+func (s *stuckSink) Publish(ctx context.Context, event *entities.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stuck == "" {
+		s.stuck = event.ID
+	}
+	if event.ID == s.stuck {
+		s.attempts++
+		return errors.New("sink indisponível")
+	}
+	s.published = append(s.published, event.ID)
+	return nil
+}
+
+func TestOutboxDispatcherParksExhaustedEntry(t *testing.T) {
+	ts := newTestStore(t)
+	clock := newFakeClock(time.Now().UTC().Add(time.Minute))
+	ts.createTask(t, "Primeiro email", clock.Now().Add(time.Hour))
+	ts.createTask(t, "Segundo email", clock.Now().Add(time.Hour))
+
+	sink := &stuckSink{}
+	d := NewOutboxDispatcher(memory.NewOutboxRepository(ts.store), clock, &OutboxConfig{RetryBase: time.Second, RetryMax: time.Minute, MaxAttempts: 3})
+	d.Register("test", sink)
+
+	run := func() int {
+		t.Helper()
+		published, err := d.RunOnce(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return published
+	}
+
+	// As duas primeiras falhas seguram o tenant: nada é publicado depois da entrada com falha
+	for attempt := 1; attempt < 3; attempt++ {
+		if published := run(); published != 0 {
+			t.Fatalf("tentativa %d publicou %d entradas atrás da entrada com falha", attempt, published)
+		}
+		clock.Advance(time.Minute)
+	}
+
+	// A terceira falha estaciona a entrada e libera as seguintes do tenant
+	if published := run(); published != 3 {
+		t.Fatalf("publicadas %d entradas após estacionar, esperado 3", published)
+	}
+	clock.Advance(time.Hour)
+	if published := run(); published != 0 {
+		t.Errorf("publicadas %d entradas depois de esvaziar a fila", published)
+	}
+	if sink.attempts != 3 {
+		t.Errorf("entrada estacionada tentada %d vezes, esperado 3", sink.attempts)
+	}
+	if len(sink.published) != 3 {
+		t.Errorf("eventos publicados = %v, esperado 3", sink.published)
+	}
+}
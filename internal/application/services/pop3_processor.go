@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/pop3"
+)
+
+// POP3Config configuração para conexão com servidor POP3
+type POP3Config struct {
+	Server   string
+	Port     int
+	Username string
+	Password string
+	SSL      bool
+	TenantID string
+	UserID   string
+
+	PollInterval        time.Duration // Intervalo entre verificações da caixa
+	DeleteAfterDownload bool          // Remove a mensagem do servidor após salvá-la
+
+	// Dial permite substituir a conexão de rede (ex: servidor POP3 em memória)
+	Dial func() (net.Conn, error)
+}
+
+// POP3Processor responsável por baixar e processar emails via POP3
+type POP3Processor struct {
+	emailClassifier *EmailClassifier
+	emailRepo       entities.EmailRepository
+	seenRepo        entities.SeenMessageRepository
+	config          *POP3Config
+}
+
+// NewPOP3Processor cria uma nova instância do processador POP3
+func NewPOP3Processor(config *POP3Config, classifier *EmailClassifier, repo entities.EmailRepository, seenRepo entities.SeenMessageRepository) (*POP3Processor, error) {
+	pp := &POP3Processor{
+		emailClassifier: classifier,
+		emailRepo:       repo,
+		seenRepo:        seenRepo,
+		config:          config,
+	}
+
+	// Validar credenciais antes de iniciar o polling
+	c, err := pp.connect()
+	if err != nil {
+		return nil, err
+	}
+	c.Quit()
+
+	return pp, nil
+}
+
+// StartProcessing processa a caixa imediatamente e depois a cada PollInterval
+func (pp *POP3Processor) StartProcessing(ctx context.Context) error {
+	if err := pp.processMailbox(ctx); err != nil {
+		return err
+	}
+
+	interval := pp.config.PollInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := pp.processMailbox(ctx); err != nil {
+					log.Printf("Erro ao processar caixa POP3 %s: %v", pp.account(), err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// processMailbox abre uma sessão, processa as mensagens ainda não vistas e a encerra.
+// No POP3 as remoções só são efetivadas no QUIT.
+func (pp *POP3Processor) processMailbox(ctx context.Context) error {
+	c, err := pp.connect()
+	if err != nil {
+		return err
+	}
+
+	messages, err := c.Uidl()
+	if err != nil {
+		c.Close()
+		return fmt.Errorf("erro ao listar mensagens: %v", err)
+	}
+
+	account := pp.account()
+	for _, msg := range messages {
+		if ctx.Err() != nil {
+			break
+		}
+
+		seen, err := pp.seenRepo.IsSeen(ctx, account, msg.UID)
+		if err != nil {
+			c.Close()
+			return err
+		}
+		if seen {
+			// Já salva, mas a remoção pode não ter sido efetivada: um DELE
+			// só vale após o QUIT e a sessão anterior pode ter caído antes
+			if pp.config.DeleteAfterDownload {
+				if err := c.Dele(msg.Number); err != nil {
+					log.Printf("Erro ao remover mensagem %s da caixa POP3 %s: %v", msg.UID, account, err)
+				}
+			}
+			continue
+		}
+
+		if err := pp.processMessage(ctx, c, msg); err != nil {
+			log.Printf("Erro ao processar mensagem %s da caixa POP3 %s: %v", msg.UID, account, err)
+		}
+	}
+
+	if err := c.Quit(); err != nil {
+		return fmt.Errorf("erro ao encerrar sessão POP3: %v", err)
+	}
+	return nil
+}
+
+// processMessage baixa, classifica e salva uma única mensagem
+func (pp *POP3Processor) processMessage(ctx context.Context, c *pop3.Client, msg pop3.Message) error {
+	body, err := c.Retr(msg.Number)
+	if err != nil {
+		return fmt.Errorf("erro ao baixar mensagem: %v", err)
+	}
+	// A resposta precisa ser consumida por completo antes do próximo comando
+	defer io.Copy(io.Discard, body)
+
+	email, err := ParseMessage(body)
+	if err != nil {
+		return err
+	}
+	email.TenantID = pp.config.TenantID
+	email.UserID = pp.config.UserID
+	email.Folder = "INBOX"
+
+	if _, err := classifyAndStore(ctx, pp.emailClassifier, pp.emailRepo, email); err != nil {
+		return err
+	}
+
+	if err := pp.seenRepo.MarkSeen(ctx, pp.account(), msg.UID); err != nil {
+		return err
+	}
+
+	if pp.config.DeleteAfterDownload {
+		if err := c.Dele(msg.Number); err != nil {
+			return fmt.Errorf("erro ao remover mensagem do servidor: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// connect abre e autentica uma sessão POP3
+func (pp *POP3Processor) connect() (*pop3.Client, error) {
+	var c *pop3.Client
+	var err error
+	if pp.config.Dial != nil {
+		var conn net.Conn
+		if conn, err = pp.config.Dial(); err == nil {
+			c, err = pop3.NewClient(conn)
+		}
+	} else {
+		addr := fmt.Sprintf("%s:%d", pp.config.Server, pp.config.Port)
+		c, err = pop3.Dial(addr, pp.config.SSL, 30*time.Second)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao servidor POP3: %v", err)
+	}
+
+	if err := c.Auth(pp.config.Username, pp.config.Password); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("erro no login: %v", err)
+	}
+
+	return c, nil
+}
+
+// account identifica a caixa para o registro de UIDLs processados
+func (pp *POP3Processor) account() string {
+	return fmt.Sprintf("pop3:%s:%s@%s", pp.config.TenantID, pp.config.Username, pp.config.Server)
+}
+
+// Close não mantém conexões abertas entre verificações
+func (pp *POP3Processor) Close() error {
+	return nil
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakePOP3Server servidor POP3 em memória com uma caixa fixa de mensagens
+type fakePOP3Server struct {
+	mu       sync.Mutex
+	messages map[string]string // UIDL -> mensagem
+	order    []string
+	deleted  map[string]bool
+	sessions int
+
+	dropNextQuit bool // Encerra a próxima sessão no QUIT sem efetivar as remoções
+}
+
+func newFakePOP3Server() *fakePOP3Server {
+	return &fakePOP3Server{messages: make(map[string]string), deleted: make(map[string]bool)}
+}
+
+func (s *fakePOP3Server) add(uid, raw string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages[uid] = raw
+	s.order = append(s.order, uid)
+}
+
+// dial abre uma sessão com o servidor sobre um net.Pipe
+func (s *fakePOP3Server) dial() (net.Conn, error) {
+	client, server := net.Pipe()
+	go s.serve(server)
+	return client, nil
+}
+
+func (s *fakePOP3Server) serve(conn net.Conn) {
+	defer conn.Close()
+	s.mu.Lock()
+	s.sessions++
+	// A sessão enxerga as mensagens presentes na abertura, como no POP3
+	uids := append([]string(nil), s.order...)
+	s.mu.Unlock()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	reply := func(format string, args ...interface{}) {
+		fmt.Fprintf(w, format+"\r\n", args...)
+		w.Flush()
+	}
+
+	pending := make(map[string]bool)
+	reply("+OK fake POP3")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch strings.ToUpper(cmd) {
+		case "USER":
+			reply("+OK")
+		case "PASS":
+			if arg != "secret" {
+				reply("-ERR senha inválida")
+				continue
+			}
+			reply("+OK logged in")
+		case "UIDL":
+			reply("+OK")
+			for i, uid := range uids {
+				fmt.Fprintf(w, "%d %s\r\n", i+1, uid)
+			}
+			reply(".")
+		case "RETR":
+			var n int
+			fmt.Sscanf(arg, "%d", &n)
+			if n < 1 || n > len(uids) {
+				reply("-ERR mensagem inexistente")
+				continue
+			}
+			s.mu.Lock()
+			raw := s.messages[uids[n-1]]
+			s.mu.Unlock()
+			reply("+OK")
+			for _, l := range strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n") {
+				if strings.HasPrefix(l, ".") {
+					l = "." + l
+				}
+				fmt.Fprintf(w, "%s\r\n", l)
+			}
+			reply(".")
+		case "DELE":
+			var n int
+			fmt.Sscanf(arg, "%d", &n)
+			pending[uids[n-1]] = true
+			reply("+OK")
+		case "NOOP":
+			reply("+OK")
+		case "QUIT":
+			s.mu.Lock()
+			if s.dropNextQuit {
+				s.dropNextQuit = false
+				s.mu.Unlock()
+				return
+			}
+			for uid := range pending {
+				s.deleted[uid] = true
+				delete(s.messages, uid)
+			}
+			s.mu.Unlock()
+			reply("+OK bye")
+			return
+		default:
+			reply("-ERR comando desconhecido")
+		}
+	}
+}
+
+// seenSet registro de UIDLs processados em memória
+type seenSet struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func (s *seenSet) IsSeen(ctx context.Context, account, uid string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seen[account+"/"+uid], nil
+}
+
+func (s *seenSet) MarkSeen(ctx context.Context, account, uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen == nil {
+		s.seen = make(map[string]bool)
+	}
+	s.seen[account+"/"+uid] = true
+	return nil
+}
+
+func testMessage(id, subject, body string) string {
+	return "Message-ID: <" + id + "@acme.test>\r\n" +
+		"From: cliente@example.com\r\n" +
+		"To: suporte@acme.test\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" + body + "\r\n"
+}
+
+func newTestPOP3Processor(t *testing.T, ts *testStore, server *fakePOP3Server, seen *seenSet, deleteAfter bool) *POP3Processor {
+	t.Helper()
+	pp, err := NewPOP3Processor(&POP3Config{
+		Server:              "pop.acme.test",
+		Username:            "suporte",
+		Password:            "secret",
+		TenantID:            ts.tenantID,
+		UserID:              ts.userID,
+		DeleteAfterDownload: deleteAfter,
+		Dial:                server.dial,
+	}, NewEmailClassifier(), ts.emails, seen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pp
+}
+
+func TestPOP3ProcessorStoresNewMessagesOnce(t *testing.T) {
+	ts := newTestStore(t)
+	server := newFakePOP3Server()
+	server.add("uid-1", testMessage("m1", "Fatura vencida", "A fatura de março venceu ontem."))
+	server.add("uid-2", testMessage("m2", "Reunião amanhã", "Linha com ponto:\n.começa com ponto"))
+	seen := &seenSet{}
+	pp := newTestPOP3Processor(t, ts, server, seen, false)
+
+	if err := pp.processMailbox(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	emails := ts.listEmails(t)
+	if len(emails) != 2 {
+		t.Fatalf("esperava 2 emails, obteve %d", len(emails))
+	}
+	for _, e := range emails {
+		if e.Folder != "INBOX" || e.UserID != ts.userID {
+			t.Errorf("email %s com pasta %q e usuário %q", e.MessageID, e.Folder, e.UserID)
+		}
+		if e.MessageID == "m2@acme.test" && !strings.Contains(e.Content, ".começa com ponto") {
+			t.Errorf("byte-stuffing não removido do conteúdo: %q", e.Content)
+		}
+	}
+
+	// Uma nova mensagem chega; as anteriores não são baixadas de novo
+	server.add("uid-3", testMessage("m3", "Contrato", "Segue o contrato."))
+	if err := pp.processMailbox(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if emails := ts.listEmails(t); len(emails) != 3 {
+		t.Fatalf("esperava 3 emails, obteve %d", len(emails))
+	}
+	for _, uid := range []string{"uid-1", "uid-2", "uid-3"} {
+		if ok, _ := seen.IsSeen(context.Background(), pp.account(), uid); !ok {
+			t.Errorf("%s não registrado como processado", uid)
+		}
+	}
+	if len(server.deleted) != 0 {
+		t.Errorf("mensagens removidas sem DeleteAfterDownload: %v", server.deleted)
+	}
+}
+
+func TestPOP3ProcessorDeletesAfterDownload(t *testing.T) {
+	ts := newTestStore(t)
+	server := newFakePOP3Server()
+	server.add("uid-1", testMessage("m1", "Pedido", "Pedido número 10."))
+	pp := newTestPOP3Processor(t, ts, server, &seenSet{}, true)
+
+	if err := pp.processMailbox(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !server.deleted["uid-1"] {
+		t.Error("mensagem não removida do servidor após o QUIT")
+	}
+	if len(ts.listEmails(t)) != 1 {
+		t.Error("mensagem removida não foi salva")
+	}
+}
+
+func TestPOP3ProcessorRetriesDeleteAfterDroppedSession(t *testing.T) {
+	ts := newTestStore(t)
+	server := newFakePOP3Server()
+	server.add("uid-1", testMessage("m1", "Pedido", "Pedido número 10."))
+	pp := newTestPOP3Processor(t, ts, server, &seenSet{}, true)
+	server.dropNextQuit = true
+
+	// A conexão cai no QUIT: a mensagem foi salva, mas continua no servidor
+	if err := pp.processMailbox(context.Background()); err == nil {
+		t.Fatal("esperava erro ao encerrar a sessão")
+	}
+	if server.deleted["uid-1"] {
+		t.Fatal("remoção efetivada sem QUIT")
+	}
+
+	// A próxima sessão remove a mensagem já vista sem salvá-la de novo
+	if err := pp.processMailbox(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !server.deleted["uid-1"] {
+		t.Error("mensagem já vista não removida do servidor")
+	}
+	if emails := ts.listEmails(t); len(emails) != 1 {
+		t.Errorf("salvos %d emails, esperado 1", len(emails))
+	}
+}
+
+func TestPOP3ProcessorRejectsInvalidCredentials(t *testing.T) {
+	ts := newTestStore(t)
+	server := newFakePOP3Server()
+	_, err := NewPOP3Processor(&POP3Config{
+		Username: "suporte",
+		Password: "errada",
+		TenantID: ts.tenantID,
+		Dial:     server.dial,
+	}, NewEmailClassifier(), ts.emails, &seenSet{})
+	if err == nil {
+		t.Fatal("esperava erro de autenticação")
+	}
+}
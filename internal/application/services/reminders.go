@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/notify"
+)
+
+// ReminderConfig configuração do agendador de lembretes
+type ReminderConfig struct {
+	Interval  time.Duration // Intervalo entre as verificações
+	Lead      time.Duration // Antecedência do lembrete de prazo próximo
+	BatchSize int           // Lembretes processados por verificação
+}
+
+// ReminderScheduler marca tarefas atrasadas e envia lembretes de prazo pelos
+// canais configurados. Cada envio é registrado antes de acontecer, então
+// reinícios e outras instâncias do serviço não repetem lembretes; falhas
+// desfazem o registro e o envio é tentado de novo na próxima verificação.
+type ReminderScheduler struct {
+	repo      entities.ReminderRepository
+	notifiers []notify.Notifier
+	channels  []string
+	clock     Clock
+	config    ReminderConfig
+}
+
+// NewReminderScheduler cria uma nova instância do agendador de lembretes
+func NewReminderScheduler(repo entities.ReminderRepository, notifiers []notify.Notifier, clock Clock, config *ReminderConfig) *ReminderScheduler {
+	cfg := ReminderConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Lead <= 0 {
+		cfg.Lead = 24 * time.Hour
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	channels := make([]string, len(notifiers))
+	for i, n := range notifiers {
+		channels[i] = n.Name()
+	}
+
+	return &ReminderScheduler{
+		repo:      repo,
+		notifiers: notifiers,
+		channels:  channels,
+		clock:     clock,
+		config:    cfg,
+	}
+}
+
+// Run executa as verificações periodicamente até o contexto ser cancelado
+func (rs *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(rs.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := rs.RunOnce(ctx); err != nil {
+			log.Printf("Erro no agendador de lembretes: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunOnce executa uma verificação: reabre as tarefas com adiamento vencido,
+// marca as tarefas atrasadas e envia os lembretes pendentes, retornando
+// quantos envios foram feitos
+func (rs *ReminderScheduler) RunOnce(ctx context.Context) (int, error) {
+	now := rs.clock.Now()
+
+	if woken, err := rs.repo.WakeSnoozed(ctx, now); err != nil {
+		return 0, err
+	} else if woken > 0 {
+		log.Printf("%d tarefa(s) adiada(s) reaberta(s)", woken)
+	}
+
+	if marked, err := rs.repo.MarkOverdue(ctx, now); err != nil {
+		return 0, err
+	} else if marked > 0 {
+		log.Printf("%d tarefa(s) marcada(s) como atrasada(s)", marked)
+	}
+
+	if len(rs.notifiers) == 0 {
+		return 0, nil
+	}
+
+	reminders, err := rs.repo.Due(ctx, now, rs.config.Lead, rs.channels, rs.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		for _, n := range rs.notifiers {
+			if ctx.Err() != nil {
+				return sent, ctx.Err()
+			}
+			if rs.send(ctx, n, reminder) {
+				sent++
+			}
+		}
+	}
+	return sent, nil
+}
+
+// send registra e envia o lembrete pelo canal; false quando já enviado ou em falha
+func (rs *ReminderScheduler) send(ctx context.Context, n notify.Notifier, reminder *entities.Reminder) bool {
+	claimed, err := rs.repo.Claim(ctx, reminder, n.Name())
+	if err != nil {
+		log.Printf("Erro ao registrar lembrete da tarefa %s: %v", reminder.Task.ID, err)
+		return false
+	}
+	if !claimed {
+		return false
+	}
+
+	if err := n.Notify(ctx, reminder); err != nil {
+		log.Printf("Erro ao enviar lembrete %s da tarefa %s via %s: %v", reminder.Kind, reminder.Task.ID, n.Name(), err)
+		// Libera mesmo com o contexto cancelado, para não perder o lembrete
+		if err := rs.repo.Release(context.WithoutCancel(ctx), reminder, n.Name()); err != nil {
+			log.Printf("Erro ao liberar lembrete da tarefa %s: %v", reminder.Task.ID, err)
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/memory"
+	"github.com/enzo010/email-filter/internal/infrastructure/notify"
+)
+
+// fakeClock relógio controlado pelo teste
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance avança o relógio
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// recordingNotifier canal que guarda os lembretes enviados; falha nas
+// próximas failures chamadas
+type recordingNotifier struct {
+	mu       sync.Mutex
+	sent     []entities.Reminder
+	failures int
+}
+
+func (n *recordingNotifier) Name() string { return "test" }
+
+func (n *recordingNotifier) Notify(ctx context.Context, reminder *entities.Reminder) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("canal indisponível")
+	}
+	n.sent = append(n.sent, *reminder)
+	return nil
+}
+
+// kinds tipos dos lembretes enviados, em ordem
+func (n *recordingNotifier) kinds() []entities.ReminderKind {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	kinds := make([]entities.ReminderKind, len(n.sent))
+	for i, r := range n.sent {
+		kinds[i] = r.Kind
+	}
+	return kinds
+}
+
+// createTask grava um email do usuário do teste com uma tarefa para due
+func (ts *testStore) createTask(t *testing.T, description string, due time.Time) *entities.Task {
+	t.Helper()
+	email := &entities.Email{
+		TenantID:    ts.tenantID,
+		UserID:      ts.userID,
+		MessageID:   description + "@acme.test",
+		Subject:     description,
+		From:        "cliente@example.com",
+		To:          "ana@acme.test",
+		Content:     description,
+		Folder:      "INBOX",
+		Priority:    entities.PriorityMedium,
+		Category:    "work",
+		ProcessedAt: due,
+		Tasks: []entities.Task{
+			{Description: description, DueDate: due, Priority: entities.PriorityMedium, Status: entities.TaskPending},
+		},
+	}
+	if _, err := ts.emails.Create(ts.ctx(), email); err != nil {
+		t.Fatal(err)
+	}
+	return &email.Tasks[0]
+}
+
+// getTask lê a tarefa do tenant do teste
+func (ts *testStore) getTask(t *testing.T, id string) *entities.Task {
+	t.Helper()
+	task, err := memory.NewTaskRepository(ts.store).GetByID(ts.ctx(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return task
+}
+
+func newTestReminderScheduler(ts *testStore, n *recordingNotifier, clock Clock) *ReminderScheduler {
+	return NewReminderScheduler(memory.NewReminderRepository(ts.store), []notify.Notifier{n}, clock, &ReminderConfig{Lead: time.Hour})
+}
+
+func runOnce(t *testing.T, rs *ReminderScheduler) int {
+	t.Helper()
+	sent, err := rs.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sent
+}
+
+func TestReminderSchedulerSendsDueSoonThenOverdueOnce(t *testing.T) {
+	ts := newTestStore(t)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+	task := ts.createTask(t, "Enviar proposta", start.Add(2*time.Hour))
+	n := &recordingNotifier{}
+	rs := newTestReminderScheduler(ts, n, clock)
+
+	// Fora da antecedência: nada a enviar
+	if sent := runOnce(t, rs); sent != 0 {
+		t.Fatalf("enviados %d lembretes antes da antecedência", sent)
+	}
+
+	// Dentro da antecedência: um lembrete de prazo próximo, uma única vez
+	clock.Advance(90 * time.Minute)
+	if sent := runOnce(t, rs); sent != 1 {
+		t.Fatalf("enviados %d lembretes de prazo próximo, esperado 1", sent)
+	}
+	if sent := runOnce(t, rs); sent != 0 {
+		t.Fatalf("lembrete de prazo próximo repetido (%d)", sent)
+	}
+	if got := ts.getTask(t, task.ID); got.OverdueAt != nil {
+		t.Errorf("tarefa no prazo marcada como atrasada em %v", got.OverdueAt)
+	}
+
+	// Prazo vencido: marca a tarefa no horário do relógio e envia o lembrete de atraso
+	clock.Advance(time.Hour)
+	if sent := runOnce(t, rs); sent != 1 {
+		t.Fatalf("enviados %d lembretes de atraso, esperado 1", sent)
+	}
+	if sent := runOnce(t, rs); sent != 0 {
+		t.Fatalf("lembrete de atraso repetido (%d)", sent)
+	}
+	if got := ts.getTask(t, task.ID); got.OverdueAt == nil || !got.OverdueAt.Equal(clock.Now()) {
+		t.Errorf("overdue_at = %v, esperado %v", got.OverdueAt, clock.Now())
+	}
+
+	kinds := n.kinds()
+	if len(kinds) != 2 || kinds[0] != entities.ReminderDueSoon || kinds[1] != entities.ReminderOverdue {
+		t.Errorf("lembretes enviados = %v", kinds)
+	}
+	if r := n.sent[0]; r.Task.ID != task.ID || r.UserID != ts.userID || r.Recipient != "ana@acme.test" {
+		t.Errorf("lembrete = %+v", r)
+	}
+}
+
+func TestReminderSchedulerRetriesFailedSend(t *testing.T) {
+	ts := newTestStore(t)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+	ts.createTask(t, "Pagar fornecedor", start.Add(-time.Minute))
+	n := &recordingNotifier{failures: 1}
+	rs := newTestReminderScheduler(ts, n, clock)
+
+	if sent := runOnce(t, rs); sent != 0 {
+		t.Fatalf("enviados %d lembretes com o canal em falha", sent)
+	}
+	clock.Advance(time.Minute)
+	if sent := runOnce(t, rs); sent != 1 {
+		t.Fatalf("enviados %d lembretes na nova tentativa, esperado 1", sent)
+	}
+	if kinds := n.kinds(); len(kinds) != 1 || kinds[0] != entities.ReminderOverdue {
+		t.Errorf("lembretes enviados = %v", kinds)
+	}
+}
+
+func TestReminderSchedulerWakesSnoozedTasks(t *testing.T) {
+	ts := newTestStore(t)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+	task := ts.createTask(t, "Revisar contrato", start.Add(-time.Hour))
+	tasks := memory.NewTaskRepository(ts.store)
+
+	until := start.Add(2 * time.Hour)
+	change, err := task.Transition(entities.TaskSnoozed, &until, ts.userID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tasks.Transition(ts.ctx(), task, change); err != nil {
+		t.Fatal(err)
+	}
+
+	n := &recordingNotifier{}
+	rs := newTestReminderScheduler(ts, n, clock)
+
+	// Adiada: sem lembretes nem marcação de atraso
+	if sent := runOnce(t, rs); sent != 0 {
+		t.Fatalf("enviados %d lembretes para tarefa adiada", sent)
+	}
+	if got := ts.getTask(t, task.ID); got.Status != entities.TaskSnoozed || got.OverdueAt != nil {
+		t.Errorf("tarefa adiada = %+v", got)
+	}
+
+	// Fim do adiamento: volta a pending, é marcada como atrasada e lembrada
+	clock.Advance(2 * time.Hour)
+	if sent := runOnce(t, rs); sent != 1 {
+		t.Fatalf("enviados %d lembretes após o adiamento, esperado 1", sent)
+	}
+	got := ts.getTask(t, task.ID)
+	if got.Status != entities.TaskPending || got.SnoozedUntil != nil || got.OverdueAt == nil {
+		t.Errorf("tarefa reaberta = %+v", got)
+	}
+	history, err := tasks.History(ts.ctx(), task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := history[len(history)-1]; last.ToStatus != entities.TaskPending || last.Note != entities.SnoozeExpiredNote {
+		t.Errorf("última mudança = %+v", last)
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-smtp"
+)
+
+// SMTPReceiverConfig configuração do receptor SMTP/LMTP de emails encaminhados
+type SMTPReceiverConfig struct {
+	Addr            string // Ex: ":2525"
+	LMTP            bool   // Fala LMTP (RFC 2033) em vez de SMTP
+	MaxMessageBytes int64
+	MaxRecipients   int
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+}
+
+// SMTPReceiver recebe emails via SMTP/LMTP e os entrega pelo InboundService
+type SMTPReceiver struct {
+	server  *smtp.Server
+	config  SMTPReceiverConfig
+	inbound *InboundService
+}
+
+var (
+	errUnknownRecipient = &smtp.SMTPError{
+		Code:         550,
+		EnhancedCode: smtp.EnhancedCode{5, 1, 1},
+		Message:      "Destinatário desconhecido",
+	}
+	errRecipientDomain = &smtp.SMTPError{
+		Code:         550,
+		EnhancedCode: smtp.EnhancedCode{5, 7, 1},
+		Message:      "Domínio de destino não atendido por este servidor",
+	}
+	errTooManyRecipients = &smtp.SMTPError{
+		Code:         452,
+		EnhancedCode: smtp.EnhancedCode{4, 5, 3},
+		Message:      "Número máximo de destinatários excedido",
+	}
+	errStorageFailed = &smtp.SMTPError{
+		Code:         451,
+		EnhancedCode: smtp.EnhancedCode{4, 3, 0},
+		Message:      "Falha temporária ao processar a mensagem",
+	}
+)
+
+// NewSMTPReceiver cria uma nova instância do receptor
+func NewSMTPReceiver(config *SMTPReceiverConfig, inbound *InboundService) *SMTPReceiver {
+	cfg := *config
+	if cfg.MaxMessageBytes <= 0 {
+		cfg.MaxMessageBytes = 25 * 1024 * 1024
+	}
+	if cfg.MaxRecipients <= 0 {
+		cfg.MaxRecipients = 50
+	}
+	if cfg.ReadTimeout <= 0 {
+		cfg.ReadTimeout = time.Minute
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = time.Minute
+	}
+
+	sr := &SMTPReceiver{
+		config:  cfg,
+		inbound: inbound,
+	}
+
+	s := smtp.NewServer(sr)
+	s.Addr = cfg.Addr
+	s.Domain = inbound.Domain()
+	s.LMTP = cfg.LMTP
+	s.MaxMessageBytes = cfg.MaxMessageBytes
+	s.MaxRecipients = cfg.MaxRecipients
+	s.ReadTimeout = cfg.ReadTimeout
+	s.WriteTimeout = cfg.WriteTimeout
+	sr.server = s
+
+	return sr
+}
+
+// ListenAndServe inicia o receptor no endereço configurado
+func (sr *SMTPReceiver) ListenAndServe() error {
+	if sr.config.LMTP && strings.HasPrefix(sr.config.Addr, "/") {
+		sr.server.Network = "unix"
+	}
+	return sr.server.ListenAndServe()
+}
+
+// Serve atende conexões de um listener já aberto
+func (sr *SMTPReceiver) Serve(l net.Listener) error {
+	return sr.server.Serve(l)
+}
+
+// Close encerra o receptor
+func (sr *SMTPReceiver) Close() error {
+	return sr.server.Close()
+}
+
+// NewSession implementa smtp.Backend
+func (sr *SMTPReceiver) NewSession(c *smtp.Conn) (smtp.Session, error) {
+	return &smtpSession{receiver: sr}, nil
+}
+
+// inboundRecipient destinatário validado de uma mensagem
+type inboundRecipient struct {
+	address  string
+	tenantID string
+	userID   string
+}
+
+// smtpSession estado de uma transação SMTP
+type smtpSession struct {
+	receiver   *SMTPReceiver
+	from       string
+	recipients []inboundRecipient
+}
+
+func (s *smtpSession) Mail(from string, opts *smtp.MailOptions) error {
+	s.from = from
+	return nil
+}
+
+func (s *smtpSession) Rcpt(to string, opts *smtp.RcptOptions) error {
+	if len(s.recipients) >= s.receiver.config.MaxRecipients {
+		return errTooManyRecipients
+	}
+
+	tenantID, userID, err := s.receiver.inbound.ResolveRecipient(context.Background(), to)
+	if errors.Is(err, ErrRecipientDomain) {
+		return errRecipientDomain
+	}
+	if err != nil {
+		return errUnknownRecipient
+	}
+
+	s.recipients = append(s.recipients, inboundRecipient{address: to, tenantID: tenantID, userID: userID})
+	return nil
+}
+
+func (s *smtpSession) Data(r io.Reader) error {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	// No SMTP a mensagem só é aceita se todos os destinatários forem processados
+	for _, rcpt := range s.recipients {
+		if err := s.receiver.deliver(context.Background(), raw, rcpt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LMTPData implementa smtp.LMTPSession com status individual por destinatário
+func (s *smtpSession) LMTPData(r io.Reader, status smtp.StatusCollector) error {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	for _, rcpt := range s.recipients {
+		status.SetStatus(rcpt.address, s.receiver.deliver(context.Background(), raw, rcpt))
+	}
+	return nil
+}
+
+func (s *smtpSession) Reset() {
+	s.from = ""
+	s.recipients = nil
+}
+
+func (s *smtpSession) Logout() error {
+	return nil
+}
+
+// deliver interpreta, classifica e armazena a mensagem para um destinatário
+func (sr *SMTPReceiver) deliver(ctx context.Context, raw []byte, rcpt inboundRecipient) error {
+	email, err := ParseMessage(bytes.NewReader(raw))
+	if err != nil {
+		return &smtp.SMTPError{
+			Code:         554,
+			EnhancedCode: smtp.EnhancedCode{5, 6, 0},
+			Message:      "Mensagem malformada",
+		}
+	}
+	if err := sr.inbound.deliverTo(ctx, email, rcpt.tenantID, rcpt.userID); err != nil {
+		log.Printf("Erro ao processar email recebido para %s: %v", rcpt.address, err)
+		return errStorageFailed
+	}
+	return nil
+}
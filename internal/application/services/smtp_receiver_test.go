@@ -0,0 +1,151 @@
+package services
+
+import (
+	"errors"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// startTestReceiver inicia o receptor em uma porta local livre
+func startTestReceiver(t *testing.T, ts *testStore, lmtp bool) string {
+	t.Helper()
+	inbound := NewInboundService("in.acme.test", NewEmailClassifier(), ts.emails, ts.tenants, ts.users)
+	receiver := NewSMTPReceiver(&SMTPReceiverConfig{LMTP: lmtp, MaxRecipients: 3}, inbound)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go receiver.Serve(l)
+	t.Cleanup(func() { receiver.Close() })
+	return l.Addr().String()
+}
+
+func TestSMTPReceiverDeliversWithNetSMTP(t *testing.T) {
+	ts := newTestStore(t)
+	addr := startTestReceiver(t, ts, false)
+
+	msg := testMessage("smtp-1", "Pagamento pendente", "O boleto vence amanhã, favor pagar.")
+	to := []string{ts.tenantID + "@in.acme.test", ts.tenantID + "+" + ts.userID + "@IN.ACME.TEST"}
+	if err := smtp.SendMail(addr, nil, "cliente@example.com", to, []byte(msg)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Os dois endereços resolvem para o mesmo usuário; a segunda cópia é
+	// ignorada pela deduplicação por Message-ID
+	emails := ts.listEmails(t)
+	if len(emails) != 1 {
+		t.Fatalf("esperava 1 email, obteve %d", len(emails))
+	}
+	e := emails[0]
+	if e.Subject != "Pagamento pendente" || e.From != "cliente@example.com" || e.UserID != ts.userID || e.Folder != "INBOX" {
+		t.Errorf("email salvo incorreto: %+v", e)
+	}
+	if e.Priority == "" || e.ProcessedAt.IsZero() {
+		t.Errorf("email salvo sem classificação: %+v", e)
+	}
+}
+
+func TestSMTPReceiverRejectsRecipients(t *testing.T) {
+	ts := newTestStore(t)
+	addr := startTestReceiver(t, ts, false)
+
+	c, err := smtp.Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.Mail("cliente@example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		rcpt string
+		code int
+	}{
+		{ts.tenantID + "@outro.test", 550},
+		{"00000000-0000-0000-0000-000000000000@in.acme.test", 550},
+		{ts.tenantID + "+desconhecido@in.acme.test", 550},
+	}
+	for _, tc := range cases {
+		err := c.Rcpt(tc.rcpt)
+		var protoErr *textproto.Error
+		if !errors.As(err, &protoErr) || protoErr.Code != tc.code {
+			t.Errorf("RCPT %s: esperava %d, obteve %v", tc.rcpt, tc.code, err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := c.Rcpt(ts.tenantID + "@in.acme.test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = c.Rcpt(ts.tenantID + "@in.acme.test")
+	var protoErr *textproto.Error
+	if !errors.As(err, &protoErr) || protoErr.Code != 452 {
+		t.Errorf("esperava 452 acima do limite de destinatários, obteve %v", err)
+	}
+}
+
+func TestSMTPReceiverRejectsMalformedMessage(t *testing.T) {
+	ts := newTestStore(t)
+	addr := startTestReceiver(t, ts, false)
+
+	err := smtp.SendMail(addr, nil, "cliente@example.com", []string{ts.tenantID + "@in.acme.test"},
+		[]byte("Cabeçalho sem separador\r\n"))
+	var protoErr *textproto.Error
+	if !errors.As(err, &protoErr) || protoErr.Code != 554 {
+		t.Fatalf("esperava 554 para mensagem malformada, obteve %v", err)
+	}
+	if len(ts.listEmails(t)) != 0 {
+		t.Error("mensagem malformada foi salva")
+	}
+}
+
+func TestLMTPReceiverReportsStatusPerRecipient(t *testing.T) {
+	ts := newTestStore(t)
+	addr := startTestReceiver(t, ts, true)
+
+	conn, err := textproto.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	expect := func(code int) {
+		t.Helper()
+		if _, _, err := conn.ReadResponse(code); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send := func(code int, format string, args ...interface{}) {
+		t.Helper()
+		if err := conn.PrintfLine(format, args...); err != nil {
+			t.Fatal(err)
+		}
+		expect(code)
+	}
+
+	expect(220)
+	send(250, "LHLO cliente.test")
+	send(250, "MAIL FROM:<cliente@example.com>")
+	send(250, "RCPT TO:<%s@in.acme.test>", ts.tenantID)
+	send(250, "RCPT TO:<%s+%s@in.acme.test>", ts.tenantID, ts.userID)
+	send(354, "DATA")
+
+	w := conn.DotWriter()
+	w.Write([]byte(strings.ReplaceAll(testMessage("lmtp-1", "Contrato", "Segue o contrato."), "\r\n", "\n")))
+	w.Close()
+
+	// LMTP responde uma vez por destinatário aceito
+	expect(250)
+	expect(250)
+	send(221, "QUIT")
+
+	if len(ts.listEmails(t)) != 1 {
+		t.Errorf("esperava 1 email salvo via LMTP")
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/webhook"
+)
+
+// webhookLease tempo em que uma entrega reservada fica fora das verificações;
+// se a instância parar durante o envio, a entrega volta a ser tentada depois dele
+const webhookLease = 2 * time.Minute
+
+// WebhookConfig configuração da entrega de webhooks
+type WebhookConfig struct {
+	Interval    time.Duration // Intervalo entre as verificações de entregas pendentes
+	BatchSize   int           // Entregas enviadas por verificação, em paralelo
+	MaxAttempts int           // Tentativas antes de a entrega ser marcada como falha
+	RetryBase   time.Duration // Espera após a primeira falha; dobra a cada tentativa
+	RetryMax    time.Duration // Espera máxima entre tentativas
+	Timeout     time.Duration // Tempo limite de cada requisição
+}
+
+var _ EventPublisher = (*WebhookService)(nil)
+
+// WebhookService registra as entregas dos eventos para os webhooks inscritos e
+// as envia em segundo plano, com novas tentativas em backoff exponencial. O
+// registro de entregas guarda o resultado da última tentativa de cada uma.
+type WebhookService struct {
+	repo   entities.WebhookRepository
+	client *webhook.Client
+	clock  Clock
+	config WebhookConfig
+}
+
+// NewWebhookService cria uma nova instância do serviço de webhooks
+func NewWebhookService(repo entities.WebhookRepository, clock Clock, config *WebhookConfig) *WebhookService {
+	cfg := WebhookConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 20
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.RetryBase <= 0 {
+		cfg.RetryBase = 30 * time.Second
+	}
+	if cfg.RetryMax <= 0 {
+		cfg.RetryMax = 6 * time.Hour
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	return &WebhookService{
+		repo:   repo,
+		client: webhook.NewClient(cfg.Timeout),
+		clock:  clock,
+		config: cfg,
+	}
+}
+
+// Publish registra uma entrega pendente do evento para cada webhook ativo do
+// tenant inscrito nele; o envio é feito por Run
+func (s *WebhookService) Publish(ctx context.Context, event *entities.Event) error {
+	ctx = entities.WithTenant(ctx, event.TenantID)
+
+	webhooks, err := s.repo.Subscribers(ctx, event.TenantID, event.Type)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	now := s.clock.Now()
+	for _, w := range webhooks {
+		delivery := &entities.WebhookDelivery{
+			WebhookID:     w.ID,
+			TenantID:      event.TenantID,
+			EventID:       event.ID,
+			Event:         event.Type,
+			Payload:       payload,
+			Status:        entities.DeliveryPending,
+			NextAttemptAt: &now,
+		}
+		if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Redeliver agenda o reenvio imediato do payload de uma entrega, registrado
+// como uma nova entrega
+func (s *WebhookService) Redeliver(ctx context.Context, original *entities.WebhookDelivery) (*entities.WebhookDelivery, error) {
+	now := s.clock.Now()
+	delivery := &entities.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		TenantID:      original.TenantID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        entities.DeliveryPending,
+		NextAttemptAt: &now,
+	}
+	if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Run envia as entregas pendentes periodicamente até o contexto ser cancelado
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil {
+			log.Printf("Erro na entrega de webhooks: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunOnce reserva e envia um lote de entregas pendentes, retornando quantas
+// foram bem-sucedidas
+func (s *WebhookService) RunOnce(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDue(ctx, s.clock.Now(), webhookLease, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(d *entities.WebhookDelivery) {
+			defer wg.Done()
+			if s.deliver(ctx, d) {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(delivery)
+	}
+	wg.Wait()
+	return succeeded, nil
+}
+
+// deliver envia a entrega e registra o resultado da tentativa
+func (s *WebhookService) deliver(ctx context.Context, d *entities.WebhookDelivery) bool {
+	// O webhook pode ter sido desativado depois de a entrega ser registrada
+	w, err := s.repo.GetByID(entities.WithTenant(ctx, d.TenantID), d.WebhookID)
+	if err != nil {
+		log.Printf("Erro ao buscar webhook %s da entrega %s: %v", d.WebhookID, d.ID, err)
+		return false
+	}
+
+	now := s.clock.Now()
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.ResponseStatus, d.LastError = 0, ""
+
+	if !w.Active {
+		d.LastError = "webhook desativado"
+	} else if d.ResponseStatus, err = s.client.Send(ctx, w, d, now); err != nil {
+		d.LastError = err.Error()
+	}
+
+	switch {
+	case d.LastError == "":
+		d.Status = entities.DeliverySucceeded
+		d.NextAttemptAt = nil
+	case d.Attempts >= s.config.MaxAttempts || !w.Active:
+		d.Status = entities.DeliveryFailed
+		d.NextAttemptAt = nil
+	default:
+		next := now.Add(backoffDelay(s.config.RetryBase, s.config.RetryMax, d.Attempts))
+		d.NextAttemptAt = &next
+	}
+
+	// Registra mesmo com o contexto cancelado, para não repetir o envio
+	if err := s.repo.RecordAttempt(context.WithoutCancel(ctx), d); err != nil {
+		log.Printf("Erro ao registrar tentativa da entrega %s: %v", d.ID, err)
+	}
+	if d.Status == entities.DeliveryFailed {
+		log.Printf("Entrega %s do evento %s falhou após %d tentativa(s): %s", d.ID, d.Event, d.Attempts, d.LastError)
+	}
+	return d.Status == entities.DeliverySucceeded
+}
+
+// backoffDelay espera antes da próxima tentativa: base dobrando a cada falha,
+// limitada a ceiling
+func backoffDelay(base, ceiling time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < ceiling; i++ {
+		delay *= 2
+	}
+	return min(delay, ceiling)
+}
@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"context"
+	"time"
+)
+
+// BackfillStatus representa o estado de um job de importação histórica
+type BackfillStatus string
+
+const (
+	BackfillPending   BackfillStatus = "pending"
+	BackfillRunning   BackfillStatus = "running"
+	BackfillPaused    BackfillStatus = "paused"
+	BackfillCompleted BackfillStatus = "completed"
+	BackfillFailed    BackfillStatus = "failed"
+)
+
+// BackfillJob representa a classificação retroativa de um período da caixa de correio.
+// Os blocos são processados do mais recente para o mais antigo; Cursor marca o fim
+// (exclusivo) do próximo bloco e permite retomar o job após reinícios.
+type BackfillJob struct {
+	ID         string         `json:"id"`
+	TenantID   string         `json:"tenant_id"`
+	UserID     string         `json:"user_id"`
+	Folder     string         `json:"folder"`
+	Since      time.Time      `json:"since"`
+	Until      time.Time      `json:"until"`
+	Cursor     time.Time      `json:"cursor"`
+	Status     BackfillStatus `json:"status"`
+	Scanned    int            `json:"scanned"`
+	Classified int            `json:"classified"`
+	Failed     int            `json:"failed"`
+	LastError  string         `json:"last_error,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// BackfillRepository interface para operações com jobs de importação histórica
+type BackfillRepository interface {
+	Create(ctx context.Context, job *BackfillJob) error
+	GetByID(ctx context.Context, id string) (*BackfillJob, error)
+	UpdateProgress(ctx context.Context, job *BackfillJob) error // Não altera o status
+	UpdateStatus(ctx context.Context, id string, status BackfillStatus) error
+	ListUnfinished(ctx context.Context, tenantID, userID string) ([]*BackfillJob, error)
+}
@@ -2,9 +2,15 @@ package entities
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"time"
 )
 
+// ErrEmailOwnerRequired indica email sem tenant ou usuário de destino
+var ErrEmailOwnerRequired = errors.New("email sem tenant ou usuário")
+
 // Priority representa o nível de prioridade do email
 type Priority string
 
@@ -19,46 +25,46 @@ type Email struct {
 	ID          string    `json:"id"`
 	TenantID    string    `json:"tenant_id"`
 	UserID      string    `json:"user_id"`
+	MessageID   string    `json:"message_id"`   // Cabeçalho Message-ID, sem os delimitadores < >
+	ContentHash string    `json:"content_hash"` // Identifica mensagens sem Message-ID
 	Subject     string    `json:"subject"`
 	From        string    `json:"from"`
 	To          string    `json:"to"`
 	Content     string    `json:"content"`
+	Folder      string    `json:"folder"` // Pasta de origem na caixa de correio
 	Priority    Priority  `json:"priority"`
 	Category    string    `json:"category"`
 	Labels      []string  `json:"labels"`
 	Tasks       []Task    `json:"tasks"`
+	Meeting     *Meeting  `json:"meeting,omitempty"` // Convite de reunião anexado, se houver
 	ProcessedAt time.Time `json:"processed_at"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-// Task representa uma tarefa sugerida baseada no conteúdo do email
-type Task struct {
-	ID          string    `json:"id"`
-	Description string    `json:"description"`
-	DueDate     time.Time `json:"due_date"`
-	Priority    Priority  `json:"priority"`
-	Status      string    `json:"status"` // pending, completed
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"` // Adicionado campo UpdatedAt
+// ComputeContentHash calcula o hash SHA-256 dos campos que identificam a mensagem
+func (e *Email) ComputeContentHash() string {
+	h := sha256.New()
+	for _, field := range []string{e.From, e.To, e.Subject, e.Content} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 // EmailRepository interface para operações com emails
 type EmailRepository interface {
-	Create(ctx context.Context, email *Email) error
+	// Create salva o email se ainda não existir no tenant (mesmo Message-ID ou,
+	// na falta dele, mesmo hash de conteúdo). created é false quando o email já
+	// existia; nesse caso email recebe o ID do registro existente.
+	Create(ctx context.Context, email *Email) (created bool, err error)
+	// CreateAll salva os emails em uma única transação, com as regras de
+	// Create; um erro em qualquer um deles descarta todos
+	CreateAll(ctx context.Context, emails []*Email) (created []bool, err error)
 	GetByID(ctx context.Context, id string) (*Email, error)
 	Update(ctx context.Context, email *Email) error
 	Delete(ctx context.Context, id string) error
-	ListByTenant(ctx context.Context, tenantID string, filters map[string]interface{}) ([]*Email, error)
-	ListByUser(ctx context.Context, userID string, filters map[string]interface{}) ([]*Email, error)
-}
-
-// TaskRepository interface para operações com tarefas
-type TaskRepository interface {
-	Create(task *Task) error
-	GetByID(id string) (*Task, error)
-	Update(task *Task) error
-	Delete(id string) error
-	ListByEmail(emailID string, filters map[string]interface{}) ([]*Task, error)
-	ListPendingTasks(userID string, filters map[string]interface{}) ([]*Task, error)
+	ListByTenant(ctx context.Context, tenantID string, filter *EmailFilter) (*PageResult[*Email], error)
+	ListByUser(ctx context.Context, userID string, filter *EmailFilter) (*PageResult[*Email], error)
+	Search(ctx context.Context, tenantID string, query *EmailSearchQuery, page Page) (*PageResult[*EmailSearchResult], error)
 }
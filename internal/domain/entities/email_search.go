@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"strings"
+	"unicode"
+)
+
+// EmailSearchQuery consulta de busca textual já separada em termos e filtros
+type EmailSearchQuery struct {
+	Text   string   // Termos e frases ("..."), no formato do websearch do Postgres
+	From   []string // Prefixo from:
+	Labels []string // Prefixo label:
+}
+
+// EmailSearchResult email encontrado com relevância e trechos destacados.
+// Subject e Highlight são HTML: o texto do email vem escapado e apenas os
+// termos encontrados são marcados com <mark>.
+type EmailSearchResult struct {
+	Email     *Email  `json:"email"`
+	Rank      float64 `json:"rank"`
+	Subject   string  `json:"subject_highlight"`
+	Highlight string  `json:"highlight"`
+}
+
+// ParseSearchQuery interpreta a consulta digitada pelo usuário.
+// Aceita frases entre aspas e os prefixos from: e label:, que podem ter valor
+// entre aspas (label:"a fazer"). O restante é mantido como texto de busca.
+func ParseSearchQuery(raw string) *EmailSearchQuery {
+	query := &EmailSearchQuery{}
+	var text []string
+
+	for _, token := range splitSearchTokens(raw) {
+		field, value, ok := strings.Cut(token, ":")
+		if ok && value != "" {
+			value = strings.Trim(value, `"`)
+			switch strings.ToLower(field) {
+			case "from":
+				query.From = append(query.From, value)
+				continue
+			case "label":
+				query.Labels = append(query.Labels, value)
+				continue
+			}
+		}
+		text = append(text, token)
+	}
+
+	query.Text = strings.Join(text, " ")
+	return query
+}
+
+// IsEmpty indica consulta sem termos nem filtros
+func (q *EmailSearchQuery) IsEmpty() bool {
+	return q.Text == "" && len(q.From) == 0 && len(q.Labels) == 0
+}
+
+// splitSearchTokens separa por espaços, preservando trechos entre aspas
+func splitSearchTokens(raw string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
@@ -0,0 +1,144 @@
+package entities
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventType tipo de evento publicado para as integrações do tenant
+type EventType string
+
+const (
+	EventEmailClassified   EventType = "email.classified"    // Email classificado e armazenado
+	EventEmailHighPriority EventType = "email.high_priority" // Email classificado com prioridade alta
+	EventTaskCreated       EventType = "task.created"        // Tarefa extraída do email ou criada manualmente
+	EventTaskOverdue       EventType = "task.overdue"        // Prazo da tarefa venceu
+)
+
+// EventTypes tipos de evento disponíveis para inscrição
+var EventTypes = []EventType{EventEmailClassified, EventEmailHighPriority, EventTaskCreated, EventTaskOverdue}
+
+// Valid indica se o tipo de evento é conhecido
+func (t EventType) Valid() bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event evento de domínio de um tenant. Data é o objeto do evento
+// (EmailSummary ou TaskEventData) já serializado.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      EventType       `json:"type"`
+	TenantID  string          `json:"tenant_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewEvent cria um evento com ID próprio e os dados serializados
+func NewEvent(tenantID string, t EventType, data interface{}) (*Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar evento %s: %v", t, err)
+	}
+	var id [16]byte
+	rand.Read(id[:])
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	return &Event{
+		ID:        fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16]),
+		Type:      t,
+		TenantID:  tenantID,
+		CreatedAt: time.Now().UTC(),
+		Data:      raw,
+	}, nil
+}
+
+// EmailSummary dados do email enviados nos eventos, sem o conteúdo da mensagem
+type EmailSummary struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	MessageID   string    `json:"message_id"`
+	Subject     string    `json:"subject"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Folder      string    `json:"folder"`
+	Priority    Priority  `json:"priority"`
+	Category    string    `json:"category"`
+	Labels      []string  `json:"labels"`
+	Tasks       []Task    `json:"tasks"`
+	Meeting     *Meeting  `json:"meeting,omitempty"`
+	ProcessedAt time.Time `json:"processed_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewEmailSummary extrai do email os dados publicados nos eventos
+func NewEmailSummary(e *Email) *EmailSummary {
+	summary := &EmailSummary{
+		ID:          e.ID,
+		UserID:      e.UserID,
+		MessageID:   e.MessageID,
+		Subject:     e.Subject,
+		From:        e.From,
+		To:          e.To,
+		Folder:      e.Folder,
+		Priority:    e.Priority,
+		Category:    e.Category,
+		Labels:      e.Labels,
+		Tasks:       e.Tasks,
+		Meeting:     e.Meeting,
+		ProcessedAt: e.ProcessedAt,
+		CreatedAt:   e.CreatedAt,
+	}
+	if summary.Labels == nil {
+		summary.Labels = []string{}
+	}
+	if summary.Tasks == nil {
+		summary.Tasks = []Task{}
+	}
+	return summary
+}
+
+// TaskEventData dados dos eventos de tarefa
+type TaskEventData struct {
+	Task         Task   `json:"task"`
+	EmailSubject string `json:"email_subject,omitempty"`
+}
+
+// EmailEvents eventos de um email recém-armazenado: email.classified,
+// email.high_priority quando a prioridade é alta e task.created para cada
+// tarefa extraída
+func EmailEvents(e *Email) ([]*Event, error) {
+	summary := NewEmailSummary(e)
+	types := []EventType{EventEmailClassified}
+	if e.Priority == PriorityHigh {
+		types = append(types, EventEmailHighPriority)
+	}
+
+	var events []*Event
+	for _, t := range types {
+		event, err := NewEvent(e.TenantID, t, summary)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	for _, task := range e.Tasks {
+		event, err := NewTaskEvent(e.TenantID, EventTaskCreated, task, e.Subject)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// NewTaskEvent cria um evento de tarefa (task.created, task.overdue)
+func NewTaskEvent(tenantID string, t EventType, task Task, emailSubject string) (*Event, error) {
+	return NewEvent(tenantID, t, TaskEventData{Task: task, EmailSubject: emailSubject})
+}
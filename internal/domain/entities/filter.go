@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidFilter indica filtro de listagem com valores inválidos
+var ErrInvalidFilter = errors.New("filtro inválido")
+
+// SortOrder direção da ordenação
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// Campos de ordenação aceitos nas listagens de emails
+const (
+	EmailSortCreatedAt   = "created_at"
+	EmailSortProcessedAt = "processed_at"
+	EmailSortPriority    = "priority"
+	EmailSortSubject     = "subject"
+)
+
+// Campos de ordenação aceitos nas listagens de tarefas
+const (
+	TaskSortCreatedAt = "created_at"
+	TaskSortDueDate   = "due_date"
+	TaskSortPriority  = "priority"
+)
+
+// EmailFilter filtros da listagem de emails. Campos com vários valores
+// aceitam qualquer um deles; campos vazios não filtram.
+type EmailFilter struct {
+	IDs        []string
+	Categories []string
+	Priorities []Priority
+	Labels     []string
+	Folders    []string
+	StartDate  *time.Time // created_at >= StartDate
+	EndDate    *time.Time // created_at <= EndDate
+	Sort       string
+	Order      SortOrder
+	Page
+}
+
+// Validate normaliza os valores padrão e rejeita filtros inválidos
+func (f *EmailFilter) Validate() error {
+	if f.Sort == "" {
+		f.Sort = EmailSortCreatedAt
+	}
+	switch f.Sort {
+	case EmailSortCreatedAt, EmailSortProcessedAt, EmailSortPriority, EmailSortSubject:
+	default:
+		return fmt.Errorf("%w: ordenação desconhecida %q", ErrInvalidFilter, f.Sort)
+	}
+	if err := validateCommon(&f.Order, f.Priorities, f.StartDate, f.EndDate); err != nil {
+		return err
+	}
+	return f.Page.normalize(f.Sort == EmailSortCreatedAt)
+}
+
+// TaskFilter filtros da listagem de tarefas
+type TaskFilter struct {
+	Priorities []Priority
+	Statuses   []string
+	AssigneeID string
+	UserID     string     // Responsável pela tarefa ou, sem responsável, dono do email
+	StartDate  *time.Time // due_date >= StartDate
+	EndDate    *time.Time // due_date <= EndDate
+	Sort       string
+	Order      SortOrder
+	Page
+}
+
+// Validate normaliza os valores padrão e rejeita filtros inválidos
+func (f *TaskFilter) Validate() error {
+	if f.Sort == "" {
+		f.Sort = TaskSortCreatedAt
+	}
+	switch f.Sort {
+	case TaskSortCreatedAt, TaskSortDueDate, TaskSortPriority:
+	default:
+		return fmt.Errorf("%w: ordenação desconhecida %q", ErrInvalidFilter, f.Sort)
+	}
+	for _, status := range f.Statuses {
+		if !TaskStatus(status).Valid() {
+			return fmt.Errorf("%w: status desconhecido %q", ErrInvalidFilter, status)
+		}
+	}
+	if err := validateCommon(&f.Order, f.Priorities, f.StartDate, f.EndDate); err != nil {
+		return err
+	}
+	return f.Page.normalize(f.Sort == TaskSortCreatedAt)
+}
+
+// validateCommon valida os campos compartilhados pelos filtros
+func validateCommon(order *SortOrder, priorities []Priority, start, end *time.Time) error {
+	if *order == "" {
+		*order = SortDesc
+	}
+	if *order != SortAsc && *order != SortDesc {
+		return fmt.Errorf("%w: direção de ordenação desconhecida %q", ErrInvalidFilter, *order)
+	}
+	for _, p := range priorities {
+		if p != PriorityHigh && p != PriorityMedium && p != PriorityLow {
+			return fmt.Errorf("%w: prioridade desconhecida %q", ErrInvalidFilter, p)
+		}
+	}
+	if start != nil && end != nil && start.After(*end) {
+		return fmt.Errorf("%w: data inicial posterior à final", ErrInvalidFilter)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "context"
+
+// FolderState progresso de sincronização de uma pasta IMAP. LastUID só avança
+// sobre mensagens processadas com sucesso e volta a zero quando o UIDVALIDITY muda.
+type FolderState struct {
+	Account     string
+	Folder      string
+	UIDValidity uint32
+	LastUID     uint32
+}
+
+// FolderStateRepository persiste o progresso das pastas IMAP, permitindo
+// retomar a sincronização após reinícios. account identifica a caixa de correio.
+type FolderStateRepository interface {
+	// GetFolderState retorna nil quando a pasta ainda não foi sincronizada
+	GetFolderState(ctx context.Context, account, folder string) (*FolderState, error)
+	SaveFolderState(ctx context.Context, state *FolderState) error
+}
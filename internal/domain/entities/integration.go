@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// IntegrationKind serviço de mensagens de uma integração de notificações
+type IntegrationKind string
+
+const (
+	IntegrationSlack IntegrationKind = "slack" // Incoming webhook do Slack, mensagem em Block Kit
+	IntegrationTeams IntegrationKind = "teams" // Incoming webhook do Microsoft Teams, Adaptive Card
+)
+
+// Valid indica se o tipo de integração é suportado
+func (k IntegrationKind) Valid() bool {
+	return k == IntegrationSlack || k == IntegrationTeams
+}
+
+// IntegrationCriteria condições para um email gerar notificação. Cada lista
+// preenchida precisa conter o valor do email; listas vazias não restringem.
+type IntegrationCriteria struct {
+	Priorities []Priority `json:"priorities"`
+	Categories []string   `json:"categories"`
+}
+
+// Empty indica se nenhum critério foi definido
+func (c IntegrationCriteria) Empty() bool {
+	return len(c.Priorities) == 0 && len(c.Categories) == 0
+}
+
+// Matches indica se o email atende aos critérios
+func (c IntegrationCriteria) Matches(e *EmailSummary) bool {
+	if len(c.Priorities) > 0 && !slices.Contains(c.Priorities, e.Priority) {
+		return false
+	}
+	if len(c.Categories) > 0 && !slices.Contains(c.Categories, e.Category) {
+		return false
+	}
+	return true
+}
+
+// Integration integração de notificações do tenant com Slack ou Teams. WebhookURL
+// dá acesso de escrita ao canal e, como o segredo dos webhooks, só é exibida na criação.
+type Integration struct {
+	ID         string              `json:"id"`
+	TenantID   string              `json:"tenant_id"`
+	Kind       IntegrationKind     `json:"kind"`
+	Name       string              `json:"name"`
+	WebhookURL string              `json:"webhook_url,omitempty"`
+	Criteria   IntegrationCriteria `json:"criteria"`
+	Active     bool                `json:"active"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// IntegrationRepository interface para persistência das integrações de
+// notificações, escopada pelo tenant do contexto
+type IntegrationRepository interface {
+	Create(ctx context.Context, integration *Integration) error
+	GetByID(ctx context.Context, id string) (*Integration, error)
+	ListByTenant(ctx context.Context, tenantID string) ([]*Integration, error)
+	// Update altera nome, URL, critérios e se a integração está ativa
+	Update(ctx context.Context, integration *Integration) error
+	Delete(ctx context.Context, id string) error
+	// ListActive lista as integrações ativas do tenant
+	ListActive(ctx context.Context, tenantID string) ([]*Integration, error)
+}
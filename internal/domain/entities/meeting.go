@@ -0,0 +1,61 @@
+package entities
+
+import "time"
+
+// MeetingMethod método iTIP do convite (RFC 5546)
+type MeetingMethod string
+
+const (
+	MeetingRequest MeetingMethod = "REQUEST"
+	MeetingCancel  MeetingMethod = "CANCEL"
+)
+
+// MeetingPrepTask prefixo da descrição da tarefa de preparação criada para o
+// convite. Novas versões da mesma reunião cancelam as tarefas com esse
+// prefixo ainda abertas nas versões anteriores.
+const MeetingPrepTask = "Preparar para a reunião: "
+
+// MeetingStatus situação da reunião
+type MeetingStatus string
+
+const (
+	MeetingConfirmed MeetingStatus = "confirmed"
+	MeetingCancelled MeetingStatus = "cancelled"
+)
+
+// Meeting reunião descrita pelo convite text/calendar anexado ao email. Convites
+// e cancelamentos da mesma reunião compartilham o UID; Sequence aumenta a cada
+// alteração feita pelo organizador.
+type Meeting struct {
+	UID       string        `json:"uid"`
+	Sequence  int           `json:"sequence"`
+	Method    MeetingMethod `json:"method"`
+	Status    MeetingStatus `json:"status"`
+	Summary   string        `json:"summary"`
+	Organizer string        `json:"organizer"` // Endereço de email
+	Location  string        `json:"location,omitempty"`
+	Start     time.Time     `json:"start"`
+	End       time.Time     `json:"end"`
+	Attendees []string      `json:"attendees"` // Endereços de email
+}
+
+// Scheduling indica se o convite altera a agenda do destinatário. Apenas
+// REQUEST e CANCEL o fazem; REPLY, COUNTER, PUBLISH e demais métodos são
+// respostas ou publicações que não geram tarefas nem substituem versões.
+func (m *Meeting) Scheduling() bool {
+	return m.Method == MeetingRequest || m.Method == MeetingCancel
+}
+
+// Cancelled indica se a reunião foi cancelada
+func (m *Meeting) Cancelled() bool {
+	return m.Status == MeetingCancelled
+}
+
+// SupersededNote nota do histórico das tarefas de preparação das versões
+// anteriores, canceladas ao receber esta versão do convite
+func (m *Meeting) SupersededNote() string {
+	if m.Cancelled() {
+		return "reunião cancelada pelo organizador"
+	}
+	return "reunião atualizada pelo organizador"
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// OutboxEntry evento gravado na mesma transação da alteração que o originou,
+// aguardando publicação nos sinks. As entradas de um tenant são publicadas na
+// ordem de ID; a entrada é removida quando todos os sinks a recebem, ou
+// estacionada quando as tentativas se esgotam.
+type OutboxEntry struct {
+	ID            int64
+	Event         *Event
+	Delivered     []string // Sinks, e destinos de cada sink ("sink:destino"), que já receberam o evento
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	ParkedAt      *time.Time // Preenchido quando a entrada sai da fila após esgotar as tentativas
+}
+
+// Pending indica se o sink ainda não recebeu o evento
+func (e *OutboxEntry) Pending(sink string) bool {
+	return !slices.Contains(e.Delivered, sink)
+}
+
+// OutboxRepository interface para a publicação das entradas do outbox. As
+// operações abrangem todos os tenants e são usadas apenas pelo dispatcher;
+// as entradas são gravadas pelos repositórios de emails e tarefas.
+type OutboxRepository interface {
+	// ClaimDue reserva até limit entradas não estacionadas, em ordem de ID,
+	// dos tenants cuja entrada mais antiga tem tentativa prevista até now,
+	// adiando todas as reservadas para now+lease para que outra instância não
+	// publique o mesmo tenant ao mesmo tempo
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*OutboxEntry, error)
+	// Complete remove a entrada publicada em todos os sinks
+	Complete(ctx context.Context, id int64) error
+	// RecordFailure grava os sinks concluídos, as tentativas, o erro e a
+	// próxima tentativa da entrada, estacionando-a quando ParkedAt está preenchido
+	RecordFailure(ctx context.Context, entry *OutboxEntry) error
+}
+
+type withoutEventsKey struct{}
+
+// WithoutEvents desativa a gravação de eventos no outbox pelos repositórios,
+// para cargas históricas que não devem notificar as integrações
+func WithoutEvents(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutEventsKey{}, true)
+}
+
+// EventsDisabled indica se o contexto desativa a gravação de eventos
+func EventsDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(withoutEventsKey{}).(bool)
+	return disabled
+}
@@ -0,0 +1,223 @@
+package entities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Limites de paginação comuns a todas as listagens
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Page paginação de uma listagem. After e Before recebem os cursores opacos
+// devolvidos em PageResult (NextCursor e PrevCursor, respectivamente).
+type Page struct {
+	PageSize  int
+	After     string
+	Before    string
+	WithTotal bool // Calcula o total de itens que atendem ao filtro
+}
+
+// PageResult página de uma listagem
+type PageResult[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// Cursor posição em uma listagem. Listagens ordenadas por created_at usam a
+// chave (CreatedAt, ID), estável mesmo com a chegada de novos itens; as demais
+// ordenações usam o deslocamento.
+type Cursor struct {
+	CreatedAt time.Time `json:"t,omitempty"`
+	ID        string    `json:"i,omitempty"`
+	Offset    int       `json:"o,omitempty"`
+}
+
+// Keyset indica cursor baseado em chave
+func (c *Cursor) Keyset() bool {
+	return c.ID != ""
+}
+
+// Encode serializa o cursor no formato opaco usado pela API
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor interpreta um cursor devolvido pela API
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor malformado", ErrInvalidFilter)
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 {
+		return nil, fmt.Errorf("%w: cursor malformado", ErrInvalidFilter)
+	}
+	return &c, nil
+}
+
+// Cursor retorna o cursor informado e se a página é anterior a ele (Before)
+func (p Page) Cursor() (cursor *Cursor, backward bool, err error) {
+	switch {
+	case p.After != "":
+		cursor, err = DecodeCursor(p.After)
+	case p.Before != "":
+		cursor, err = DecodeCursor(p.Before)
+		backward = true
+	}
+	return cursor, backward, err
+}
+
+// normalize aplica os valores padrão e valida os limites e o tipo do cursor
+func (p *Page) normalize(keyset bool) error {
+	if p.PageSize == 0 {
+		p.PageSize = DefaultPageSize
+	}
+	if p.PageSize < 0 || p.PageSize > MaxPageSize {
+		return fmt.Errorf("%w: tamanho de página deve estar entre 1 e %d", ErrInvalidFilter, MaxPageSize)
+	}
+	if p.After != "" && p.Before != "" {
+		return fmt.Errorf("%w: use apenas after ou before", ErrInvalidFilter)
+	}
+
+	cursor, _, err := p.Cursor()
+	if err != nil {
+		return err
+	}
+	if cursor != nil && cursor.Keyset() != keyset {
+		return fmt.Errorf("%w: cursor não corresponde à ordenação", ErrInvalidFilter)
+	}
+	return nil
+}
+
+// Pager estado de paginação de uma listagem, compartilhado pelas implementações
+// dos repositórios. Ordenações por created_at usam keyset em (created_at, id);
+// as demais, deslocamento codificado no cursor.
+type Pager struct {
+	Size     int
+	Keyset   bool
+	Cursor   *Cursor
+	Backward bool
+	Offset   int
+}
+
+// NewPager interpreta uma página já validada pelo filtro
+func NewPager(page Page, keyset bool) (*Pager, error) {
+	cursor, backward, err := page.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Pager{Size: page.PageSize, Keyset: keyset, Cursor: cursor, Backward: backward}
+	if !keyset && cursor != nil {
+		p.Offset = cursor.Offset
+		if backward {
+			p.Offset = max(cursor.Offset-page.PageSize, 0)
+		}
+	}
+	return p, nil
+}
+
+// Order retorna o sentido de leitura; páginas anteriores (keyset) são lidas ao
+// contrário e reordenadas em Paginate
+func (p *Pager) Order(order SortOrder) SortOrder {
+	if !p.Keyset || !p.Backward {
+		return order
+	}
+	if order == SortAsc {
+		return SortDesc
+	}
+	return SortAsc
+}
+
+// Includes informa se a chave (createdAt, id) está além do cursor keyset no
+// sentido de leitura
+func (p *Pager) Includes(order SortOrder, createdAt time.Time, id string) bool {
+	if !p.Keyset || p.Cursor == nil {
+		return true
+	}
+	cmp := createdAt.Compare(p.Cursor.CreatedAt)
+	if cmp == 0 {
+		cmp = strings.Compare(id, p.Cursor.ID)
+	}
+	if p.Order(order) == SortAsc {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+// Paginate monta a página e os cursores a partir dos itens lidos no sentido de
+// Order, com até um item além do tamanho da página; key retorna a chave
+// (created_at, id) de um item
+func Paginate[T any](p *Pager, items []T, key func(T) (time.Time, string)) *PageResult[T] {
+	more := len(items) > p.Size
+	if more {
+		items = items[:p.Size]
+	}
+	if items == nil {
+		items = []T{}
+	}
+	result := &PageResult[T]{Items: items}
+
+	if !p.Keyset {
+		if more {
+			result.NextCursor = Cursor{Offset: p.Offset + p.Size}.Encode()
+		}
+		if p.Offset > 0 {
+			result.PrevCursor = Cursor{Offset: p.Offset}.Encode()
+		}
+		return result
+	}
+
+	if p.Backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	if len(items) == 0 {
+		// Página vazia: permite voltar a partir do próprio cursor
+		if p.Cursor != nil && p.Backward {
+			result.NextCursor = p.Cursor.Encode()
+		} else if p.Cursor != nil {
+			result.PrevCursor = p.Cursor.Encode()
+		}
+		return result
+	}
+
+	keyCursor := func(item T) string {
+		createdAt, id := key(item)
+		return Cursor{CreatedAt: createdAt, ID: id}.Encode()
+	}
+	// Em páginas anteriores, "more" indica itens antes da página
+	hasNext, hasPrev := more, p.Cursor != nil
+	if p.Backward {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		result.NextCursor = keyCursor(items[len(items)-1])
+	}
+	if hasPrev {
+		result.PrevCursor = keyCursor(items[0])
+	}
+	return result
+}
+
+// Normalize aplica os valores padrão de paginação em listagens sem filtro próprio
+// (ex: busca por relevância, que pagina por deslocamento)
+func (p *Page) Normalize() error {
+	return p.normalize(false)
+}
+
+// NormalizeKeyset aplica os valores padrão de paginação em listagens sem filtro
+// próprio ordenadas por created_at (ex: registro de entregas de webhooks)
+func (p *Page) NormalizeKeyset() error {
+	return p.normalize(true)
+}
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"context"
+	"time"
+)
+
+// ReminderKind tipo de lembrete de uma tarefa
+type ReminderKind string
+
+const (
+	ReminderDueSoon ReminderKind = "due_soon" // Prazo dentro da antecedência configurada
+	ReminderOverdue ReminderKind = "overdue"  // Prazo vencido
+)
+
+// Reminder lembrete de uma tarefa aberta (pending ou in_progress) a ser enviado
+// ao responsável ou, sem responsável, ao dono do email de origem. O envio é
+// registrado por tarefa, tipo, prazo e canal: alterar o prazo gera novos lembretes.
+type Reminder struct {
+	Kind         ReminderKind `json:"kind"`
+	Task         Task         `json:"task"`
+	TenantID     string       `json:"tenant_id"`
+	UserID       string       `json:"user_id"`   // Destinatário
+	Recipient    string       `json:"recipient"` // Endereço de email do destinatário
+	EmailSubject string       `json:"email_subject"`
+}
+
+// ReminderRepository interface para o agendamento de lembretes. As operações
+// abrangem todos os tenants e são usadas apenas pelo agendador.
+type ReminderRepository interface {
+	// Due lista até limit lembretes com prazo até now+lead ainda não
+	// registrados em algum dos canais, dos prazos mais antigos para os mais novos
+	Due(ctx context.Context, now time.Time, lead time.Duration, channels []string, limit int) ([]*Reminder, error)
+	// Claim registra o envio do lembrete no canal; false quando já registrado,
+	// inclusive por outra instância do serviço
+	Claim(ctx context.Context, reminder *Reminder, channel string) (bool, error)
+	// Release desfaz o registro após falha no envio, permitindo nova tentativa
+	Release(ctx context.Context, reminder *Reminder, channel string) error
+	// MarkOverdue marca como atrasadas, em now, as tarefas abertas com prazo
+	// vencido ainda não marcadas, retornando quantas foram marcadas
+	MarkOverdue(ctx context.Context, now time.Time) (int64, error)
+	// WakeSnoozed devolve a pending as tarefas adiadas até now ou antes,
+	// registrando a mudança no histórico, e retorna quantas foram reabertas
+	WakeSnoozed(ctx context.Context, now time.Time) (int64, error)
+}
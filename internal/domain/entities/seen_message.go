@@ -0,0 +1,10 @@
+package entities
+
+import "context"
+
+// SeenMessageRepository registra mensagens já processadas por fontes sem estado
+// no servidor (ex: UIDL do POP3). account identifica a caixa de correio de origem.
+type SeenMessageRepository interface {
+	IsSeen(ctx context.Context, account, uid string) (bool, error)
+	MarkSeen(ctx context.Context, account, uid string) error
+}
@@ -0,0 +1,128 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTransition indica mudança de status não permitida a partir do status atual
+var ErrInvalidTransition = errors.New("transição de status inválida")
+
+// TaskStatus representa o estado de uma tarefa
+type TaskStatus string
+
+const (
+	TaskPending    TaskStatus = "pending"
+	TaskInProgress TaskStatus = "in_progress"
+	TaskSnoozed    TaskStatus = "snoozed"
+	TaskCompleted  TaskStatus = "completed"
+	TaskCancelled  TaskStatus = "cancelled"
+)
+
+// taskTransitions status alcançáveis a partir de cada status. Tarefas concluídas
+// ou canceladas só saem desse estado quando reabertas (voltam a pending);
+// tarefas adiadas podem ser adiadas novamente para outra data.
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	TaskPending:    {TaskInProgress, TaskSnoozed, TaskCompleted, TaskCancelled},
+	TaskInProgress: {TaskPending, TaskSnoozed, TaskCompleted, TaskCancelled},
+	TaskSnoozed:    {TaskPending, TaskInProgress, TaskSnoozed, TaskCompleted, TaskCancelled},
+	TaskCompleted:  {TaskPending},
+	TaskCancelled:  {TaskPending},
+}
+
+// Valid indica se o status é conhecido
+func (s TaskStatus) Valid() bool {
+	_, ok := taskTransitions[s]
+	return ok
+}
+
+// Open indica se a tarefa ainda aguarda ação: não foi concluída nem cancelada
+func (s TaskStatus) Open() bool {
+	return s == TaskPending || s == TaskInProgress || s == TaskSnoozed
+}
+
+// CanTransitionTo indica se a tarefa pode passar do status s para to
+func (s TaskStatus) CanTransitionTo(to TaskStatus) bool {
+	for _, next := range taskTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Task representa uma tarefa sugerida baseada no conteúdo do email
+type Task struct {
+	ID           string     `json:"id"`
+	EmailID      string     `json:"email_id,omitempty"`
+	Description  string     `json:"description"`
+	DueDate      time.Time  `json:"due_date"`
+	Priority     Priority   `json:"priority"`
+	Status       TaskStatus `json:"status"`
+	AssigneeID   string     `json:"assignee_id,omitempty"`   // Usuário responsável, do mesmo tenant
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"` // Preenchido apenas no status snoozed
+	OverdueAt    *time.Time `json:"overdue_at,omitempty"`    // Quando o atraso foi detectado; limpo ao mudar o prazo
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"` // Adicionado campo UpdatedAt
+}
+
+// TaskStatusChange registro de uma mudança de status no histórico da tarefa
+type TaskStatusChange struct {
+	ID         string     `json:"id"`
+	TaskID     string     `json:"task_id"`
+	FromStatus TaskStatus `json:"from_status"`
+	ToStatus   TaskStatus `json:"to_status"`
+	ActorID    string     `json:"actor_id,omitempty"` // Usuário que fez a mudança
+	Note       string     `json:"note,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// SnoozeExpiredNote nota das mudanças feitas pelo agendador ao reabrir tarefas adiadas
+const SnoozeExpiredNote = "adiamento expirado"
+
+// Transition muda o status da tarefa, validando a transição, e retorna o
+// registro a ser gravado no histórico. until é obrigatório (e só aceito) ao adiar.
+func (t *Task) Transition(to TaskStatus, until *time.Time, actorID, note string) (*TaskStatusChange, error) {
+	if !t.Status.CanTransitionTo(to) {
+		return nil, fmt.Errorf("%w: de %s para %s", ErrInvalidTransition, t.Status, to)
+	}
+	if (to == TaskSnoozed) != (until != nil) {
+		return nil, fmt.Errorf("%w: data de adiamento obrigatória apenas para %s", ErrInvalidTransition, TaskSnoozed)
+	}
+
+	change := &TaskStatusChange{
+		TaskID:     t.ID,
+		FromStatus: t.Status,
+		ToStatus:   to,
+		ActorID:    actorID,
+		Note:       note,
+	}
+	t.Status = to
+	t.SnoozedUntil = until
+	return change, nil
+}
+
+// TaskRepository interface para operações com tarefas
+type TaskRepository interface {
+	// Create salva a tarefa no email task.EmailID, que deve pertencer ao tenant
+	Create(ctx context.Context, task *Task) error
+	GetByID(ctx context.Context, id string) (*Task, error)
+	// Update grava descrição, prazo, prioridade e responsável; o status só muda
+	// por Transition
+	Update(ctx context.Context, task *Task) error
+	Delete(ctx context.Context, id string) error
+	ListByTenant(ctx context.Context, tenantID string, filter *TaskFilter) (*PageResult[*Task], error)
+	ListByEmail(ctx context.Context, emailID string, filter *TaskFilter) (*PageResult[*Task], error)
+	// ListPendingTasks lista as tarefas em aberto (pending, in_progress e
+	// snoozed) pelas quais o usuário responde: atribuídas a ele ou, sem
+	// responsável, dos emails dele. Por padrão, o prazo mais próximo primeiro.
+	ListPendingTasks(ctx context.Context, userID string, filter *TaskFilter) (*PageResult[*Task], error)
+	// Transition grava o novo status de task e o registro change no histórico,
+	// atomicamente. Falha com ErrInvalidTransition se o status gravado não for
+	// mais change.FromStatus (alteração concorrente).
+	Transition(ctx context.Context, task *Task, change *TaskStatusChange) error
+	// History lista as mudanças de status da tarefa, da mais antiga para a mais recente
+	History(ctx context.Context, taskID string) ([]*TaskStatusChange, error)
+}
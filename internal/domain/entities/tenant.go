@@ -1,9 +1,14 @@
 package entities
 
 import (
+	"context"
+	"errors"
 	"time"
 )
 
+// ErrNoTenantContext indica operação com escopo de tenant sem tenant no contexto
+var ErrNoTenantContext = errors.New("tenant não informado no contexto")
+
 // Tenant representa uma organização no sistema multitenancy
 type Tenant struct {
 	ID        string    `json:"id"`
@@ -16,9 +21,23 @@ type Tenant struct {
 
 // TenantRepository interface para operações com tenants
 type TenantRepository interface {
-	Create(tenant *Tenant) error
-	GetByID(id string) (*Tenant, error)
-	Update(tenant *Tenant) error
-	Delete(id string) error
-	List() ([]*Tenant, error)
+	Create(ctx context.Context, tenant *Tenant) error
+	GetByID(ctx context.Context, id string) (*Tenant, error)
+	Update(ctx context.Context, tenant *Tenant) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*Tenant, error)
+}
+
+type tenantContextKey struct{}
+
+// WithTenant associa o tenant ao contexto; os repositórios restringem todas as
+// operações a ele
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext recupera o tenant associado ao contexto
+func TenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantContextKey{}).(string)
+	return tenantID
 }
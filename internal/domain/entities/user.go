@@ -1,28 +1,34 @@
 package entities
 
 import (
+	"context"
 	"time"
 )
 
 // User representa um usuário do sistema
 type User struct {
-	ID           string    `json:"id"`
-	TenantID     string    `json:"tenant_id"`
-	Email        string    `json:"email"`
-	Name         string    `json:"name"`
-	PasswordHash string    `json:"-"`
-	Role         string    `json:"role"` // admin, user
-	Active       bool      `json:"active"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID               string    `json:"id"`
+	TenantID         string    `json:"tenant_id"`
+	Email            string    `json:"email"`
+	Name             string    `json:"name"`
+	PasswordHash     string    `json:"-"`
+	Role             string    `json:"role"` // admin, user
+	Active           bool      `json:"active"`
+	FeedTokenVersion int       `json:"-"` // Incrementada para revogar os links do feed de calendário
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 // UserRepository interface para operações com usuários
 type UserRepository interface {
-	Create(user *User) error
-	GetByID(id string) (*User, error)
-	GetByEmail(email string) (*User, error)
-	Update(user *User) error
-	Delete(id string) error
-	ListByTenant(tenantID string) ([]*User, error)
+	Create(ctx context.Context, user *User) error
+	GetByID(ctx context.Context, id string) (*User, error)
+	// GetByEmail busca em todos os tenants (usado no login)
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	Update(ctx context.Context, user *User) error
+	Delete(ctx context.Context, id string) error
+	ListByTenant(ctx context.Context, tenantID string) ([]*User, error)
+	// RotateFeedToken incrementa FeedTokenVersion do usuário, revogando os links
+	// do feed de calendário, e retorna a nova versão
+	RotateFeedToken(ctx context.Context, id string) (int, error)
 }
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Webhook endpoint HTTP registrado pelo tenant para receber eventos. Os
+// payloads são assinados com HMAC-SHA256 usando Secret, exibido apenas na criação.
+type Webhook struct {
+	ID        string      `json:"id"`
+	TenantID  string      `json:"tenant_id"`
+	URL       string      `json:"url"`
+	Secret    string      `json:"secret,omitempty"`
+	Events    []EventType `json:"events"`
+	Active    bool        `json:"active"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Subscribed indica se o webhook recebe o tipo de evento
+func (w *Webhook) Subscribed(t EventType) bool {
+	for _, e := range w.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus situação da entrega de um evento a um webhook
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"   // Aguardando a primeira tentativa ou nova tentativa
+	DeliverySucceeded DeliveryStatus = "succeeded" // Endpoint respondeu 2xx
+	DeliveryFailed    DeliveryStatus = "failed"    // Tentativas esgotadas
+)
+
+// WebhookDelivery entrega de um evento a um webhook, com o resultado da última
+// tentativa. Reenvios manuais criam uma nova entrega com o mesmo payload.
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	TenantID       string          `json:"tenant_id"`
+	EventID        string          `json:"event_id"`
+	Event          EventType       `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"` // Apenas pendentes
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// WebhookRepository interface para persistência de webhooks e do registro de
+// entregas. As operações de cadastro são escopadas pelo tenant do contexto;
+// ClaimDue e RecordAttempt abrangem todos os tenants e são usadas apenas pelo
+// serviço de entrega.
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *Webhook) error
+	GetByID(ctx context.Context, id string) (*Webhook, error)
+	ListByTenant(ctx context.Context, tenantID string) ([]*Webhook, error)
+	// Update altera URL, eventos e se o webhook está ativo
+	Update(ctx context.Context, webhook *Webhook) error
+	// Delete remove o webhook e as suas entregas
+	Delete(ctx context.Context, id string) error
+	// Subscribers lista os webhooks ativos do tenant inscritos no evento
+	Subscribers(ctx context.Context, tenantID string, event EventType) ([]*Webhook, error)
+
+	CreateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	GetDelivery(ctx context.Context, id string) (*WebhookDelivery, error)
+	// ListDeliveries lista as entregas do webhook, das mais recentes para as mais antigas
+	ListDeliveries(ctx context.Context, webhookID string, page Page) (*PageResult[*WebhookDelivery], error)
+	// ClaimDue reserva até limit entregas pendentes com tentativa prevista até
+	// now, adiando a próxima tentativa para now+lease para que outra instância
+	// não as envie ao mesmo tempo
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error)
+	// RecordAttempt grava o resultado de uma tentativa: status, tentativas,
+	// próxima tentativa, resposta e erro
+	RecordAttempt(ctx context.Context, delivery *WebhookDelivery) error
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"os"
+	"strconv"
+)
+
+// feedSecret chave dos tokens de feed; trocar CALENDAR_FEED_SECRET invalida
+// todos os links já distribuídos
+func feedSecret() []byte {
+	secret := os.Getenv("CALENDAR_FEED_SECRET")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	if secret == "" {
+		secret = "your-256-bit-secret" // Fallback para desenvolvimento
+	}
+	return []byte(secret)
+}
+
+// FeedToken gera o token que autentica o feed de calendário do usuário. Ao
+// contrário do JWT, não expira: aplicativos de calendário não renovam credenciais.
+// version é User.FeedTokenVersion; incrementá-la revoga os tokens anteriores.
+func FeedToken(tenantID, userID string, version int) string {
+	mac := hmac.New(sha256.New, feedSecret())
+	mac.Write([]byte("calendar\x00" + tenantID + "\x00" + userID + "\x00" + strconv.Itoa(version)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyFeedToken confere o token do feed em tempo constante
+func VerifyFeedToken(tenantID, userID string, version int, token string) bool {
+	return hmac.Equal([]byte(FeedToken(tenantID, userID, version)), []byte(token))
+}
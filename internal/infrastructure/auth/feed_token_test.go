@@ -0,0 +1,38 @@
+package auth
+
+import "testing"
+
+func TestVerifyFeedToken(t *testing.T) {
+	t.Setenv("CALENDAR_FEED_SECRET", "segredo-de-teste")
+	token := FeedToken("tenant-1", "user-1", 0)
+
+	tests := []struct {
+		name             string
+		tenantID, userID string
+		version          int
+		token            string
+		want             bool
+	}{
+		{"válido", "tenant-1", "user-1", 0, token, true},
+		{"vazio", "tenant-1", "user-1", 0, "", false},
+		{"adulterado", "tenant-1", "user-1", 0, token[:len(token)-1] + "x", false},
+		{"outro usuário", "tenant-1", "user-2", 0, token, false},
+		{"outro tenant", "tenant-2", "user-1", 0, token, false},
+		{"versão revogada", "tenant-1", "user-1", 1, token, false},
+		// Os separadores impedem deslocar caracteres entre tenant e usuário
+		{"fronteira ambígua", "tenant-1u", "ser-1", 0, token, false},
+	}
+	for _, tt := range tests {
+		if got := VerifyFeedToken(tt.tenantID, tt.userID, tt.version, tt.token); got != tt.want {
+			t.Errorf("%s: VerifyFeedToken = %v, esperado %v", tt.name, got, tt.want)
+		}
+	}
+
+	if FeedToken("tenant-1", "user-1", 1) == token {
+		t.Error("nova versão gerou o mesmo token")
+	}
+	t.Setenv("CALENDAR_FEED_SECRET", "outro-segredo")
+	if VerifyFeedToken("tenant-1", "user-1", 0, token) {
+		t.Error("token aceito após trocar CALENDAR_FEED_SECRET")
+	}
+}
@@ -0,0 +1,202 @@
+// Package calendar gera feeds iCalendar (RFC 5545) com as tarefas dos usuários e
+// lê os convites de reunião recebidos por email.
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// prodID identificador do produto gerador do feed
+const prodID = "-//email-filter//Tarefas//PT"
+
+// meetingDuration duração assumida para eventos sem horário de término
+const meetingDuration = time.Hour
+
+// Item componente do calendário: tarefa (VTODO) ou, quando Event, evento (VEVENT)
+type Item struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time // Início do evento ou prazo da tarefa
+	End         time.Time // Apenas eventos
+	Priority    int       // 1 (alta) a 9 (baixa); 0 indefinida
+	Status      string
+	Event       bool
+	Created     time.Time
+	Modified    time.Time
+}
+
+// TaskItem converte a tarefa em item do feed. Tarefas de emails identificados
+// como reunião viram eventos com início no prazo, exceto quando o email traz o
+// convite: a reunião já está na agenda do usuário e a tarefa (de preparação)
+// continua como VTODO. emailURL, se informado, é o link para o email de origem.
+func TaskItem(task *entities.Task, email *entities.Email, emailURL string) Item {
+	item := Item{
+		UID:      task.ID + "@email-filter",
+		Summary:  task.Description,
+		URL:      emailURL,
+		Start:    task.DueDate,
+		Priority: priority(task.Priority),
+		Status:   todoStatus(task.Status),
+		Created:  task.CreatedAt,
+		Modified: task.UpdatedAt,
+	}
+	if email != nil {
+		item.Description = fmt.Sprintf("Email: %s\nDe: %s", email.Subject, email.From)
+		if m := email.Meeting; m != nil {
+			item.Description += fmt.Sprintf("\nReunião: %s", m.Start.Format("02/01/2006 15:04 MST"))
+			if m.Location != "" {
+				item.Description += "\nLocal: " + m.Location
+			}
+			if m.Cancelled() {
+				item.Description += "\nReunião cancelada"
+			}
+		} else if isMeeting(email) {
+			item.Event = true
+			item.End = task.DueDate.Add(meetingDuration)
+			item.Status = "CONFIRMED"
+		}
+	}
+	return item
+}
+
+// isMeeting indica se o classificador marcou o email como reunião
+func isMeeting(email *entities.Email) bool {
+	for _, label := range email.Labels {
+		if label == "reunião" {
+			return true
+		}
+	}
+	return false
+}
+
+// priority mapeia a prioridade da tarefa para PRIORITY do iCalendar
+func priority(p entities.Priority) int {
+	switch p {
+	case entities.PriorityHigh:
+		return 1
+	case entities.PriorityMedium:
+		return 5
+	case entities.PriorityLow:
+		return 9
+	}
+	return 0
+}
+
+// todoStatus mapeia o status da tarefa para STATUS de VTODO
+func todoStatus(s entities.TaskStatus) string {
+	switch s {
+	case entities.TaskInProgress:
+		return "IN-PROCESS"
+	case entities.TaskCompleted:
+		return "COMPLETED"
+	case entities.TaskCancelled:
+		return "CANCELLED"
+	}
+	return "NEEDS-ACTION"
+}
+
+// Write grava o calendário name com os itens em w
+func Write(w io.Writer, name string, items []Item, now time.Time) error {
+	cw := &contentWriter{w: bufio.NewWriter(w)}
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", prodID)
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("METHOD", "PUBLISH")
+	cw.line("X-WR-CALNAME", escape(name))
+
+	stamp := formatTime(now)
+	for _, item := range items {
+		component := "VTODO"
+		if item.Event {
+			component = "VEVENT"
+		}
+		cw.line("BEGIN", component)
+		cw.line("UID", escape(item.UID))
+		cw.line("DTSTAMP", stamp)
+		cw.line("SUMMARY", escape(item.Summary))
+		if item.Description != "" {
+			cw.line("DESCRIPTION", escape(item.Description))
+		}
+		if item.URL != "" {
+			cw.line("URL", item.URL)
+		}
+		if item.Event {
+			cw.line("DTSTART", formatTime(item.Start))
+			cw.line("DTEND", formatTime(item.End))
+		} else if !item.Start.IsZero() {
+			cw.line("DUE", formatTime(item.Start))
+		}
+		if item.Priority > 0 {
+			cw.line("PRIORITY", fmt.Sprint(item.Priority))
+		}
+		if item.Status != "" {
+			cw.line("STATUS", item.Status)
+		}
+		if !item.Created.IsZero() {
+			cw.line("CREATED", formatTime(item.Created))
+		}
+		if !item.Modified.IsZero() {
+			cw.line("LAST-MODIFIED", formatTime(item.Modified))
+		}
+		cw.line("END", component)
+	}
+
+	cw.line("END", "VCALENDAR")
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+// contentWriter grava linhas de conteúdo terminadas em CRLF, dobradas em 75 octetos
+type contentWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (cw *contentWriter) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+	line := name + ":" + value
+	// As linhas de continuação começam com um espaço, que conta no limite
+	for limit := 75; len(line) > limit; limit = 74 {
+		// Não dividir caracteres UTF-8 de vários bytes
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		if _, cw.err = cw.w.WriteString(line[:cut] + "\r\n "); cw.err != nil {
+			return
+		}
+		line = line[cut:]
+	}
+	_, cw.err = cw.w.WriteString(line + "\r\n")
+}
+
+// escape escapa um valor do tipo TEXT
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// formatTime formata a data em UTC (forma DATE-TIME com Z)
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
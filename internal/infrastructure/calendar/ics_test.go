@@ -0,0 +1,151 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// render grava os itens e retorna as linhas de conteúdo, desdobradas
+func render(t *testing.T, items ...Item) (raw string, lines []string) {
+	t.Helper()
+	var b strings.Builder
+	if err := Write(&b, "Tarefas", items, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	raw = b.String()
+	if !strings.HasSuffix(raw, "\r\n") {
+		t.Fatalf("calendário sem CRLF final: %q", raw)
+	}
+	return raw, strings.Split(strings.ReplaceAll(strings.TrimSuffix(raw, "\r\n"), "\r\n ", ""), "\r\n")
+}
+
+// contentValue retorna o valor da primeira propriedade name
+func contentValue(lines []string, name string) (string, bool) {
+	for _, line := range lines {
+		if value, ok := strings.CutPrefix(line, name+":"); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func TestWriteFoldsLongLines(t *testing.T) {
+	summary := strings.Repeat("Revisar contrato de prestação de serviços ", 6) + "çãé"
+	raw, lines := render(t, Item{UID: "t1@email-filter", Summary: summary})
+
+	for _, line := range strings.Split(strings.TrimSuffix(raw, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("linha com %d octetos: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("caractere UTF-8 dividido na dobra: %q", line)
+		}
+	}
+	if got, _ := contentValue(lines, "SUMMARY"); got != summary {
+		t.Errorf("SUMMARY desdobrado = %q, esperado %q", got, summary)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"simples", "simples"},
+		{`a\b`, `a\\b`},
+		{"a;b,c", `a\;b\,c`},
+		{"linha 1\r\nlinha 2", `linha 1\nlinha 2`},
+		{"linha 1\nlinha 2", `linha 1\nlinha 2`},
+		{"linha 1\rlinha 2", `linha 1\nlinha 2`},
+		{"fim\r", `fim\n`},
+	}
+	for _, tt := range tests {
+		if got := escape(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, esperado %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTaskItem(t *testing.T) {
+	due := time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC)
+	task := &entities.Task{ID: "t1", EmailID: "e1", Description: "Preparar pauta", DueDate: due, Status: entities.TaskPending}
+
+	tests := []struct {
+		name      string
+		priority  entities.Priority
+		status    entities.TaskStatus
+		email     *entities.Email
+		component string
+		props     map[string]string
+	}{
+		{
+			name: "sem email", priority: entities.PriorityHigh, status: entities.TaskPending,
+			component: "VTODO",
+			props:     map[string]string{"PRIORITY": "1", "STATUS": "NEEDS-ACTION", "DUE": "20240305T140000Z"},
+		},
+		{
+			name: "email comum", priority: entities.PriorityMedium, status: entities.TaskInProgress,
+			email:     &entities.Email{Subject: "Contrato", From: "ana@example.com"},
+			component: "VTODO",
+			props:     map[string]string{"PRIORITY": "5", "STATUS": "IN-PROCESS", "DESCRIPTION": `Email: Contrato\nDe: ana@example.com`},
+		},
+		{
+			name: "email de reunião sem convite", priority: entities.PriorityLow, status: entities.TaskPending,
+			email:     &entities.Email{Subject: "Reunião", From: "ana@example.com", Labels: []string{"reunião"}},
+			component: "VEVENT",
+			props: map[string]string{
+				"PRIORITY": "9", "STATUS": "CONFIRMED",
+				"DTSTART": "20240305T140000Z", "DTEND": "20240305T150000Z",
+			},
+		},
+		{
+			name: "email com convite", priority: entities.PriorityHigh, status: entities.TaskPending,
+			email: &entities.Email{
+				Subject: "Convite", From: "ana@example.com", Labels: []string{"reunião"},
+				Meeting: &entities.Meeting{Start: due.Add(time.Hour), Location: "Sala 2"},
+			},
+			component: "VTODO",
+			props: map[string]string{
+				"DUE":         "20240305T140000Z",
+				"DESCRIPTION": `Email: Convite\nDe: ana@example.com\nReunião: 05/03/2024 15:00 UTC\nLocal: Sala 2`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := *task
+			task.Priority, task.Status = tt.priority, tt.status
+			_, lines := render(t, TaskItem(&task, tt.email, "https://app.example.com/dashboard?email=e1"))
+
+			if begin, _ := contentValue(lines[6:], "BEGIN"); begin != tt.component {
+				t.Errorf("componente = %s, esperado %s", begin, tt.component)
+			}
+			for name, want := range tt.props {
+				if got, ok := contentValue(lines, name); got != want {
+					t.Errorf("%s = %q (presente: %v), esperado %q", name, got, ok, want)
+				}
+			}
+			other := "DUE"
+			if tt.component == "VTODO" {
+				other = "DTSTART"
+			}
+			if _, ok := contentValue(lines, other); ok {
+				t.Errorf("%s presente em %s", other, tt.component)
+			}
+			if got, _ := contentValue(lines, "URL"); got != "https://app.example.com/dashboard?email=e1" {
+				t.Errorf("URL = %q", got)
+			}
+		})
+	}
+}
+
+func TestPriorityUndefined(t *testing.T) {
+	_, lines := render(t, TaskItem(&entities.Task{ID: "t1", Description: "Sem prioridade"}, nil, ""))
+	if _, ok := contentValue(lines, "PRIORITY"); ok {
+		t.Error("PRIORITY presente para tarefa sem prioridade")
+	}
+}
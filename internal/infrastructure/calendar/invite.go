@@ -0,0 +1,342 @@
+package calendar
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// ErrNoMeeting indica calendário sem evento (VEVENT) utilizável
+var ErrNoMeeting = errors.New("convite sem evento")
+
+// maxInviteBytes limite de leitura de um anexo text/calendar
+const maxInviteBytes = 1 << 20
+
+// property linha de conteúdo já desdobrada: NOME;PARAM=valor:valor
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// ParseInvite interpreta um convite iCalendar (text/calendar) e retorna a
+// primeira reunião. method é o METHOD do anexo, usado quando o calendário não
+// declara um; sem nenhum dos dois, assume-se REQUEST.
+func ParseInvite(r io.Reader, method string) (*entities.Meeting, error) {
+	props, err := readProperties(io.LimitReader(r, maxInviteBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	meeting := &entities.Meeting{
+		Method: entities.MeetingMethod(strings.ToUpper(method)),
+		Status: entities.MeetingConfirmed,
+	}
+	var duration time.Duration
+	var start, end *property
+	var inEvent, found bool
+	nested := 0 // Profundidade de subcomponentes do evento (ex: VALARM)
+	zones := readTimezones(props)
+	for _, p := range props {
+		switch {
+		case p.name == "BEGIN" && inEvent:
+			nested++
+		case p.name == "END" && nested > 0:
+			nested--
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VEVENT"):
+			inEvent = !found
+		case p.name == "END" && strings.EqualFold(p.value, "VEVENT"):
+			if inEvent {
+				found = true
+			}
+			inEvent = false
+		case p.name == "METHOD" && !inEvent:
+			meeting.Method = entities.MeetingMethod(strings.ToUpper(p.value))
+		case !inEvent || nested > 0:
+			// Componentes fora do evento (VTIMEZONE) e dentro dele (VALARM) são ignorados
+		case p.name == "UID":
+			meeting.UID = p.value
+		case p.name == "SEQUENCE":
+			meeting.Sequence, _ = strconv.Atoi(p.value)
+		case p.name == "SUMMARY":
+			meeting.Summary = unescape(p.value)
+		case p.name == "LOCATION":
+			meeting.Location = unescape(p.value)
+		case p.name == "STATUS":
+			if strings.EqualFold(p.value, "CANCELLED") {
+				meeting.Status = entities.MeetingCancelled
+			}
+		case p.name == "ORGANIZER":
+			meeting.Organizer = mailto(p.value)
+		case p.name == "ATTENDEE":
+			if addr := mailto(p.value); addr != "" {
+				meeting.Attendees = append(meeting.Attendees, addr)
+			}
+		case p.name == "DTSTART":
+			start = &p
+		case p.name == "DTEND":
+			end = &p
+		case p.name == "DURATION":
+			if duration, err = parseDuration(p.value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Datas interpretadas ao final: o VTIMEZONE pode vir depois do evento
+	if start != nil {
+		if meeting.Start, err = parseDateTime(*start, zones); err != nil {
+			return nil, err
+		}
+	}
+	if end != nil {
+		if meeting.End, err = parseDateTime(*end, zones); err != nil {
+			return nil, err
+		}
+	}
+
+	if !found || meeting.UID == "" || meeting.Start.IsZero() {
+		return nil, ErrNoMeeting
+	}
+	if meeting.Method == "" {
+		meeting.Method = entities.MeetingRequest
+	}
+	if meeting.Method == entities.MeetingCancel {
+		meeting.Status = entities.MeetingCancelled
+	}
+	if meeting.End.IsZero() {
+		if duration <= 0 {
+			duration = meetingDuration
+		}
+		meeting.End = meeting.Start.Add(duration)
+	}
+	return meeting, nil
+}
+
+// readProperties lê as linhas de conteúdo, desfazendo as dobras de linha
+func readProperties(r io.Reader) ([]property, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxInviteBytes)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler convite: %v", err)
+	}
+
+	props := make([]property, 0, len(lines))
+	for _, line := range lines {
+		if p, ok := parseProperty(line); ok {
+			props = append(props, p)
+		}
+	}
+	return props, nil
+}
+
+// parseProperty separa nome, parâmetros e valor, respeitando parâmetros entre aspas
+func parseProperty(line string) (property, bool) {
+	p := property{params: make(map[string]string)}
+
+	quoted := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return p, false
+	}
+
+	p.value = line[colon+1:]
+	parts := splitUnquoted(line[:colon], ';')
+	p.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return p, true
+}
+
+// splitUnquoted divide s em sep, ignorando separadores entre aspas
+func splitUnquoted(s string, sep rune) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i, c := range s {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// parseDateTime interpreta DATE-TIME em UTC, com TZID ou flutuante (tratado
+// como UTC), e DATE (meia-noite UTC). O TZID é resolvido por location.
+func parseDateTime(p property, zones map[string]*time.Location) (time.Time, error) {
+	value := p.value
+	if p.params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("data inválida em %s: %s", p.name, value)
+		}
+		return t, nil
+	}
+
+	loc := time.UTC
+	if strings.HasSuffix(value, "Z") {
+		value = strings.TrimSuffix(value, "Z")
+	} else if tzid := p.params["TZID"]; tzid != "" {
+		loc = location(tzid, zones)
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data inválida em %s: %s", p.name, p.value)
+	}
+	return t.UTC(), nil
+}
+
+// location resolve um TZID: nomes IANA, nomes do Windows (Outlook/Exchange)
+// pela tabela windowsZones e, por último, o deslocamento do VTIMEZONE do
+// próprio convite. TZIDs desconhecidos são tratados como UTC.
+func location(tzid string, zones map[string]*time.Location) *time.Location {
+	tzid = strings.TrimPrefix(tzid, "/") // Prefixo de TZIDs globais (RFC 5545)
+	if iana, ok := windowsZones[tzid]; ok {
+		tzid = iana
+	}
+	if l, err := time.LoadLocation(tzid); err == nil {
+		return l
+	}
+	if l, ok := zones[tzid]; ok {
+		return l
+	}
+	return time.UTC
+}
+
+// readTimezones lê os componentes VTIMEZONE do calendário como fusos de
+// deslocamento fixo, usando o TZOFFSETTO da definição STANDARD (ou da
+// DAYLIGHT, se for a única). As regras de horário de verão não são
+// aplicadas; só são consultados para TZIDs sem equivalente IANA.
+func readTimezones(props []property) map[string]*time.Location {
+	zones := make(map[string]*time.Location)
+	var tzid, component string
+	var standard, daylight string
+	for _, p := range props {
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VTIMEZONE"):
+			tzid, standard, daylight = "", "", ""
+		case p.name == "END" && strings.EqualFold(p.value, "VTIMEZONE"):
+			offset := standard
+			if offset == "" {
+				offset = daylight
+			}
+			if seconds, ok := parseOffset(offset); ok && tzid != "" {
+				zones[strings.TrimPrefix(tzid, "/")] = time.FixedZone(tzid, seconds)
+			}
+			tzid = ""
+		case p.name == "BEGIN":
+			component = strings.ToUpper(p.value)
+		case p.name == "END":
+			component = ""
+		case p.name == "TZID":
+			tzid = p.value
+		case p.name == "TZOFFSETTO" && component == "STANDARD" && standard == "":
+			standard = p.value
+		case p.name == "TZOFFSETTO" && component == "DAYLIGHT" && daylight == "":
+			daylight = p.value
+		}
+	}
+	return zones
+}
+
+// parseOffset interpreta um UTC-OFFSET (+hhmm ou -hhmmss) em segundos
+func parseOffset(value string) (int, bool) {
+	if len(value) != 5 && len(value) != 7 || (value[0] != '+' && value[0] != '-') {
+		return 0, false
+	}
+	digits, err := strconv.Atoi(value[1:])
+	if err != nil {
+		return 0, false
+	}
+	if len(value) == 5 {
+		digits *= 100
+	}
+	seconds := digits/10000*3600 + digits/100%100*60 + digits%100
+	if value[0] == '-' {
+		seconds = -seconds
+	}
+	return seconds, true
+}
+
+// parseDuration interpreta durações no formato P[n]W ou P[n]DT[n]H[n]M[n]S
+func parseDuration(value string) (time.Duration, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(value, "+"), "P")
+	if s == value || s == "" {
+		return 0, fmt.Errorf("duração inválida: %s", value)
+	}
+
+	units := map[byte]time.Duration{
+		'W': 7 * 24 * time.Hour,
+		'D': 24 * time.Hour,
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+	}
+	var total time.Duration
+	n := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			n = n*10 + int(c-'0')
+		case c == 'T':
+		case units[c] > 0:
+			total += time.Duration(n) * units[c]
+			n = 0
+		default:
+			return 0, fmt.Errorf("duração inválida: %s", value)
+		}
+	}
+	return total, nil
+}
+
+// mailto extrai o endereço de um valor CAL-ADDRESS (mailto:ana@example.com)
+func mailto(value string) string {
+	if len(value) >= 7 && strings.EqualFold(value[:7], "mailto:") {
+		value = value[7:]
+	}
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// unescape desfaz o escape de valores do tipo TEXT
+func unescape(s string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(s)
+}
@@ -0,0 +1,212 @@
+package calendar
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// invite monta um calendário com as linhas informadas, separadas por CRLF
+func invite(lines ...string) string {
+	return strings.Join(append(append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...), "END:VCALENDAR"), "\r\n") + "\r\n"
+}
+
+// event monta um VEVENT com UID fixo e as propriedades informadas
+func event(lines ...string) []string {
+	return append(append([]string{"BEGIN:VEVENT", "UID:reuniao-1@example.com"}, lines...), "END:VEVENT")
+}
+
+func utc(day, hour, min int) time.Time {
+	return time.Date(2024, 3, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestParseInvite(t *testing.T) {
+	tests := []struct {
+		name      string
+		ics       string
+		method    string // METHOD do Content-Type do anexo
+		want      entities.MeetingMethod
+		cancelled bool
+		start     time.Time
+		end       time.Time
+	}{
+		{
+			name:  "request em UTC",
+			ics:   invite(append([]string{"METHOD:REQUEST"}, event("DTSTART:20240315T140000Z", "DTEND:20240315T150000Z")...)...),
+			want:  entities.MeetingRequest,
+			start: utc(15, 14, 0),
+			end:   utc(15, 15, 0),
+		},
+		{
+			name:   "method do anexo quando o calendário não declara",
+			ics:    invite(event("DTSTART:20240315T140000Z")...),
+			method: "request",
+			want:   entities.MeetingRequest,
+			start:  utc(15, 14, 0),
+			end:    utc(15, 15, 0),
+		},
+		{
+			name:  "sem method assume request",
+			ics:   invite(event("DTSTART:20240315T140000Z", "DURATION:PT30M")...),
+			want:  entities.MeetingRequest,
+			start: utc(15, 14, 0),
+			end:   utc(15, 14, 30),
+		},
+		{
+			name:      "cancel",
+			ics:       invite(append([]string{"METHOD:CANCEL"}, event("SEQUENCE:2", "DTSTART:20240315T140000Z")...)...),
+			want:      entities.MeetingCancel,
+			cancelled: true,
+			start:     utc(15, 14, 0),
+			end:       utc(15, 15, 0),
+		},
+		{
+			name:      "request com status cancelado",
+			ics:       invite(append([]string{"METHOD:REQUEST"}, event("STATUS:CANCELLED", "DTSTART:20240315T140000Z")...)...),
+			want:      entities.MeetingRequest,
+			cancelled: true,
+			start:     utc(15, 14, 0),
+			end:       utc(15, 15, 0),
+		},
+		{
+			name:  "reply",
+			ics:   invite(append([]string{"METHOD:REPLY"}, event("DTSTART:20240315T140000Z", "ATTENDEE;PARTSTAT=ACCEPTED:mailto:bia@example.com")...)...),
+			want:  "REPLY",
+			start: utc(15, 14, 0),
+			end:   utc(15, 15, 0),
+		},
+		{
+			name:  "tzid iana",
+			ics:   invite(event("DTSTART;TZID=America/New_York:20240315T090000", "DTEND;TZID=America/New_York:20240315T100000")...),
+			want:  entities.MeetingRequest,
+			start: utc(15, 13, 0),
+			end:   utc(15, 14, 0),
+		},
+		{
+			name:  "tzid do windows",
+			ics:   invite(event(`DTSTART;TZID="E. South America Standard Time":20240315T090000`, "DTEND;TZID=E. South America Standard Time:20240315T100000")...),
+			want:  entities.MeetingRequest,
+			start: utc(15, 12, 0),
+			end:   utc(15, 13, 0),
+		},
+		{
+			name: "tzid definido só pelo vtimezone, declarado depois do evento",
+			ics: invite(append(event("DTSTART;TZID=Fuso do Cliente:20240315T090000"),
+				"BEGIN:VTIMEZONE", "TZID:Fuso do Cliente",
+				"BEGIN:DAYLIGHT", "TZOFFSETFROM:+0530", "TZOFFSETTO:+0630", "END:DAYLIGHT",
+				"BEGIN:STANDARD", "TZOFFSETFROM:+0630", "TZOFFSETTO:+0530", "END:STANDARD",
+				"END:VTIMEZONE")...),
+			want:  entities.MeetingRequest,
+			start: utc(15, 3, 30),
+			end:   utc(15, 4, 30),
+		},
+		{
+			name:  "tzid desconhecido é tratado como utc",
+			ics:   invite(event("DTSTART;TZID=Fuso Inexistente:20240315T090000")...),
+			want:  entities.MeetingRequest,
+			start: utc(15, 9, 0),
+			end:   utc(15, 10, 0),
+		},
+		{
+			name:  "horário flutuante é tratado como utc",
+			ics:   invite(event("DTSTART:20240315T090000", "DTEND:20240315T093000")...),
+			want:  entities.MeetingRequest,
+			start: utc(15, 9, 0),
+			end:   utc(15, 9, 30),
+		},
+		{
+			name:  "dia inteiro",
+			ics:   invite(event("DTSTART;VALUE=DATE:20240315", "DTEND;VALUE=DATE:20240316")...),
+			want:  entities.MeetingRequest,
+			start: utc(15, 0, 0),
+			end:   utc(16, 0, 0),
+		},
+		{
+			name: "alarme não sobrescreve o evento",
+			ics: invite(event("SUMMARY:Planejamento", "DTSTART:20240315T140000Z",
+				"BEGIN:VALARM", "TRIGGER:-PT15M", "DURATION:PT5M", "SUMMARY:Lembrete", "END:VALARM")...),
+			want:  entities.MeetingRequest,
+			start: utc(15, 14, 0),
+			end:   utc(15, 15, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meeting, err := ParseInvite(strings.NewReader(tt.ics), tt.method)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if meeting.Method != tt.want {
+				t.Errorf("Method = %q, esperado %q", meeting.Method, tt.want)
+			}
+			if meeting.Cancelled() != tt.cancelled {
+				t.Errorf("Cancelled() = %v, esperado %v", meeting.Cancelled(), tt.cancelled)
+			}
+			if !meeting.Start.Equal(tt.start) || !meeting.End.Equal(tt.end) {
+				t.Errorf("período = %s – %s, esperado %s – %s", meeting.Start, meeting.End, tt.start, tt.end)
+			}
+			if meeting.Start.Location() != time.UTC {
+				t.Errorf("Start fora de UTC: %s", meeting.Start.Location())
+			}
+		})
+	}
+}
+
+func TestParseInviteFields(t *testing.T) {
+	ics := invite(append([]string{"METHOD:REQUEST"}, event(
+		"SEQUENCE:3",
+		`SUMMARY:Revisão do contrato\, fase 2`,
+		`LOCATION:Sala 1\; andar 3`,
+		`ORGANIZER;CN="Ana, Vendas":MAILTO:Ana@Example.com`,
+		"ATTENDEE;CN=Bia:mailto:bia@example.com",
+		"ATTENDEE;CUTYPE=ROOM:",
+		"DTSTART:20240315T140000Z",
+	)...)...)
+
+	meeting, err := ParseInvite(strings.NewReader(ics), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meeting.UID != "reuniao-1@example.com" || meeting.Sequence != 3 {
+		t.Errorf("UID/Sequence = %q/%d", meeting.UID, meeting.Sequence)
+	}
+	if meeting.Summary != "Revisão do contrato, fase 2" || meeting.Location != "Sala 1; andar 3" {
+		t.Errorf("Summary/Location = %q/%q", meeting.Summary, meeting.Location)
+	}
+	if meeting.Organizer != "ana@example.com" {
+		t.Errorf("Organizer = %q", meeting.Organizer)
+	}
+	if len(meeting.Attendees) != 1 || meeting.Attendees[0] != "bia@example.com" {
+		t.Errorf("Attendees = %v", meeting.Attendees)
+	}
+}
+
+func TestParseInviteErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		ics       string
+		noMeeting bool
+	}{
+		{"sem evento", invite("METHOD:REQUEST"), true},
+		{"sem uid", invite("BEGIN:VEVENT", "DTSTART:20240315T140000Z", "END:VEVENT"), true},
+		{"sem início", invite(event("SUMMARY:Sem data")...), true},
+		{"data inválida", invite(event("DTSTART:2024-03-15")...), false},
+		{"duração inválida", invite(event("DTSTART:20240315T140000Z", "DURATION:1h")...), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseInvite(strings.NewReader(tt.ics), "")
+			if err == nil {
+				t.Fatal("esperado erro")
+			}
+			if errors.Is(err, ErrNoMeeting) != tt.noMeeting {
+				t.Errorf("err = %v, ErrNoMeeting esperado: %v", err, tt.noMeeting)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Package chat publica notificações de emails no Slack (Block Kit) e no
+// Microsoft Teams (Adaptive Cards) por meio de incoming webhooks.
+package chat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/safehttp"
+)
+
+// maxTasks tarefas listadas na mensagem; as demais são apenas contadas
+const maxTasks = 10
+
+// Message conteúdo da notificação de um email
+type Message struct {
+	Title    string
+	Subject  string
+	From     string
+	Priority entities.Priority
+	Category string
+	Tasks    []entities.Task
+	URL      string // Link para o email no dashboard; vazio sem APP_URL
+}
+
+// EmailMessage monta a notificação do email
+func EmailMessage(e *entities.EmailSummary, url string) *Message {
+	title := "Novo email"
+	switch {
+	case e.Priority == entities.PriorityHigh:
+		title = "Novo email de prioridade alta"
+	case e.Category != "":
+		title = "Novo email de " + e.Category
+	}
+	return &Message{
+		Title:    title,
+		Subject:  e.Subject,
+		From:     e.From,
+		Priority: e.Priority,
+		Category: e.Category,
+		Tasks:    e.Tasks,
+		URL:      url,
+	}
+}
+
+// priorityLabel nome da prioridade exibido nas mensagens
+func priorityLabel(p entities.Priority) string {
+	switch p {
+	case entities.PriorityHigh:
+		return "Alta"
+	case entities.PriorityMedium:
+		return "Média"
+	case entities.PriorityLow:
+		return "Baixa"
+	}
+	return string(p)
+}
+
+// taskLines linhas da lista de tarefas, com o prazo quando houver
+func taskLines(tasks []entities.Task) []string {
+	var lines []string
+	for i, t := range tasks {
+		if i == maxTasks {
+			lines = append(lines, fmt.Sprintf("e mais %d tarefa(s)", len(tasks)-maxTasks))
+			break
+		}
+		line := t.Description
+		if !t.DueDate.IsZero() {
+			line += " (prazo " + t.DueDate.Format("02/01/2006 15:04") + ")"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Client envia as mensagens aos incoming webhooks
+type Client struct {
+	http *http.Client
+}
+
+// NewClient cria o cliente com o tempo limite de cada requisição; conexões à
+// rede interna são recusadas
+func NewClient(timeout time.Duration) *Client {
+	return &Client{http: safehttp.NewClient(timeout)}
+}
+
+// Send publica a mensagem no formato do tipo de integração
+func (c *Client) Send(ctx context.Context, kind entities.IntegrationKind, url string, msg *Message) error {
+	var payload interface{}
+	switch kind {
+	case entities.IntegrationSlack:
+		payload = slackPayload(msg)
+	case entities.IntegrationTeams:
+		payload = teamsPayload(msg)
+	default:
+		return fmt.Errorf("tipo de integração desconhecido: %s", kind)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição para o %s: %v", kind, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao enviar mensagem ao %s: %w", kind, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s respondeu com status %d: %s", kind, resp.StatusCode, strings.TrimSpace(string(text)))
+	}
+	return nil
+}
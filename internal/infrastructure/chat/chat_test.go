@@ -0,0 +1,173 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/safehttp"
+)
+
+// stubServer incoming webhook local que valida a requisição como o Slack ou o
+// Teams e guarda os payloads aceitos
+type stubServer struct {
+	validate func(payload map[string]interface{}) string
+	reply    string
+
+	mu       sync.Mutex
+	payloads []map[string]interface{}
+}
+
+func (s *stubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+		return
+	}
+	var payload map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+		return
+	}
+	if msg := s.validate(payload); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.payloads = append(s.payloads, payload)
+	s.mu.Unlock()
+	w.Write([]byte(s.reply))
+}
+
+// newSlackStub responde como um incoming webhook do Slack: exige text e
+// blocos com tipo
+func newSlackStub() *stubServer {
+	return &stubServer{reply: "ok", validate: func(p map[string]interface{}) string {
+		if text, _ := p["text"].(string); text == "" {
+			return "no_text"
+		}
+		blocks, _ := p["blocks"].([]interface{})
+		if len(blocks) == 0 {
+			return "invalid_blocks"
+		}
+		for _, b := range blocks {
+			if block, _ := b.(map[string]interface{}); block["type"] == nil {
+				return "invalid_blocks"
+			}
+		}
+		return ""
+	}}
+}
+
+// newTeamsStub responde como um incoming webhook do Teams: exige uma mensagem
+// com um Adaptive Card anexado
+func newTeamsStub() *stubServer {
+	return &stubServer{reply: "1", validate: func(p map[string]interface{}) string {
+		if p["type"] != "message" {
+			return "tipo de mensagem inválido"
+		}
+		attachments, _ := p["attachments"].([]interface{})
+		if len(attachments) != 1 {
+			return "anexo ausente"
+		}
+		a, _ := attachments[0].(map[string]interface{})
+		card, _ := a["content"].(map[string]interface{})
+		if a["contentType"] != "application/vnd.microsoft.card.adaptive" || card["type"] != "AdaptiveCard" {
+			return "card inválido"
+		}
+		return ""
+	}}
+}
+
+// newLoopbackClient cliente sem a recusa de loopback de NewClient, para
+// alcançar os servidores de teste
+func newLoopbackClient() *Client {
+	return &Client{http: &http.Client{Timeout: time.Second}}
+}
+
+func testMessage() *Message {
+	return &Message{
+		Title:    "Novo email de prioridade alta",
+		Subject:  "Contrato <revisão>",
+		From:     "cliente@example.com",
+		Priority: entities.PriorityHigh,
+		Category: "trabalho",
+		Tasks:    []entities.Task{{Description: "Revisar contrato", DueDate: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)}},
+		URL:      "https://app.example.com/dashboard?email=1",
+	}
+}
+
+func TestClientSendSlack(t *testing.T) {
+	stub := newSlackStub()
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	if err := newLoopbackClient().Send(context.Background(), entities.IntegrationSlack, srv.URL, testMessage()); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.payloads) != 1 {
+		t.Fatalf("Slack recebeu %d mensagens, esperado 1", len(stub.payloads))
+	}
+	p := stub.payloads[0]
+	if text := p["text"].(string); text != "Novo email de prioridade alta: Contrato &lt;revisão&gt;" {
+		t.Errorf("text = %q", text)
+	}
+	body, _ := json.Marshal(p["blocks"])
+	for _, want := range []string{"Revisar contrato", "Alta", "Abrir email", "https://app.example.com/dashboard?email=1"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("blocos sem %q: %s", want, body)
+		}
+	}
+}
+
+func TestClientSendTeams(t *testing.T) {
+	stub := newTeamsStub()
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	if err := newLoopbackClient().Send(context.Background(), entities.IntegrationTeams, srv.URL, testMessage()); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.payloads) != 1 {
+		t.Fatalf("Teams recebeu %d mensagens, esperado 1", len(stub.payloads))
+	}
+	body, _ := json.Marshal(stub.payloads[0])
+	for _, want := range []string{"Contrato \\u003crevisão\\u003e", "Revisar contrato", "Action.OpenUrl"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("card sem %q: %s", want, body)
+		}
+	}
+}
+
+func TestClientSendReportsRejection(t *testing.T) {
+	stub := newSlackStub()
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	// O Teams usa outro formato: o stub do Slack recusa a mensagem
+	err := newLoopbackClient().Send(context.Background(), entities.IntegrationTeams, srv.URL, testMessage())
+	if err == nil || !strings.Contains(err.Error(), "status 400") || !strings.Contains(err.Error(), "no_text") {
+		t.Errorf("Send = %v, esperado erro com a resposta do servidor", err)
+	}
+}
+
+func TestClientRefusesInternalAddresses(t *testing.T) {
+	stub := newSlackStub()
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	err := NewClient(time.Second).Send(context.Background(), entities.IntegrationSlack, srv.URL, testMessage())
+	if !errors.Is(err, safehttp.ErrForbiddenAddress) {
+		t.Errorf("Send = %v, esperado ErrForbiddenAddress", err)
+	}
+	if len(stub.payloads) != 0 {
+		t.Error("mensagem enviada a endereço de loopback")
+	}
+}
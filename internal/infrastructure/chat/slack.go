@@ -0,0 +1,73 @@
+package chat
+
+import (
+	"strings"
+)
+
+// Limites de texto dos blocos do Slack
+const (
+	slackHeaderMax  = 150
+	slackSectionMax = 3000
+)
+
+// slackEscape escapa os caracteres de controle do mrkdwn do Slack
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackPayload mensagem em Block Kit; text é exibido nas notificações do Slack
+func slackPayload(msg *Message) map[string]interface{} {
+	fields := []map[string]interface{}{
+		slackField("Assunto", msg.Subject),
+		slackField("De", msg.From),
+		slackField("Prioridade", priorityLabel(msg.Priority)),
+	}
+	if msg.Category != "" {
+		fields = append(fields, slackField("Categoria", msg.Category))
+	}
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": truncate(msg.Title, slackHeaderMax)},
+		},
+		{"type": "section", "fields": fields},
+	}
+	if lines := taskLines(msg.Tasks); len(lines) > 0 {
+		text := "*Tarefas*\n• " + slackEscape.Replace(strings.Join(lines, "\n• "))
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": truncate(text, slackSectionMax)},
+		})
+	}
+	if msg.URL != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "actions",
+			"elements": []map[string]interface{}{{
+				"type": "button",
+				"text": map[string]interface{}{"type": "plain_text", "text": "Abrir email"},
+				"url":  msg.URL,
+			}},
+		})
+	}
+
+	return map[string]interface{}{
+		"text":   slackEscape.Replace(msg.Title + ": " + msg.Subject),
+		"blocks": blocks,
+	}
+}
+
+// slackField campo de uma seção; o Slack limita cada campo a 2000 caracteres
+func slackField(name, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "mrkdwn",
+		"text": truncate("*"+name+"*\n"+slackEscape.Replace(value), 2000),
+	}
+}
+
+// truncate limita s a max caracteres, indicando o corte com reticências
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
@@ -0,0 +1,49 @@
+package chat
+
+import (
+	"strings"
+)
+
+// teamsPayload mensagem com um Adaptive Card, no formato aceito pelos
+// incoming webhooks e workflows do Teams
+func teamsPayload(msg *Message) map[string]interface{} {
+	facts := []map[string]interface{}{
+		{"title": "Assunto", "value": msg.Subject},
+		{"title": "De", "value": msg.From},
+		{"title": "Prioridade", "value": priorityLabel(msg.Priority)},
+	}
+	if msg.Category != "" {
+		facts = append(facts, map[string]interface{}{"title": "Categoria", "value": msg.Category})
+	}
+
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": msg.Title, "weight": "Bolder", "size": "Medium", "wrap": true},
+		{"type": "FactSet", "facts": facts},
+	}
+	if lines := taskLines(msg.Tasks); len(lines) > 0 {
+		body = append(body,
+			map[string]interface{}{"type": "TextBlock", "text": "Tarefas", "weight": "Bolder", "wrap": true},
+			map[string]interface{}{"type": "TextBlock", "text": "- " + strings.Join(lines, "\n- "), "wrap": true},
+		)
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if msg.URL != "" {
+		card["actions"] = []map[string]interface{}{
+			{"type": "Action.OpenUrl", "title": "Abrir email", "url": msg.URL},
+		}
+	}
+
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	}
+}
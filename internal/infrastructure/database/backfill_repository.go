@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ entities.BackfillRepository = (*BackfillRepository)(nil)
+
+type BackfillRepository struct {
+	db *Database
+}
+
+func NewBackfillRepository(db *Database) *BackfillRepository {
+	return &BackfillRepository{db: db}
+}
+
+const backfillColumns = `
+	id, tenant_id, user_id, folder, since, until, cursor, status,
+	scanned, classified, failed, last_error, created_at, updated_at`
+
+func (r *BackfillRepository) Create(ctx context.Context, job *entities.BackfillJob) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		query := `
+			INSERT INTO backfill_jobs (
+				tenant_id, user_id, folder, since, until, cursor, status
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, created_at, updated_at`
+
+		err := tx.QueryRow(
+			ctx, query,
+			job.TenantID, job.UserID, job.Folder,
+			job.Since, job.Until, job.Cursor, job.Status,
+		).Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao criar job de backfill: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *BackfillRepository) GetByID(ctx context.Context, id string) (*entities.BackfillJob, error) {
+	var job *entities.BackfillJob
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		job, err = scanBackfillJob(tx.QueryRow(ctx, `SELECT`+backfillColumns+` FROM backfill_jobs WHERE id = $1`, id))
+		if err != nil {
+			return fmt.Errorf("erro ao buscar job de backfill: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *BackfillRepository) UpdateProgress(ctx context.Context, job *entities.BackfillJob) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		query := `
+			UPDATE backfill_jobs SET
+				cursor = $1,
+				scanned = $2,
+				classified = $3,
+				failed = $4,
+				last_error = $5,
+				updated_at = NOW()
+			WHERE id = $6
+			RETURNING updated_at`
+
+		err := tx.QueryRow(
+			ctx, query,
+			job.Cursor, job.Scanned, job.Classified,
+			job.Failed, job.LastError, job.ID,
+		).Scan(&job.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar progresso do backfill: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *BackfillRepository) UpdateStatus(ctx context.Context, id string, status entities.BackfillStatus) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		result, err := tx.Exec(ctx,
+			"UPDATE backfill_jobs SET status = $1, updated_at = NOW() WHERE id = $2",
+			status, id,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar status do backfill: %v", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("job de backfill não encontrado com id: %s", id)
+		}
+		return nil
+	})
+}
+
+func (r *BackfillRepository) ListUnfinished(ctx context.Context, tenantID, userID string) ([]*entities.BackfillJob, error) {
+	query := `SELECT` + backfillColumns + `
+		FROM backfill_jobs
+		WHERE tenant_id = $1 AND user_id = $2 AND status IN ('pending', 'running')
+		ORDER BY created_at ASC`
+
+	var jobs []*entities.BackfillJob
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, tenantID, userID)
+		if err != nil {
+			return fmt.Errorf("erro ao listar jobs de backfill: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			job, err := scanBackfillJob(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao ler job de backfill: %v", err)
+			}
+			jobs = append(jobs, job)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func scanBackfillJob(row pgx.Row) (*entities.BackfillJob, error) {
+	var job entities.BackfillJob
+	err := row.Scan(
+		&job.ID, &job.TenantID, &job.UserID, &job.Folder,
+		&job.Since, &job.Until, &job.Cursor, &job.Status,
+		&job.Scanned, &job.Classified, &job.Failed, &job.LastError,
+		&job.CreatedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
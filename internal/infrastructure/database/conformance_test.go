@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/infrastructure/repotest"
+)
+
+func TestRepositoryConformance(t *testing.T) {
+	db := openTestDatabase(t)
+	repotest.Run(t, func(t *testing.T) *repotest.Repositories {
+		return &repotest.Repositories{
+			Tenants:      NewTenantRepository(db),
+			Users:        NewUserRepository(db),
+			Emails:       NewEmailRepository(db),
+			Tasks:        NewTaskRepository(db),
+			Reminders:    NewReminderRepository(db),
+			Outbox:       NewOutboxRepository(db),
+			Webhooks:     NewWebhookRepository(db),
+			Integrations: NewIntegrationRepository(db),
+			Backfill:     NewBackfillRepository(db),
+			FolderStates: NewFolderStateRepository(db),
+		}
+	})
+}
+
+func TestSeenMessageRepository(t *testing.T) {
+	db := openTestDatabase(t)
+	repo := NewSeenMessageRepository(db)
+	ctx := context.Background()
+	account := "pop3:" + t.Name() + "@" + time.Now().Format("150405.000000")
+
+	if seen, err := repo.IsSeen(ctx, account, "uid-1"); err != nil || seen {
+		t.Fatalf("IsSeen antes de MarkSeen = %v, %v", seen, err)
+	}
+	// MarkSeen é idempotente
+	for i := 0; i < 2; i++ {
+		if err := repo.MarkSeen(ctx, account, "uid-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if seen, err := repo.IsSeen(ctx, account, "uid-1"); err != nil || !seen {
+		t.Errorf("IsSeen após MarkSeen = %v, %v", seen, err)
+	}
+	if seen, err := repo.IsSeen(ctx, account, "uid-2"); err != nil || seen {
+		t.Errorf("IsSeen de outra mensagem = %v, %v", seen, err)
+	}
+}
@@ -3,13 +3,17 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/enzo010/email-filter/internal/domain/entities"
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 )
 
+var _ entities.EmailRepository = (*EmailRepository)(nil)
+
 type EmailRepository struct {
 	db *Database
 }
@@ -18,90 +22,247 @@ func NewEmailRepository(db *Database) *EmailRepository {
 	return &EmailRepository{db: db}
 }
 
-func (r *EmailRepository) Create(ctx context.Context, email *entities.Email) error {
-	// Gerar IDs se não fornecidos
-	if email.TenantID == "" {
-		email.TenantID = uuid.New().String()
+func (r *EmailRepository) Create(ctx context.Context, email *entities.Email) (bool, error) {
+	created, err := r.CreateAll(ctx, []*entities.Email{email})
+	if err != nil {
+		return false, err
 	}
-	if email.UserID == "" {
-		email.UserID = uuid.New().String()
+	return created[0], nil
+}
+
+func (r *EmailRepository) CreateAll(ctx context.Context, emails []*entities.Email) ([]bool, error) {
+	for _, email := range emails {
+		if email.TenantID == "" || email.UserID == "" {
+			return nil, entities.ErrEmailOwnerRequired
+		}
+		if email.ContentHash == "" {
+			email.ContentHash = email.ComputeContentHash()
+		}
 	}
 
-	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		// Inserir email
-		query := `
-			INSERT INTO emails (
-				tenant_id, user_id, subject, from_address, to_address,
-				content, priority, category, processed_at
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-			RETURNING id, created_at, updated_at`
+	created := make([]bool, len(emails))
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		for i, email := range emails {
+			var err error
+			if created[i], err = r.insert(ctx, tx, email); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		err := tx.QueryRow(
-			ctx, query,
-			email.TenantID, email.UserID, email.Subject,
-			email.From, email.To, email.Content,
-			email.Priority, email.Category, email.ProcessedAt,
-		).Scan(&email.ID, &email.CreatedAt, &email.UpdatedAt)
+	return created, nil
+}
 
-		if err != nil {
-			return fmt.Errorf("erro ao inserir email: %v", err)
+// insert grava o email com labels, tarefas, reunião e eventos do outbox.
+// Retorna false sem erro se a mensagem já estava armazenada no tenant.
+func (r *EmailRepository) insert(ctx context.Context, tx pgx.Tx, email *entities.Email) (bool, error) {
+	// Inserir email, ignorando mensagens já armazenadas no tenant
+	query := `
+		INSERT INTO emails (
+			tenant_id, user_id, message_id, content_hash, subject, from_address,
+			to_address, content, folder, priority, category, processed_at
+		) VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at, updated_at`
+
+	err := tx.QueryRow(
+		ctx, query,
+		email.TenantID, email.UserID, email.MessageID, email.ContentHash,
+		email.Subject, email.From, email.To, email.Content, email.Folder,
+		email.Priority, email.Category, email.ProcessedAt,
+	).Scan(&email.ID, &email.CreatedAt, &email.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Conflito: carregar o registro existente
+		return false, r.findExisting(ctx, tx, email)
+	}
+	if err != nil {
+		return false, fmt.Errorf("erro ao inserir email: %v", err)
+	}
+
+	// Inserir labels
+	if len(email.Labels) > 0 {
+		for _, label := range email.Labels {
+			_, err = tx.Exec(ctx,
+				"INSERT INTO email_labels (email_id, label) VALUES ($1, $2)",
+				email.ID, label,
+			)
+			if err != nil {
+				return false, fmt.Errorf("erro ao inserir label: %v", err)
+			}
 		}
+	}
 
-		// Inserir labels
-		if len(email.Labels) > 0 {
-			for _, label := range email.Labels {
-				_, err = tx.Exec(ctx,
-					"INSERT INTO email_labels (email_id, label) VALUES ($1, $2)",
-					email.ID, label,
-				)
-				if err != nil {
-					return fmt.Errorf("erro ao inserir label: %v", err)
-				}
+	// Inserir tarefas
+	if len(email.Tasks) > 0 {
+		for i := range email.Tasks {
+			task := &email.Tasks[i]
+			task.EmailID = email.ID
+			query = `
+				INSERT INTO tasks (
+					email_id, description, due_date,
+					priority, status
+				) VALUES ($1, $2, $3, $4, $5)
+				RETURNING id, created_at, updated_at`
+
+			err = tx.QueryRow(
+				ctx, query,
+				email.ID, task.Description, task.DueDate,
+				task.Priority, task.Status,
+			).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt)
+
+			if err != nil {
+				return false, fmt.Errorf("erro ao inserir tarefa: %v", err)
 			}
 		}
+	}
 
-		// Inserir tarefas
-		if len(email.Tasks) > 0 {
-			for i := range email.Tasks {
-				task := &email.Tasks[i]
-				query = `
-					INSERT INTO tasks (
-						email_id, description, due_date,
-						priority, status
-					) VALUES ($1, $2, $3, $4, $5)
-					RETURNING id, created_at, updated_at`
+	if err := insertMeeting(ctx, tx, email); err != nil {
+		return false, err
+	}
 
-				err = tx.QueryRow(
-					ctx, query,
-					email.ID, task.Description, task.DueDate,
-					task.Priority, task.Status,
-				).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt)
+	// Eventos publicados pelo dispatcher do outbox após o commit
+	events, err := entities.EmailEvents(email)
+	if err != nil {
+		return false, err
+	}
+	return true, insertOutbox(ctx, tx, events)
+}
 
-				if err != nil {
-					return fmt.Errorf("erro ao inserir tarefa: %v", err)
-				}
-			}
+// insertMeeting grava o convite de reunião do email. Um cancelamento também
+// marca como canceladas as versões anteriores da mesma reunião no tenant, e
+// qualquer nova versão cancela as tarefas de preparação das anteriores.
+func insertMeeting(ctx context.Context, tx pgx.Tx, email *entities.Email) error {
+	m := email.Meeting
+	if m == nil {
+		return nil
+	}
+	attendees := m.Attendees
+	if attendees == nil {
+		attendees = []string{}
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO email_meetings (
+			email_id, uid, sequence, method, status, summary,
+			organizer, location, starts_at, ends_at, attendees
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		email.ID, m.UID, m.Sequence, m.Method, m.Status, m.Summary,
+		m.Organizer, m.Location, m.Start, m.End, attendees,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir reunião: %v", err)
+	}
+
+	if m.Cancelled() {
+		_, err = tx.Exec(ctx, `
+			UPDATE email_meetings SET status = $1
+			WHERE uid = $2 AND sequence <= $3
+			  AND email_id IN (SELECT id FROM emails WHERE tenant_id = $4)`,
+			entities.MeetingCancelled, m.UID, m.Sequence, email.TenantID,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao cancelar reunião: %v", err)
 		}
+	}
 
-		return nil
-	})
+	return cancelPrepTasks(ctx, tx, email)
+}
+
+// cancelPrepTasks cancela as tarefas de preparação abertas das versões
+// anteriores da reunião do email, registrando a mudança no histórico
+func cancelPrepTasks(ctx context.Context, tx pgx.Tx, email *entities.Email) error {
+	m := email.Meeting
+	_, err := tx.Exec(ctx, `
+		WITH stale AS (
+			SELECT t.id, t.status FROM tasks t
+			JOIN emails e ON e.id = t.email_id
+			JOIN email_meetings m ON m.email_id = t.email_id
+			WHERE e.tenant_id = $1 AND m.uid = $2 AND m.sequence <= $3 AND t.email_id <> $4
+			  AND t.status IN ('pending', 'in_progress', 'snoozed')
+			  AND starts_with(t.description, $5)
+			FOR UPDATE OF t
+		), cancelled AS (
+			UPDATE tasks t SET status = 'cancelled', snoozed_until = NULL, updated_at = NOW()
+			FROM stale WHERE t.id = stale.id
+			RETURNING t.id, stale.status
+		)
+		INSERT INTO task_status_history (task_id, from_status, to_status, note)
+		SELECT id, status, 'cancelled', $6 FROM cancelled`,
+		email.TenantID, m.UID, m.Sequence, email.ID, entities.MeetingPrepTask, m.SupersededNote(),
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao cancelar tarefas da reunião: %v", err)
+	}
+	return nil
+}
+
+// getMeeting busca o convite de reunião do email, se houver
+func getMeeting(ctx context.Context, tx pgx.Tx, emailID string) (*entities.Meeting, error) {
+	m := &entities.Meeting{}
+	err := tx.QueryRow(ctx, `
+		SELECT uid, sequence, method, status, summary, organizer,
+			   location, starts_at, ends_at, attendees
+		FROM email_meetings WHERE email_id = $1`,
+		emailID,
+	).Scan(
+		&m.UID, &m.Sequence, &m.Method, &m.Status, &m.Summary, &m.Organizer,
+		&m.Location, &m.Start, &m.End, &m.Attendees,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar reunião: %v", err)
+	}
+	return m, nil
+}
+
+// findExisting preenche email com o registro que causou o conflito na inserção
+func (r *EmailRepository) findExisting(ctx context.Context, tx pgx.Tx, email *entities.Email) error {
+	query := `
+		SELECT id, created_at, updated_at FROM emails
+		WHERE tenant_id = $1 AND message_id = $2`
+	args := []interface{}{email.TenantID, email.MessageID}
+	if email.MessageID == "" {
+		query = `
+			SELECT id, created_at, updated_at FROM emails
+			WHERE tenant_id = $1 AND message_id IS NULL AND content_hash = $2`
+		args = []interface{}{email.TenantID, email.ContentHash}
+	}
+
+	err := tx.QueryRow(ctx, query, args...).Scan(&email.ID, &email.CreatedAt, &email.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar email existente: %v", err)
+	}
+	return nil
 }
 
 func (r *EmailRepository) GetByID(ctx context.Context, id string) (*entities.Email, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	email := &entities.Email{}
 
-	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		// Buscar email
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// Buscar email (o filtro por tenant complementa as políticas de RLS)
 		query := `
-			SELECT id, tenant_id, user_id, subject, from_address,
-				   to_address, content, priority, category,
-				   processed_at, created_at, updated_at
-			FROM emails WHERE id = $1`
+			SELECT id, tenant_id, user_id, COALESCE(message_id, ''), content_hash,
+				   subject, from_address, to_address, content, folder,
+				   priority, category, processed_at, created_at, updated_at
+			FROM emails WHERE id = $1 AND tenant_id = $2`
 
-		err := tx.QueryRow(ctx, query, id).Scan(
+		err := tx.QueryRow(ctx, query, id, tenantID).Scan(
 			&email.ID, &email.TenantID, &email.UserID,
+			&email.MessageID, &email.ContentHash,
 			&email.Subject, &email.From, &email.To,
-			&email.Content, &email.Priority, &email.Category,
+			&email.Content, &email.Folder, &email.Priority, &email.Category,
 			&email.ProcessedAt, &email.CreatedAt, &email.UpdatedAt,
 		)
 		if err != nil {
@@ -128,7 +289,7 @@ func (r *EmailRepository) GetByID(ctx context.Context, id string) (*entities.Ema
 
 		// Buscar tarefas
 		rows, err = tx.Query(ctx, `
-			SELECT id, description, due_date, priority,
+			SELECT id, email_id, description, due_date, priority,
 				   status, created_at, updated_at
 			FROM tasks WHERE email_id = $1`,
 			id,
@@ -141,7 +302,7 @@ func (r *EmailRepository) GetByID(ctx context.Context, id string) (*entities.Ema
 		for rows.Next() {
 			var task entities.Task
 			if err := rows.Scan(
-				&task.ID, &task.Description, &task.DueDate,
+				&task.ID, &task.EmailID, &task.Description, &task.DueDate,
 				&task.Priority, &task.Status,
 				&task.CreatedAt, &task.UpdatedAt,
 			); err != nil {
@@ -150,7 +311,8 @@ func (r *EmailRepository) GetByID(ctx context.Context, id string) (*entities.Ema
 			email.Tasks = append(email.Tasks, task)
 		}
 
-		return nil
+		email.Meeting, err = getMeeting(ctx, tx, id)
+		return err
 	})
 
 	if err != nil {
@@ -160,125 +322,133 @@ func (r *EmailRepository) GetByID(ctx context.Context, id string) (*entities.Ema
 	return email, nil
 }
 
-func (r *EmailRepository) ListByTenant(ctx context.Context, tenantID string, filters map[string]interface{}) ([]*entities.Email, error) {
-	var emails []*entities.Email
-
-	// Extrair parâmetros de paginação
-	page := 1
-	pageSize := 20
-	if p, ok := filters["page"].(int); ok && p > 0 {
-		page = p
-	}
-	if ps, ok := filters["page_size"].(int); ok && ps > 0 && ps <= 100 {
-		pageSize = ps
-	}
-	offset := (page - 1) * pageSize
-
-	// Construir query base
-	query := `
-		WITH filtered_emails AS (
-			SELECT id, tenant_id, user_id, subject, from_address,
-				   to_address, content, priority, category,
-				   processed_at, created_at, updated_at
-			FROM emails
-			WHERE tenant_id = $1`
-
-	// Adicionar filtros
-	args := []interface{}{tenantID}
-	argCount := 2
-
-	if category, ok := filters["category"]; ok {
-		query += fmt.Sprintf(" AND category = $%d", argCount)
-		args = append(args, category)
-		argCount++
+func (r *EmailRepository) ListByTenant(ctx context.Context, tenantID string, filter *entities.EmailFilter) (*entities.PageResult[*entities.Email], error) {
+	if filter == nil {
+		filter = &entities.EmailFilter{}
 	}
-
-	if priority, ok := filters["priority"]; ok {
-		query += fmt.Sprintf(" AND priority = $%d", argCount)
-		args = append(args, priority)
-		argCount++
+	if err := filter.Validate(); err != nil {
+		return nil, err
 	}
-
-	if startDate, ok := filters["start_date"]; ok {
-		query += fmt.Sprintf(" AND created_at >= $%d", argCount)
-		args = append(args, startDate)
-		argCount++
+	p, err := entities.NewPager(filter.Page, filter.Sort == entities.EmailSortCreatedAt)
+	if err != nil {
+		return nil, err
 	}
+	order := p.Order(filter.Order)
 
-	if endDate, ok := filters["end_date"]; ok {
-		query += fmt.Sprintf(" AND created_at <= $%d", argCount)
-		args = append(args, endDate)
-		argCount++
-	}
+	var emails []*entities.Email
+	var total *int64
 
-	// Adicionar ordenação e paginação
-	query += fmt.Sprintf(`
-		ORDER BY created_at DESC
-		LIMIT $%d OFFSET $%d
-	)`, argCount, argCount+1)
-	args = append(args, pageSize, offset)
+	// Filtrar e paginar antes de agregar labels e tarefas
+	b := &queryBuilder{}
+	b.where("emails.tenant_id = %s", tenantID)
+	applyEmailFilter(b, filter, "emails.")
+	countQuery, countArgs := b.count("emails")
+	cursorWhere(b, p, filter.Order, "emails.")
 
-	// Adicionar joins para labels e tasks
-	query += `
+	query := `
+		WITH filtered_emails AS (
+			SELECT id, tenant_id, user_id, COALESCE(message_id, '') AS message_id,
+				   content_hash, subject, from_address, to_address, content,
+				   folder, priority, category, processed_at, created_at, updated_at
+			FROM emails` + b.whereClause() +
+		orderBy(emailSortExpr(filter.Sort, "emails."), order, "emails.") +
+		pageLimit(b, p) + `
+		)
 		SELECT
 			e.*,
 			ARRAY_AGG(DISTINCT el.label) FILTER (WHERE el.label IS NOT NULL) as labels,
-			ARRAY_AGG(DISTINCT jsonb_build_object(
+			jsonb_agg(DISTINCT jsonb_build_object(
 				'id', t.id,
+				'email_id', t.email_id,
 				'description', t.description,
 				'due_date', t.due_date,
 				'priority', t.priority,
 				'status', t.status,
 				'created_at', t.created_at,
 				'updated_at', t.updated_at
-			)) FILTER (WHERE t.id IS NOT NULL) as tasks
+			)) FILTER (WHERE t.id IS NOT NULL) as tasks,
+			(SELECT jsonb_build_object(
+				'uid', m.uid,
+				'sequence', m.sequence,
+				'method', m.method,
+				'status', m.status,
+				'summary', m.summary,
+				'organizer', m.organizer,
+				'location', m.location,
+				'start', m.starts_at,
+				'end', m.ends_at,
+				'attendees', to_jsonb(m.attendees)
+			) FROM email_meetings m WHERE m.email_id = e.id) as meeting
 		FROM filtered_emails e
 		LEFT JOIN email_labels el ON e.id = el.email_id
 		LEFT JOIN tasks t ON e.id = t.email_id
-		GROUP BY e.id, e.tenant_id, e.user_id, e.subject, e.from_address,
-				 e.to_address, e.content, e.priority, e.category,
-				 e.processed_at, e.created_at, e.updated_at`
-
-	rows, err := r.db.pool.Query(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao listar emails: %v", err)
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		email := &entities.Email{}
-		var labelsArray []string
-		var tasksJson []byte
+		GROUP BY e.id, e.tenant_id, e.user_id, e.message_id, e.content_hash, e.subject, e.from_address,
+				 e.to_address, e.content, e.folder, e.priority, e.category,
+				 e.processed_at, e.created_at, e.updated_at` +
+		orderBy(emailSortExpr(filter.Sort, "e."), order, "e.")
+	args := b.args
+
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		if total, err = countTotal(ctx, tx, filter.Page, countQuery, countArgs); err != nil {
+			return err
+		}
 
-		err := rows.Scan(
-			&email.ID, &email.TenantID, &email.UserID,
-			&email.Subject, &email.From, &email.To,
-			&email.Content, &email.Priority, &email.Category,
-			&email.ProcessedAt, &email.CreatedAt, &email.UpdatedAt,
-			&labelsArray, &tasksJson,
-		)
+		rows, err := tx.Query(ctx, query, args...)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao ler email: %v", err)
+			return fmt.Errorf("erro ao listar emails: %v", err)
 		}
+		defer rows.Close()
 
-		// Processar labels
-		if labelsArray != nil {
-			email.Labels = labelsArray
-		}
+		for rows.Next() {
+			email := &entities.Email{}
+			var labelsArray []string
+			var tasksJson, meetingJson []byte
+
+			err := rows.Scan(
+				&email.ID, &email.TenantID, &email.UserID,
+				&email.MessageID, &email.ContentHash,
+				&email.Subject, &email.From, &email.To,
+				&email.Content, &email.Folder, &email.Priority, &email.Category,
+				&email.ProcessedAt, &email.CreatedAt, &email.UpdatedAt,
+				&labelsArray, &tasksJson, &meetingJson,
+			)
+			if err != nil {
+				return fmt.Errorf("erro ao ler email: %v", err)
+			}
 
-		// Processar tasks
-		if tasksJson != nil {
-			var tasks []entities.Task
-			if err := json.Unmarshal(tasksJson, &tasks); err != nil {
-				return nil, fmt.Errorf("erro ao decodificar tasks: %v", err)
+			// Processar labels
+			if labelsArray != nil {
+				email.Labels = labelsArray
+			}
+
+			// Processar tasks
+			if tasksJson != nil {
+				var tasks []entities.Task
+				if err := json.Unmarshal(tasksJson, &tasks); err != nil {
+					return fmt.Errorf("erro ao decodificar tasks: %v", err)
+				}
+				email.Tasks = tasks
+			}
+
+			// Processar reunião
+			if meetingJson != nil {
+				if err := json.Unmarshal(meetingJson, &email.Meeting); err != nil {
+					return fmt.Errorf("erro ao decodificar reunião: %v", err)
+				}
 			}
-			email.Tasks = tasks
-		}
 
-		emails = append(emails, email)
+			emails = append(emails, email)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return emails, nil
+	result := entities.Paginate(p, emails, emailKey)
+	result.Total = total
+	return result, nil
 }
 
 func (r *EmailRepository) Update(ctx context.Context, email *entities.Email) error {
@@ -290,19 +460,20 @@ func (r *EmailRepository) Update(ctx context.Context, email *entities.Email) err
 				from_address = $2,
 				to_address = $3,
 				content = $4,
-				priority = $5,
-				category = $6,
-				processed_at = $7,
+				folder = $5,
+				priority = $6,
+				category = $7,
+				processed_at = $8,
 				updated_at = NOW()
-			WHERE id = $8 AND tenant_id = $9 AND user_id = $10
+			WHERE id = $9 AND tenant_id = $10 AND user_id = $11
 			RETURNING updated_at`
 
 		err := tx.QueryRow(
 			ctx, query,
 			email.Subject, email.From, email.To,
-			email.Content, email.Priority, email.Category,
-			email.ProcessedAt, email.ID, email.TenantID,
-			email.UserID,
+			email.Content, email.Folder, email.Priority,
+			email.Category, email.ProcessedAt, email.ID,
+			email.TenantID, email.UserID,
 		).Scan(&email.UpdatedAt)
 
 		if err != nil {
@@ -335,6 +506,7 @@ func (r *EmailRepository) Update(ctx context.Context, email *entities.Email) err
 		// Atualizar tarefas existentes e adicionar novas
 		for i := range email.Tasks {
 			task := &email.Tasks[i]
+			task.EmailID = email.ID
 			if task.ID != "" {
 				// Atualizar tarefa existente
 				query = `
@@ -383,9 +555,27 @@ func (r *EmailRepository) Update(ctx context.Context, email *entities.Email) err
 }
 
 func (r *EmailRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
 	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// Verificar se o email pertence ao tenant
+		var exists bool
+		err := tx.QueryRow(ctx,
+			"SELECT EXISTS(SELECT 1 FROM emails WHERE id = $1 AND tenant_id = $2)",
+			id, tenantID,
+		).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("erro ao verificar email: %v", err)
+		}
+		if !exists {
+			return fmt.Errorf("email não encontrado com id: %s", id)
+		}
+
 		// Deletar labels primeiro devido à chave estrangeira
-		_, err := tx.Exec(ctx,
+		_, err = tx.Exec(ctx,
 			"DELETE FROM email_labels WHERE email_id = $1",
 			id,
 		)
@@ -404,8 +594,8 @@ func (r *EmailRepository) Delete(ctx context.Context, id string) error {
 
 		// Deletar o email
 		result, err := tx.Exec(ctx,
-			"DELETE FROM emails WHERE id = $1",
-			id,
+			"DELETE FROM emails WHERE id = $1 AND tenant_id = $2",
+			id, tenantID,
 		)
 		if err != nil {
 			return fmt.Errorf("erro ao deletar email: %v", err)
@@ -419,64 +609,183 @@ func (r *EmailRepository) Delete(ctx context.Context, id string) error {
 	})
 }
 
-func (r *EmailRepository) ListByUser(ctx context.Context, userID string, filters map[string]interface{}) ([]*entities.Email, error) {
+func (r *EmailRepository) ListByUser(ctx context.Context, userID string, filter *entities.EmailFilter) (*entities.PageResult[*entities.Email], error) {
+	if filter == nil {
+		filter = &entities.EmailFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	p, err := entities.NewPager(filter.Page, filter.Sort == entities.EmailSortCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
 	var emails []*entities.Email
+	var total *int64
+
+	b := &queryBuilder{}
+	b.where("emails.user_id = %s", userID)
+	applyEmailFilter(b, filter, "emails.")
+	countQuery, countArgs := b.count("emails")
+	cursorWhere(b, p, filter.Order, "emails.")
 
 	query := `
-		SELECT id, tenant_id, user_id, subject, from_address,
-			   to_address, content, priority, category,
-			   processed_at, created_at, updated_at
-		FROM emails 
-		WHERE user_id = $1`
+		SELECT id, tenant_id, user_id, COALESCE(message_id, ''), content_hash,
+			   subject, from_address, to_address, content, folder,
+			   priority, category, processed_at, created_at, updated_at
+		FROM emails` + b.whereClause() +
+		orderBy(emailSortExpr(filter.Sort, "emails."), p.Order(filter.Order), "emails.") +
+		pageLimit(b, p)
+	args := b.args
+
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		if total, err = countTotal(ctx, tx, filter.Page, countQuery, countArgs); err != nil {
+			return err
+		}
 
-	// Adicionar filtros
-	args := []interface{}{userID}
-	argCount := 2
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("erro ao listar emails: %v", err)
+		}
+		defer rows.Close()
 
-	if category, ok := filters["category"]; ok {
-		query += fmt.Sprintf(" AND category = $%d", argCount)
-		args = append(args, category)
-		argCount++
+		for rows.Next() {
+			email := &entities.Email{}
+			err := rows.Scan(
+				&email.ID, &email.TenantID, &email.UserID,
+				&email.MessageID, &email.ContentHash,
+				&email.Subject, &email.From, &email.To,
+				&email.Content, &email.Folder, &email.Priority, &email.Category,
+				&email.ProcessedAt, &email.CreatedAt, &email.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("erro ao ler email: %v", err)
+			}
+			emails = append(emails, email)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if priority, ok := filters["priority"]; ok {
-		query += fmt.Sprintf(" AND priority = $%d", argCount)
-		args = append(args, priority)
-		argCount++
-	}
+	result := entities.Paginate(p, emails, emailKey)
+	result.Total = total
+	return result, nil
+}
 
-	if startDate, ok := filters["start_date"]; ok {
-		query += fmt.Sprintf(" AND created_at >= $%d", argCount)
-		args = append(args, startDate)
-		argCount++
-	}
+// emailKey chave de paginação de um email
+func emailKey(e *entities.Email) (time.Time, string) {
+	return e.CreatedAt, e.ID
+}
+
+// searchHeadlineOptions marca os termos encontrados nos trechos retornados
+const searchHeadlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" … "`
 
-	if endDate, ok := filters["end_date"]; ok {
-		query += fmt.Sprintf(" AND created_at <= $%d", argCount)
-		args = append(args, endDate)
+func (r *EmailRepository) Search(ctx context.Context, tenantID string, query *entities.EmailSearchQuery, page entities.Page) (*entities.PageResult[*entities.EmailSearchResult], error) {
+	// Resultados por relevância são paginados por deslocamento
+	if err := page.Normalize(); err != nil {
+		return nil, err
+	}
+	p, err := entities.NewPager(page, false)
+	if err != nil {
+		return nil, err
 	}
 
-	query += " ORDER BY created_at DESC LIMIT 100"
+	b := &queryBuilder{}
+
+	// Sem termos de busca (apenas from:/label:), ordenar pelos mais recentes
+	selectRank := fmt.Sprintf(`0::float8 AS rank, %s AS subject_highlight, %s AS highlight`,
+		escapeHTML("e.subject"), escapeHTML("LEFT(COALESCE(e.content, ''), 200)"))
+	from := "emails e"
+	order := "e.created_at DESC"
+
+	if query.Text != "" {
+		// A consulta é avaliada nas duas línguas indexadas
+		text := b.arg(query.Text)
+		from = fmt.Sprintf(`emails e, (
+			SELECT websearch_to_tsquery('portuguese', %s) || websearch_to_tsquery('english', %s) AS query
+		) q`, text, text)
+
+		// O texto é escapado antes do ts_headline: apenas as marcações <mark> são HTML
+		selectRank = fmt.Sprintf(`ts_rank_cd(e.search_vector, q.query) AS rank,
+			ts_headline('portuguese', %s, q.query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS subject_highlight,
+			ts_headline('portuguese', %s, q.query, '%s') AS highlight`,
+			escapeHTML("e.subject"), escapeHTML("COALESCE(e.content, '')"), searchHeadlineOptions)
+		b.conditions = append(b.conditions, "e.search_vector @@ q.query")
+		order = "rank DESC, e.created_at DESC"
+	}
 
-	rows, err := r.db.pool.Query(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao listar emails: %v", err)
+	b.where("e.tenant_id = %s", tenantID)
+	for _, sender := range query.From {
+		b.where("e.from_address ILIKE %s", "%"+escapeLike(sender)+"%")
+	}
+	for _, label := range query.Labels {
+		b.where("EXISTS (SELECT 1 FROM email_labels el WHERE el.email_id = e.id AND el.label = %s)", label)
 	}
-	defer rows.Close()
+	countQuery, countArgs := b.count(from)
+
+	sql := fmt.Sprintf(`
+		SELECT e.id, e.tenant_id, e.user_id, COALESCE(e.message_id, ''), e.content_hash,
+			   e.subject, e.from_address, e.to_address, e.content, e.folder,
+			   e.priority, e.category, e.processed_at, e.created_at, e.updated_at,
+			   %s
+		FROM %s%s
+		ORDER BY %s, e.id`,
+		selectRank, from, b.whereClause(), order) + pageLimit(b, p)
+	args := b.args
+
+	var results []*entities.EmailSearchResult
+	var total *int64
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		if total, err = countTotal(ctx, tx, page, countQuery, countArgs); err != nil {
+			return err
+		}
 
-	for rows.Next() {
-		email := &entities.Email{}
-		err := rows.Scan(
-			&email.ID, &email.TenantID, &email.UserID,
-			&email.Subject, &email.From, &email.To,
-			&email.Content, &email.Priority, &email.Category,
-			&email.ProcessedAt, &email.CreatedAt, &email.UpdatedAt,
-		)
+		rows, err := tx.Query(ctx, sql, args...)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao ler email: %v", err)
+			return fmt.Errorf("erro ao buscar emails: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			email := &entities.Email{}
+			result := &entities.EmailSearchResult{Email: email}
+			err := rows.Scan(
+				&email.ID, &email.TenantID, &email.UserID,
+				&email.MessageID, &email.ContentHash,
+				&email.Subject, &email.From, &email.To,
+				&email.Content, &email.Folder, &email.Priority, &email.Category,
+				&email.ProcessedAt, &email.CreatedAt, &email.UpdatedAt,
+				&result.Rank, &result.Subject, &result.Highlight,
+			)
+			if err != nil {
+				return fmt.Errorf("erro ao ler email: %v", err)
+			}
+			results = append(results, result)
 		}
-		emails = append(emails, email)
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return emails, nil
+	result := entities.Paginate(p, results, func(r *entities.EmailSearchResult) (time.Time, string) {
+		return emailKey(r.Email)
+	})
+	result.Total = total
+	return result, nil
+}
+
+// escapeHTML retorna a expressão SQL com os caracteres especiais do HTML escapados
+func escapeHTML(expr string) string {
+	return fmt.Sprintf(`replace(replace(replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;')`, expr)
+}
+
+// escapeLike escapa os curingas do LIKE em valores informados pelo usuário
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
 }
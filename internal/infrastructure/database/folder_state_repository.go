@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ entities.FolderStateRepository = (*FolderStateRepository)(nil)
+
+// FolderStateRepository progresso das pastas IMAP por conta, sem tenant: as
+// políticas de RLS só liberam a tabela no escopo de sistema
+type FolderStateRepository struct {
+	db *Database
+}
+
+func NewFolderStateRepository(db *Database) *FolderStateRepository {
+	return &FolderStateRepository{db: db}
+}
+
+func (r *FolderStateRepository) GetFolderState(ctx context.Context, account, folder string) (*entities.FolderState, error) {
+	state := &entities.FolderState{Account: account, Folder: folder}
+	err := r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		return tx.QueryRow(ctx,
+			"SELECT uid_validity, last_uid FROM folder_states WHERE account = $1 AND folder = $2",
+			account, folder,
+		).Scan(&state.UIDValidity, &state.LastUID)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar estado da pasta: %v", err)
+	}
+	return state, nil
+}
+
+func (r *FolderStateRepository) SaveFolderState(ctx context.Context, state *entities.FolderState) error {
+	err := r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO folder_states (account, folder, uid_validity, last_uid)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (account, folder) DO UPDATE SET
+				uid_validity = EXCLUDED.uid_validity,
+				last_uid = EXCLUDED.last_uid,
+				updated_at = NOW()`,
+			state.Account, state.Folder, state.UIDValidity, state.LastUID,
+		)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao salvar estado da pasta: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ entities.IntegrationRepository = (*IntegrationRepository)(nil)
+
+// IntegrationRepository acessa as integrações de notificações do tenant
+type IntegrationRepository struct {
+	db *Database
+}
+
+func NewIntegrationRepository(db *Database) *IntegrationRepository {
+	return &IntegrationRepository{db: db}
+}
+
+const integrationColumns = `
+	id, tenant_id, kind, name, webhook_url, priorities, categories, active, created_at, updated_at`
+
+func (r *IntegrationRepository) Create(ctx context.Context, integration *entities.Integration) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO integrations (tenant_id, kind, name, webhook_url, priorities, categories, active)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, created_at, updated_at`,
+			integration.TenantID, integration.Kind, integration.Name, integration.WebhookURL,
+			priorityStrings(integration.Criteria.Priorities), nonNilStrings(integration.Criteria.Categories),
+			integration.Active,
+		).Scan(&integration.ID, &integration.CreatedAt, &integration.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao criar integração: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *IntegrationRepository) GetByID(ctx context.Context, id string) (*entities.Integration, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var integration *entities.Integration
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		integration, err = scanIntegration(tx.QueryRow(ctx,
+			`SELECT`+integrationColumns+` FROM integrations WHERE id = $1 AND tenant_id = $2`,
+			id, tenantID,
+		))
+		if err != nil {
+			return fmt.Errorf("erro ao buscar integração: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return integration, nil
+}
+
+func (r *IntegrationRepository) ListByTenant(ctx context.Context, tenantID string) ([]*entities.Integration, error) {
+	return r.list(ctx, `
+		SELECT`+integrationColumns+` FROM integrations
+		WHERE tenant_id = $1
+		ORDER BY created_at, id`,
+		tenantID,
+	)
+}
+
+func (r *IntegrationRepository) ListActive(ctx context.Context, tenantID string) ([]*entities.Integration, error) {
+	return r.list(ctx, `
+		SELECT`+integrationColumns+` FROM integrations
+		WHERE tenant_id = $1 AND active
+		ORDER BY created_at, id`,
+		tenantID,
+	)
+}
+
+// list executa a consulta de integrações informada
+func (r *IntegrationRepository) list(ctx context.Context, query string, args ...interface{}) ([]*entities.Integration, error) {
+	var integrations []*entities.Integration
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("erro ao listar integrações: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			integration, err := scanIntegration(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao ler integração: %v", err)
+			}
+			integrations = append(integrations, integration)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return integrations, nil
+}
+
+func (r *IntegrationRepository) Update(ctx context.Context, integration *entities.Integration) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			UPDATE integrations SET
+				name = $1, webhook_url = $2, priorities = $3, categories = $4, active = $5, updated_at = NOW()
+			WHERE id = $6 AND tenant_id = $7
+			RETURNING updated_at`,
+			integration.Name, integration.WebhookURL, priorityStrings(integration.Criteria.Priorities),
+			nonNilStrings(integration.Criteria.Categories), integration.Active, integration.ID, tenantID,
+		).Scan(&integration.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar integração: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *IntegrationRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, "DELETE FROM integrations WHERE id = $1 AND tenant_id = $2", id, tenantID)
+		if err != nil {
+			return fmt.Errorf("erro ao deletar integração: %v", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("integração não encontrada com id: %s", id)
+		}
+		return nil
+	})
+}
+
+func scanIntegration(row pgx.Row) (*entities.Integration, error) {
+	var integration entities.Integration
+	var priorities []string
+	err := row.Scan(
+		&integration.ID, &integration.TenantID, &integration.Kind, &integration.Name,
+		&integration.WebhookURL, &priorities, &integration.Criteria.Categories,
+		&integration.Active, &integration.CreatedAt, &integration.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range priorities {
+		integration.Criteria.Priorities = append(integration.Criteria.Priorities, entities.Priority(p))
+	}
+	return &integration, nil
+}
+
+// nonNilStrings evita gravar NULL em colunas TEXT[] NOT NULL
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
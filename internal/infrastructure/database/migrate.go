@@ -0,0 +1,347 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID chave do advisory lock que serializa execuções concorrentes
+const migrationLockID = 7219360414
+
+var (
+	ErrSchemaOutdated = errors.New("schema do banco desatualizado; execute `migrate up`")
+	ErrSchemaUnknown  = errors.New("schema do banco em versão desconhecida por este binário")
+)
+
+// Migration migração numerada com scripts de aplicação e reversão
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus situação de uma migração no banco
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Migrations retorna as migrações embutidas no binário, em ordem de versão.
+// Os arquivos seguem o padrão NNN_nome.up.sql / NNN_nome.down.sql.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("nome de migração inválido: %s", name)
+		}
+		number, label, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if err != nil {
+			return nil, fmt.Errorf("nome de migração inválido: %s", name)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("versão %d usada por duas migrações: %s e %s", version, m.Name, label)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migração %03d_%s sem script up ou down", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// LatestSchemaVersion versão esperada pelo binário
+func LatestSchemaVersion() (int, error) {
+	migrations, err := Migrations()
+	if err != nil || len(migrations) == 0 {
+		return 0, err
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// MigrateUp aplica as migrações pendentes, cada uma em sua própria transação
+func (db *Database) MigrateUp(ctx context.Context) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	err = db.withMigrationLock(ctx, func(conn *pgx.Conn) error {
+		current, err := schemaVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if current == 0 && len(migrations) > 0 {
+			if current, err = adoptExistingSchema(ctx, conn, migrations[0]); err != nil {
+				return err
+			}
+		}
+
+		for _, m := range migrations {
+			if m.Version <= current {
+				continue
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if err := bypassRowLevelSecurity(ctx, tx); err != nil {
+					return err
+				}
+				if _, err := tx.Exec(ctx, m.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx,
+					"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+					m.Version, m.Name,
+				)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("erro ao aplicar migração %03d_%s: %v", m.Version, m.Name, err)
+			}
+			applied = append(applied, m)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// MigrateDown reverte as últimas steps migrações aplicadas
+func (db *Database) MigrateDown(ctx context.Context, steps int) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	err = db.withMigrationLock(ctx, func(conn *pgx.Conn) error {
+		current, err := schemaVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			m := migrations[i]
+			if m.Version > current {
+				continue
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if err := bypassRowLevelSecurity(ctx, tx); err != nil {
+					return err
+				}
+				if _, err := tx.Exec(ctx, m.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", m.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("erro ao reverter migração %03d_%s: %v", m.Version, m.Name, err)
+			}
+			reverted = append(reverted, m)
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// MigrationStatus lista as migrações conhecidas e quando foram aplicadas
+func (db *Database) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	appliedAt := make(map[int]time.Time)
+	err = db.withMigrationLock(ctx, func(conn *pgx.Conn) error {
+		rows, err := conn.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var version int
+			var at time.Time
+			if err := rows.Scan(&version, &at); err != nil {
+				return err
+			}
+			appliedAt[version] = at
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar migrações aplicadas: %v", err)
+	}
+
+	status := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		s := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := appliedAt[m.Version]; ok {
+			s.AppliedAt = &at
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+// CheckSchemaVersion confirma que o banco está exatamente na versão esperada
+// pelo binário, evitando rodar contra um schema antigo ou mais novo
+func (db *Database) CheckSchemaVersion(ctx context.Context) error {
+	expected, err := LatestSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	var current int
+	err = db.pool.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current)
+	if err != nil {
+		return fmt.Errorf("%w (schema_migrations não encontrada: %v)", ErrSchemaOutdated, err)
+	}
+
+	switch {
+	case current < expected:
+		return fmt.Errorf("%w: versão %d, esperada %d", ErrSchemaOutdated, current, expected)
+	case current > expected:
+		return fmt.Errorf("%w: versão %d, esperada %d", ErrSchemaUnknown, current, expected)
+	}
+	return nil
+}
+
+// withMigrationLock executa fn em uma conexão dedicada, com o advisory lock de
+// migrações e a tabela schema_migrations garantida
+func (db *Database) withMigrationLock(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	conn, err := db.GetConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("erro ao obter lock de migração: %v", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	_, err = conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	if err != nil {
+		return fmt.Errorf("erro ao criar schema_migrations: %v", err)
+	}
+
+	return fn(conn.Conn())
+}
+
+// bypassRowLevelSecurity libera as políticas de RLS na transação da migração:
+// com FORCE ROW LEVEL SECURITY elas valem também para o dono das tabelas, e as
+// migrações de dados precisam enxergar as linhas de todos os tenants
+func bypassRowLevelSecurity(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, "SELECT set_config('app.bypass_rls', 'on', true)")
+	return err
+}
+
+// initialSchemaTables tabelas criadas pela migração 001
+var initialSchemaTables = []string{"tenants", "users", "emails", "email_labels", "tasks"}
+
+// adoptExistingSchema registra a migração 001 em bancos criados antes das
+// migrações versionadas (001_initial_schema.sql aplicado via psql ou
+// docker-entrypoint-initdb.d), que têm as tabelas iniciais mas nenhuma versão
+// em schema_migrations. As migrações 002 a 005 usam IF NOT EXISTS e podem ser
+// reaplicadas sobre o que esses bancos já tenham. Retorna a versão resultante.
+func adoptExistingSchema(ctx context.Context, conn *pgx.Conn, initial Migration) (int, error) {
+	var existing int
+	err := conn.QueryRow(ctx,
+		"SELECT count(*) FROM unnest($1::text[]) AS t(name) WHERE to_regclass(t.name) IS NOT NULL",
+		initialSchemaTables,
+	).Scan(&existing)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao verificar schema existente: %v", err)
+	}
+
+	switch existing {
+	case 0:
+		return 0, nil
+	case len(initialSchemaTables):
+	default:
+		return 0, fmt.Errorf("schema parcial: %d de %d tabelas iniciais (%s) existem sem schema_migrations",
+			existing, len(initialSchemaTables), strings.Join(initialSchemaTables, ", "))
+	}
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		// password_hash era adicionada à parte pelo antigo setup_db.sql
+		_, err := tx.Exec(ctx, `
+			ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash VARCHAR(255) NOT NULL DEFAULT '';
+			ALTER TABLE users ALTER COLUMN password_hash DROP DEFAULT`)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx,
+			"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+			initial.Version, initial.Name,
+		)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao registrar schema existente como %03d_%s: %v", initial.Version, initial.Name, err)
+	}
+	return initial.Version, nil
+}
+
+// schemaVersion retorna a maior versão aplicada (0 para banco vazio)
+func schemaVersion(ctx context.Context, conn *pgx.Conn) (int, error) {
+	var version int
+	err := conn.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao consultar versão do schema: %v", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// execMigrations aplica os scripts up diretamente, como o antigo
+// docker-entrypoint-initdb.d, sem registrar versões
+func execMigrations(t *testing.T, db *Database, migrations []Migration) {
+	t.Helper()
+	for _, m := range migrations {
+		up := m.Up
+		if m.Version == 1 {
+			// Antes das migrações versionadas, password_hash vinha do setup_db.sql
+			up = strings.Replace(up, "password_hash VARCHAR(255) NOT NULL,", "", 1)
+		}
+		if _, err := db.pool.Exec(context.Background(), up); err != nil {
+			t.Fatalf("aplicar %03d_%s: %v", m.Version, m.Name, err)
+		}
+	}
+}
+
+func TestMigrateUpAdoptsExistingSchema(t *testing.T) {
+	db := connectTestDatabase(t, createTestDatabase(t))
+	ctx := context.Background()
+
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	execMigrations(t, db, migrations[:5])
+	if _, err := db.pool.Exec(ctx, "INSERT INTO tenants (name, plan) VALUES ('legado', 'free')"); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := db.MigrateUp(ctx)
+	if err != nil {
+		t.Fatalf("migrar banco existente: %v", err)
+	}
+	if len(applied) != len(migrations)-1 || applied[0].Version != 2 {
+		t.Errorf("aplicadas %v, esperado da 002 à última", applied)
+	}
+	if err := db.CheckSchemaVersion(ctx); err != nil {
+		t.Errorf("CheckSchemaVersion: %v", err)
+	}
+
+	status, err := db.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range status {
+		if s.AppliedAt == nil {
+			t.Errorf("migração %03d_%s pendente", s.Version, s.Name)
+		}
+	}
+
+	var tenants int
+	err = db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		return tx.QueryRow(ctx, "SELECT count(*) FROM tenants").Scan(&tenants)
+	})
+	if err != nil || tenants != 1 {
+		t.Errorf("tenants após a migração = %d, %v; esperado o tenant existente", tenants, err)
+	}
+}
+
+func TestMigrateUpRejectsPartialSchema(t *testing.T) {
+	db := connectTestDatabase(t, createTestDatabase(t))
+	ctx := context.Background()
+
+	if _, err := db.pool.Exec(ctx, "CREATE TABLE tenants (id UUID PRIMARY KEY)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.MigrateUp(ctx); err == nil || !strings.Contains(err.Error(), "schema parcial") {
+		t.Errorf("MigrateUp com schema parcial = %v, esperado erro", err)
+	}
+}
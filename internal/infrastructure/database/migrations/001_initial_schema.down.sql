@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS validate_tenant_plan_trigger ON tenants;
+DROP FUNCTION IF EXISTS validate_tenant_plan();
+
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS email_labels;
+DROP TABLE IF EXISTS emails;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS tenants;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
@@ -0,0 +1 @@
+ALTER TABLE emails DROP COLUMN IF EXISTS folder;
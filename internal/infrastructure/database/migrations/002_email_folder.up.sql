@@ -0,0 +1,2 @@
+-- Pasta de origem dos emails (monitoramento de várias pastas)
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS folder VARCHAR(255) NOT NULL DEFAULT 'INBOX';
@@ -0,0 +1,7 @@
+-- Mensagens já baixadas de fontes sem estado no servidor (UIDL do POP3)
+CREATE TABLE IF NOT EXISTS seen_messages (
+    account VARCHAR(512) NOT NULL,
+    uid VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account, uid)
+);
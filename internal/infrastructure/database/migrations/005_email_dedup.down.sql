@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS emails_tenant_content_hash_key;
+DROP INDEX IF EXISTS emails_tenant_message_id_key;
+
+ALTER TABLE emails DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE emails DROP COLUMN IF EXISTS message_id;
@@ -0,0 +1,12 @@
+-- Armazenamento idempotente: Message-ID e hash do conteúdo por tenant
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS message_id VARCHAR(998);
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS emails_tenant_message_id_key
+    ON emails (tenant_id, message_id)
+    WHERE message_id IS NOT NULL;
+
+-- Mensagens sem Message-ID são identificadas pelo hash do conteúdo
+CREATE UNIQUE INDEX IF NOT EXISTS emails_tenant_content_hash_key
+    ON emails (tenant_id, content_hash)
+    WHERE message_id IS NULL AND content_hash <> '';
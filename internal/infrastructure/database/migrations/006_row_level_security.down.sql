@@ -0,0 +1,14 @@
+DROP POLICY IF EXISTS tenant_isolation ON tasks;
+ALTER TABLE tasks DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON email_labels;
+ALTER TABLE email_labels DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON users;
+ALTER TABLE users DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON emails;
+ALTER TABLE emails DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS app_bypass_rls();
+DROP FUNCTION IF EXISTS app_current_tenant();
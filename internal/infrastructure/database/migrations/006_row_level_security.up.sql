@@ -0,0 +1,37 @@
+-- Isolamento de tenants via row-level security.
+-- O serviço define app.tenant_id (SET LOCAL) em cada transação; sem ele nenhuma
+-- linha é visível. app.bypass_rls é usado apenas por operações internas.
+-- As políticas não se aplicam a superusuários nem ao dono das tabelas: o serviço
+-- deve conectar com um usuário sem esses privilégios.
+
+CREATE OR REPLACE FUNCTION app_current_tenant()
+RETURNS UUID AS $$
+    SELECT NULLIF(current_setting('app.tenant_id', true), '')::UUID;
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION app_bypass_rls()
+RETURNS BOOLEAN AS $$
+    SELECT COALESCE(current_setting('app.bypass_rls', true), '') = 'on';
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE emails ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON emails
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant());
+
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON users
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant());
+
+-- Labels e tarefas herdam o tenant do email (a subconsulta também passa pela
+-- política de emails)
+ALTER TABLE email_labels ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON email_labels
+    USING (EXISTS (SELECT 1 FROM emails e WHERE e.id = email_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM emails e WHERE e.id = email_id));
+
+ALTER TABLE tasks ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON tasks
+    USING (EXISTS (SELECT 1 FROM emails e WHERE e.id = email_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM emails e WHERE e.id = email_id));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_emails_search;
+ALTER TABLE emails DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,11 @@
+-- Busca textual em emails: assunto (peso A), remetente (peso B) e conteúdo,
+-- indexados com stemming em português e inglês
+ALTER TABLE emails ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('portuguese', COALESCE(subject, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(subject, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(from_address, '')), 'B') ||
+    setweight(to_tsvector('portuguese', COALESCE(content, '')), 'C') ||
+    setweight(to_tsvector('english', COALESCE(content, '')), 'C')
+) STORED;
+
+CREATE INDEX idx_emails_search ON emails USING GIN (search_vector);
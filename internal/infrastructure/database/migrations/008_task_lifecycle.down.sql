@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS task_status_history;
+
+DROP INDEX IF EXISTS idx_tasks_assignee_id;
+
+-- Os novos status não existem na versão anterior
+UPDATE tasks SET status = 'pending' WHERE status IN ('in_progress', 'snoozed');
+UPDATE tasks SET status = 'completed' WHERE status = 'cancelled';
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS snoozed_until,
+    DROP COLUMN IF EXISTS assignee_id;
@@ -0,0 +1,26 @@
+-- Ciclo de vida das tarefas: responsável, adiamento e histórico de status.
+-- Status válidos: pending, in_progress, snoozed, completed, cancelled.
+
+ALTER TABLE tasks
+    ADD COLUMN assignee_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN snoozed_until TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_tasks_assignee_id ON tasks(assignee_id);
+
+CREATE TABLE task_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_task_status_history_task_id ON task_status_history(task_id, created_at);
+
+-- O histórico herda o tenant da tarefa (e, por ela, do email)
+ALTER TABLE task_status_history ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON task_status_history
+    USING (EXISTS (SELECT 1 FROM tasks t WHERE t.id = task_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM tasks t WHERE t.id = task_id));
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS task_reminders;
+DROP INDEX IF EXISTS idx_tasks_open_due_date;
+ALTER TABLE tasks DROP COLUMN IF EXISTS overdue_at;
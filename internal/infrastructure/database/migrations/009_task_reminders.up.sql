@@ -0,0 +1,22 @@
+-- Lembretes de prazo das tarefas. task_reminders registra cada envio por
+-- tarefa, tipo, prazo e canal, evitando reenvios após reinícios ou entre
+-- instâncias do serviço.
+
+ALTER TABLE tasks ADD COLUMN overdue_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_tasks_open_due_date ON tasks(due_date)
+    WHERE status IN ('pending', 'in_progress');
+
+CREATE TABLE task_reminders (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    due_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    channel VARCHAR(50) NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, kind, due_date, channel)
+);
+
+ALTER TABLE task_reminders ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON task_reminders
+    USING (EXISTS (SELECT 1 FROM tasks t WHERE t.id = task_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM tasks t WHERE t.id = task_id));
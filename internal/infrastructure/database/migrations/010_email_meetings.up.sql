@@ -0,0 +1,23 @@
+-- Convites de reunião (text/calendar) recebidos por email. Convites e
+-- cancelamentos da mesma reunião compartilham o uid.
+
+CREATE TABLE email_meetings (
+    email_id UUID PRIMARY KEY REFERENCES emails(id) ON DELETE CASCADE,
+    uid VARCHAR(255) NOT NULL,
+    sequence INTEGER NOT NULL DEFAULT 0,
+    method VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    organizer VARCHAR(255) NOT NULL DEFAULT '',
+    location TEXT NOT NULL DEFAULT '',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    attendees TEXT[] NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_email_meetings_uid ON email_meetings(uid);
+
+ALTER TABLE email_meetings ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON email_meetings
+    USING (EXISTS (SELECT 1 FROM emails e WHERE e.id = email_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM emails e WHERE e.id = email_id));
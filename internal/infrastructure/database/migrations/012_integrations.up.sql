@@ -0,0 +1,22 @@
+-- Integrações de notificações com Slack e Microsoft Teams. Listas de
+-- critérios vazias não restringem os emails notificados.
+
+CREATE TABLE integrations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    webhook_url TEXT NOT NULL,
+    priorities TEXT[] NOT NULL DEFAULT '{}',
+    categories TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_integrations_tenant ON integrations(tenant_id);
+
+ALTER TABLE integrations ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON integrations
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant());
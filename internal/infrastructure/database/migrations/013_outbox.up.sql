@@ -0,0 +1,23 @@
+-- Outbox transacional: eventos gravados na mesma transação das alterações de
+-- emails e tarefas, removidos após a publicação em todos os sinks. O id
+-- sequencial define a ordem de publicação dentro de cada tenant.
+
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    event_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    data JSONB NOT NULL,
+    delivered TEXT[] NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_outbox_tenant ON outbox(tenant_id, id);
+
+ALTER TABLE outbox ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON outbox
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant());
@@ -0,0 +1,10 @@
+-- Progresso de sincronização das pastas IMAP (UIDVALIDITY e último UID
+-- processado), para retomar a leitura após reinícios
+CREATE TABLE IF NOT EXISTS folder_states (
+    account VARCHAR(512) NOT NULL,
+    folder VARCHAR(255) NOT NULL,
+    uid_validity BIGINT NOT NULL,
+    last_uid BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account, folder)
+);
@@ -0,0 +1,11 @@
+ALTER TABLE outbox NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE integrations NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhooks NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE email_meetings NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE task_reminders NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE task_status_history NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE tasks NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE email_labels NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE users NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE emails NO FORCE ROW LEVEL SECURITY;
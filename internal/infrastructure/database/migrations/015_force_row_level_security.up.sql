@@ -0,0 +1,14 @@
+-- Sem FORCE, o dono das tabelas ignora as políticas de RLS, e o servidor
+-- normalmente conecta com ele. Com FORCE as políticas valem também para o dono;
+-- apenas superusuários e usuários com BYPASSRLS as ignoram.
+ALTER TABLE emails FORCE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+ALTER TABLE email_labels FORCE ROW LEVEL SECURITY;
+ALTER TABLE tasks FORCE ROW LEVEL SECURITY;
+ALTER TABLE task_status_history FORCE ROW LEVEL SECURITY;
+ALTER TABLE task_reminders FORCE ROW LEVEL SECURITY;
+ALTER TABLE email_meetings FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhooks FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+ALTER TABLE integrations FORCE ROW LEVEL SECURITY;
+ALTER TABLE outbox FORCE ROW LEVEL SECURITY;
@@ -0,0 +1,7 @@
+ALTER TABLE backfill_jobs
+    DROP CONSTRAINT IF EXISTS backfill_jobs_tenant_id_fkey,
+    DROP CONSTRAINT IF EXISTS backfill_jobs_user_id_fkey,
+    ADD CONSTRAINT backfill_jobs_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    ADD CONSTRAINT backfill_jobs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+
+ALTER TABLE users ADD CONSTRAINT users_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES tenants(id);
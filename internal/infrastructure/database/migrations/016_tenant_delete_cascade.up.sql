@@ -0,0 +1,9 @@
+-- A remoção de um tenant remove os seus usuários e jobs de importação. users
+-- tinha, além de fk_tenant (em cascata), a restrição da coluna sem cascata.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_tenant_id_fkey;
+
+ALTER TABLE backfill_jobs
+    DROP CONSTRAINT IF EXISTS backfill_jobs_tenant_id_fkey,
+    DROP CONSTRAINT IF EXISTS backfill_jobs_user_id_fkey,
+    ADD CONSTRAINT backfill_jobs_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    ADD CONSTRAINT backfill_jobs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,6 @@
+-- Entradas do outbox que esgotaram as tentativas ficam estacionadas: saem da
+-- fila do tenant, para que um sink com falha permanente não bloqueie os
+-- eventos seguintes, e permanecem na tabela para inspeção e reenvio.
+ALTER TABLE outbox ADD COLUMN parked_at TIMESTAMPTZ;
+
+CREATE INDEX idx_outbox_parked ON outbox(parked_at) WHERE parked_at IS NOT NULL;
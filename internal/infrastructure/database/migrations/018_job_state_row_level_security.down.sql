@@ -0,0 +1,11 @@
+DROP POLICY IF EXISTS system_only ON folder_states;
+ALTER TABLE folder_states NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE folder_states DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS system_only ON seen_messages;
+ALTER TABLE seen_messages NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE seen_messages DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON backfill_jobs;
+ALTER TABLE backfill_jobs NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE backfill_jobs DISABLE ROW LEVEL SECURITY;
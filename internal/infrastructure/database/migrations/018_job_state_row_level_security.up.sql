@@ -0,0 +1,21 @@
+-- Jobs de backfill pertencem a um tenant e seguem as mesmas políticas de
+-- webhooks, integrações e outbox.
+ALTER TABLE backfill_jobs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE backfill_jobs FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON backfill_jobs
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant());
+
+-- O progresso das caixas POP3 e IMAP é indexado pela conta, sem tenant: só
+-- operações internas (app.bypass_rls) acessam essas tabelas.
+ALTER TABLE seen_messages ENABLE ROW LEVEL SECURITY;
+ALTER TABLE seen_messages FORCE ROW LEVEL SECURITY;
+CREATE POLICY system_only ON seen_messages
+    USING (app_bypass_rls())
+    WITH CHECK (app_bypass_rls());
+
+ALTER TABLE folder_states ENABLE ROW LEVEL SECURITY;
+ALTER TABLE folder_states FORCE ROW LEVEL SECURITY;
+CREATE POLICY system_only ON folder_states
+    USING (app_bypass_rls())
+    WITH CHECK (app_bypass_rls());
@@ -0,0 +1,2 @@
+-- Versão do token do feed de calendário: incrementá-la revoga os links do usuário
+ALTER TABLE users ADD COLUMN feed_token_version INTEGER NOT NULL DEFAULT 0;
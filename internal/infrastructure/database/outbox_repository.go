@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ entities.OutboxRepository = (*OutboxRepository)(nil)
+
+// OutboxRepository publica as entradas do outbox de todos os tenants; as
+// operações rodam com SystemContext
+type OutboxRepository struct {
+	db *Database
+}
+
+func NewOutboxRepository(db *Database) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+const outboxColumns = `
+	id, tenant_id, event_id, event_type, data, delivered, attempts, last_error, next_attempt_at, created_at, parked_at`
+
+// outboxClaimLock chave do advisory lock que serializa as reservas entre instâncias
+const outboxClaimLock = 0x6f7574626f78
+
+// outboxTenantLock espaço dos advisory locks que serializam as gravações de
+// cada tenant no outbox; o segundo componente da chave é hashtext(tenant_id)
+const outboxTenantLock = 0x6f7574
+
+// insertOutbox grava os eventos no outbox, na transação da alteração que os
+// originou; não faz nada com os eventos desativados no contexto
+func insertOutbox(ctx context.Context, tx pgx.Tx, events []*entities.Event) error {
+	if entities.EventsDisabled(ctx) || len(events) == 0 {
+		return nil
+	}
+
+	// O id vem da sequência na inserção, não no commit: sem o lock, uma
+	// transação mais lenta do mesmo tenant confirmaria um id menor depois que o
+	// dispatcher já publicou os seguintes. O lock, mantido até o fim da
+	// transação, faz os ids de cada tenant seguirem a ordem de commit. Os
+	// tenants são bloqueados em ordem para evitar deadlocks entre transações
+	// com eventos de vários tenants.
+	tenants := make([]string, 0, 1)
+	for _, event := range events {
+		if !slices.Contains(tenants, event.TenantID) {
+			tenants = append(tenants, event.TenantID)
+		}
+	}
+	slices.Sort(tenants)
+	for _, tenantID := range tenants {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1, hashtext($2))", int32(outboxTenantLock), tenantID); err != nil {
+			return fmt.Errorf("erro ao bloquear outbox do tenant %s: %v", tenantID, err)
+		}
+	}
+
+	for _, event := range events {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO outbox (tenant_id, event_id, event_type, data, created_at)
+			VALUES ($1, $2, $3, $4, $5)`,
+			event.TenantID, event.ID, event.Type, string(event.Data), event.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao gravar evento %s no outbox: %v", event.Type, err)
+		}
+	}
+	return nil
+}
+
+func (r *OutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.OutboxEntry, error) {
+	// O lock serializa as reservas: uma instância só vê o tenant depois que a
+	// reserva da outra adiou a sua entrada mais antiga
+	query := `
+		WITH heads AS (
+			SELECT DISTINCT ON (tenant_id) tenant_id, next_attempt_at
+			FROM outbox
+			WHERE parked_at IS NULL
+			ORDER BY tenant_id, id
+		)
+		UPDATE outbox SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT o.id FROM outbox o
+			JOIN heads h ON h.tenant_id = o.tenant_id
+			WHERE h.next_attempt_at <= $1 AND o.parked_at IS NULL
+			ORDER BY o.id
+			LIMIT $3
+		)
+		RETURNING` + outboxColumns
+
+	var entries []*entities.OutboxEntry
+	err := r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", int64(outboxClaimLock)); err != nil {
+			return fmt.Errorf("erro ao bloquear outbox: %v", err)
+		}
+
+		rows, err := tx.Query(ctx, query, now, now.Add(lease), limit)
+		if err != nil {
+			return fmt.Errorf("erro ao reservar entradas do outbox: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			entry, err := scanOutboxEntry(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao ler entrada do outbox: %v", err)
+			}
+			entries = append(entries, entry)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortOutbox(entries)
+	return entries, nil
+}
+
+func (r *OutboxRepository) Complete(ctx context.Context, id int64) error {
+	return r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "DELETE FROM outbox WHERE id = $1", id); err != nil {
+			return fmt.Errorf("erro ao remover entrada do outbox: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *OutboxRepository) RecordFailure(ctx context.Context, entry *entities.OutboxEntry) error {
+	return r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			UPDATE outbox SET delivered = $1, attempts = $2, last_error = $3, next_attempt_at = $4, parked_at = $5
+			WHERE id = $6`,
+			nonNilStrings(entry.Delivered), entry.Attempts, entry.LastError, entry.NextAttemptAt, entry.ParkedAt, entry.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar falha do outbox: %v", err)
+		}
+		return nil
+	})
+}
+
+func scanOutboxEntry(row pgx.Row) (*entities.OutboxEntry, error) {
+	entry := entities.OutboxEntry{Event: &entities.Event{}}
+	var data string
+	err := row.Scan(
+		&entry.ID, &entry.Event.TenantID, &entry.Event.ID, &entry.Event.Type, &data,
+		&entry.Delivered, &entry.Attempts, &entry.LastError, &entry.NextAttemptAt, &entry.Event.CreatedAt,
+		&entry.ParkedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	entry.Event.Data = []byte(data)
+	return &entry, nil
+}
+
+// sortOutbox ordena as entradas por ID; RETURNING não garante a ordem
+func sortOutbox(entries []*entities.OutboxEntry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+}
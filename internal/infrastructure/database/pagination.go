@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+// cursorWhere adiciona a condição do cursor keyset (colunas com o prefixo alias)
+func cursorWhere(b *queryBuilder, p *entities.Pager, order entities.SortOrder, alias string) {
+	if !p.Keyset || p.Cursor == nil {
+		return
+	}
+	op := "<"
+	if p.Order(order) == entities.SortAsc {
+		op = ">"
+	}
+	b.where(fmt.Sprintf("(%screated_at, %sid) %s (%%s, %%s)", alias, alias, op), p.Cursor.CreatedAt, p.Cursor.ID)
+}
+
+// pageLimit retorna LIMIT/OFFSET com um item extra para detectar a próxima página
+func pageLimit(b *queryBuilder, p *entities.Pager) string {
+	return fmt.Sprintf(" LIMIT %s OFFSET %s", b.arg(p.Size+1), b.arg(p.Offset))
+}
+
+// countTotal executa a contagem quando a página pede o total
+func countTotal(ctx context.Context, tx pgx.Tx, page entities.Page, query string, args []interface{}) (*int64, error) {
+	if !page.WithTotal {
+		return nil, nil
+	}
+	var total int64
+	if err := tx.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("erro ao contar registros: %v", err)
+	}
+	return &total, nil
+}
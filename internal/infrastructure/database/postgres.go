@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/enzo010/email-filter/internal/domain/entities"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -37,14 +38,8 @@ func NewDatabase(ctx context.Context, cfg *Config) (*Database, error) {
 		}
 	}
 
-	// Construir string de conexão
-	connString := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
-	)
-
 	// Configurar pool de conexões
-	poolConfig, err := pgxpool.ParseConfig(connString)
+	poolConfig, err := pgxpool.ParseConfig(cfg.connString())
 	if err != nil {
 		return nil, fmt.Errorf("erro ao parsear config do banco: %v", err)
 	}
@@ -62,6 +57,14 @@ func NewDatabase(ctx context.Context, cfg *Config) (*Database, error) {
 	return &Database{pool: pool}, nil
 }
 
+// connString monta a string de conexão da configuração
+func (c *Config) connString() string {
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode,
+	)
+}
+
 // GetConnection retorna uma conexão do pool
 func (db *Database) GetConnection(ctx context.Context) (*pgxpool.Conn, error) {
 	conn, err := db.pool.Acquire(ctx)
@@ -78,17 +81,66 @@ func (db *Database) Close() {
 	}
 }
 
-// SetTenantContext adiciona o ID do tenant ao contexto para multitenancy
+// ErrNoTenantContext indica operação com escopo de tenant sem tenant no contexto
+var ErrNoTenantContext = entities.ErrNoTenantContext
+
+type systemScopeKey struct{}
+
+// SetTenantContext adiciona o ID do tenant ao contexto para multitenancy.
+// Cada transação aplica o tenant às políticas de row-level security.
 func SetTenantContext(ctx context.Context, tenantID string) context.Context {
-	return context.WithValue(ctx, "tenant_id", tenantID)
+	return entities.WithTenant(ctx, tenantID)
 }
 
 // GetTenantID recupera o ID do tenant do contexto
 func GetTenantID(ctx context.Context) string {
-	if tenantID, ok := ctx.Value("tenant_id").(string); ok {
-		return tenantID
+	return entities.TenantFromContext(ctx)
+}
+
+// SystemContext marca operações internas que precisam consultar dados de
+// qualquer tenant (ex: login por email). Use apenas fora do fluxo de requisições.
+func SystemContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemScopeKey{}, true)
+}
+
+// requireTenant retorna o tenant do contexto ou ErrNoTenantContext
+func requireTenant(ctx context.Context) (string, error) {
+	tenantID := GetTenantID(ctx)
+	if tenantID == "" {
+		return "", ErrNoTenantContext
 	}
-	return ""
+	return tenantID, nil
+}
+
+// applyTenantScope configura as variáveis usadas pelas políticas de RLS.
+// set_config com is_local = true equivale a SET LOCAL: vale até o fim da transação.
+func applyTenantScope(ctx context.Context, tx pgx.Tx) error {
+	if tenantID := GetTenantID(ctx); tenantID != "" {
+		if _, err := tx.Exec(ctx, "SELECT set_config('app.tenant_id', $1, true)", tenantID); err != nil {
+			return fmt.Errorf("erro ao definir tenant da transação: %v", err)
+		}
+	}
+	if system, _ := ctx.Value(systemScopeKey{}).(bool); system {
+		if _, err := tx.Exec(ctx, "SELECT set_config('app.bypass_rls', 'on', true)"); err != nil {
+			return fmt.Errorf("erro ao definir escopo da transação: %v", err)
+		}
+	}
+	return nil
+}
+
+// BypassesRowLevelSecurity informa se o usuário da conexão ignora as políticas
+// de RLS: superusuário, BYPASSRLS ou dono de tabelas sem FORCE ROW LEVEL SECURITY
+func (db *Database) BypassesRowLevelSecurity(ctx context.Context) (bool, error) {
+	var bypass bool
+	err := db.pool.QueryRow(ctx, `
+		SELECT r.rolsuper OR r.rolbypassrls OR (c.relowner = r.oid AND NOT c.relforcerowsecurity)
+		FROM pg_roles r, pg_class c
+		WHERE r.rolname = current_user AND c.oid = 'emails'::regclass`,
+	).Scan(&bypass)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar privilégios do usuário: %v", err)
+	}
+	return bypass, nil
 }
 
 // ExecuteInTransaction executa uma função dentro de uma transação
@@ -104,6 +156,11 @@ func (db *Database) ExecuteInTransaction(ctx context.Context, fn func(ctx contex
 		return fmt.Errorf("erro ao iniciar transação: %v", err)
 	}
 
+	if err := applyTenantScope(ctx, tx); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
 	if err := fn(ctx, tx); err != nil {
 		if rbErr := tx.Rollback(ctx); rbErr != nil {
 			return fmt.Errorf("erro no rollback após erro: %v (erro original: %v)", rbErr, err)
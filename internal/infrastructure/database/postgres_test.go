@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+// testConfig configuração do PostgreSQL de testes (variáveis TEST_DB_*)
+func testConfig(dbName string) *Config {
+	return &Config{
+		Host:     getEnvOrDefault("TEST_DB_HOST", "localhost"),
+		Port:     getEnvOrDefault("TEST_DB_PORT", "5432"),
+		User:     getEnvOrDefault("TEST_DB_USER", "email_filter"),
+		Password: getEnvOrDefault("TEST_DB_PASSWORD", "email_filter"),
+		DBName:   dbName,
+		SSLMode:  getEnvOrDefault("TEST_DB_SSLMODE", "disable"),
+	}
+}
+
+// createTestDatabase cria um banco vazio, removido ao final do teste. O teste é
+// ignorado, com o motivo, se o servidor não estiver acessível ou o usuário não
+// puder criar bancos (CREATEDB).
+func createTestDatabase(t *testing.T) *Config {
+	t.Helper()
+	cfg := testConfig(getEnvOrDefault("TEST_DB_ADMIN_NAME", "postgres"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	admin, err := pgx.Connect(ctx, cfg.connString())
+	if err != nil {
+		t.Skipf("PostgreSQL de testes indisponível em %s:%s como %s (%v); configure TEST_DB_* para executar estes testes",
+			cfg.Host, cfg.Port, cfg.User, err)
+	}
+	defer admin.Close(context.Background())
+
+	name := fmt.Sprintf("email_filter_test_%d_%d", time.Now().Unix(), rand.Intn(1_000_000))
+	if _, err := admin.Exec(ctx, "CREATE DATABASE "+pgx.Identifier{name}.Sanitize()); err != nil {
+		t.Skipf("usuário %s não pode criar o banco temporário (%v); conceda CREATEDB para executar estes testes", cfg.User, err)
+	}
+
+	// Registrado antes do fechamento do pool do teste, roda depois dele
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		admin, err := pgx.Connect(ctx, cfg.connString())
+		if err != nil {
+			t.Errorf("remover banco %s: %v", name, err)
+			return
+		}
+		defer admin.Close(context.Background())
+		if _, err := admin.Exec(ctx, "DROP DATABASE IF EXISTS "+pgx.Identifier{name}.Sanitize()); err != nil {
+			t.Errorf("remover banco %s: %v", name, err)
+		}
+	})
+
+	dbCfg := *cfg
+	dbCfg.DBName = name
+	return &dbCfg
+}
+
+// connectTestDatabase conecta ao banco temporário, fechando o pool ao final do teste
+func connectTestDatabase(t *testing.T, cfg *Config) *Database {
+	t.Helper()
+	db, err := NewDatabase(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("conectar: %v", err)
+	}
+	t.Cleanup(db.Close)
+	return db
+}
+
+// openTestDatabase cria um banco temporário com as migrações aplicadas. O
+// usuário não deve ser superusuário nem ter BYPASSRLS, para que as políticas
+// de RLS sejam exercitadas.
+func openTestDatabase(t *testing.T) *Database {
+	t.Helper()
+	db := connectTestDatabase(t, createTestDatabase(t))
+
+	ctx := context.Background()
+	if _, err := db.MigrateUp(ctx); err != nil {
+		t.Fatalf("migrar: %v", err)
+	}
+	if bypass, err := db.BypassesRowLevelSecurity(ctx); err != nil {
+		t.Fatal(err)
+	} else if bypass {
+		t.Fatal("TEST_DB_USER ignora row-level security; use um usuário sem superusuário nem BYPASSRLS")
+	}
+	return db
+}
+
+// createTestTenant cria um tenant com um usuário e remove ambos ao final do teste
+func createTestTenant(t *testing.T, db *Database, name string) (*entities.Tenant, *entities.User) {
+	t.Helper()
+	ctx := context.Background()
+
+	tenant := &entities.Tenant{Name: name, Active: true}
+	if err := NewTenantRepository(db).Create(ctx, tenant); err != nil {
+		t.Fatalf("criar tenant: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := NewTenantRepository(db).Delete(ctx, tenant.ID); err != nil {
+			t.Errorf("remover tenant %s: %v", tenant.ID, err)
+		}
+	})
+
+	user := &entities.User{
+		TenantID:     tenant.ID,
+		Email:        name + "-" + tenant.ID[:8] + "@example.com",
+		Name:         name,
+		PasswordHash: "x",
+		Active:       true,
+	}
+	if err := NewUserRepository(db).Create(ctx, user); err != nil {
+		t.Fatalf("criar usuário: %v", err)
+	}
+	return tenant, user
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// queryBuilder monta cláusulas WHERE, ORDER BY e LIMIT com parâmetros
+// posicionais ($1, $2, ...), compartilhado pelos repositórios
+type queryBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg registra um parâmetro e retorna o seu placeholder
+func (b *queryBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// where adiciona uma condição; cada %s em format recebe o placeholder do valor
+// correspondente
+func (b *queryBuilder) where(format string, values ...interface{}) {
+	placeholders := make([]interface{}, len(values))
+	for i, v := range values {
+		placeholders[i] = b.arg(v)
+	}
+	b.conditions = append(b.conditions, fmt.Sprintf(format, placeholders...))
+}
+
+// whereAny filtra column por qualquer um dos valores; lista vazia não filtra
+func (b *queryBuilder) whereAny(column string, values []string) {
+	if len(values) > 0 {
+		b.where(column+" = ANY(%s)", values)
+	}
+}
+
+// whereClause retorna a cláusula WHERE com todas as condições
+func (b *queryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// count retorna a contagem das linhas de from com as condições atuais; deve ser
+// chamado antes de adicionar a condição do cursor
+func (b *queryBuilder) count(from string) (string, []interface{}) {
+	args := make([]interface{}, len(b.args))
+	copy(args, b.args)
+	return "SELECT COUNT(*) FROM " + from + b.whereClause(), args
+}
+
+// orderBy retorna a ordenação pela expressão, com o id como desempate
+func orderBy(expr string, order entities.SortOrder, alias string) string {
+	direction := "DESC"
+	if order == entities.SortAsc {
+		direction = "ASC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, %sid %s", expr, direction, alias, direction)
+}
+
+// priorityRank ordena prioridades por importância em vez da ordem alfabética
+func priorityRank(column string) string {
+	return fmt.Sprintf("CASE %s WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END", column)
+}
+
+// priorityStrings converte prioridades para o parâmetro de ANY()
+func priorityStrings(priorities []entities.Priority) []string {
+	values := make([]string, len(priorities))
+	for i, p := range priorities {
+		values[i] = string(p)
+	}
+	return values
+}
+
+// emailSortExpr expressão SQL do campo de ordenação de emails
+func emailSortExpr(sort, alias string) string {
+	if sort == entities.EmailSortPriority {
+		return priorityRank(alias + "priority")
+	}
+	return alias + sort
+}
+
+// taskSortExpr expressão SQL do campo de ordenação de tarefas
+func taskSortExpr(sort, alias string) string {
+	if sort == entities.TaskSortPriority {
+		return priorityRank(alias + "priority")
+	}
+	return alias + sort
+}
+
+// applyEmailFilter adiciona as condições do filtro de emails (colunas de emails
+// com o prefixo alias, ex: "e.")
+func applyEmailFilter(b *queryBuilder, f *entities.EmailFilter, alias string) {
+	b.whereAny(alias+"id", f.IDs)
+	b.whereAny(alias+"category", f.Categories)
+	b.whereAny(alias+"priority", priorityStrings(f.Priorities))
+	b.whereAny(alias+"folder", f.Folders)
+	if len(f.Labels) > 0 {
+		b.where("EXISTS (SELECT 1 FROM email_labels fl WHERE fl.email_id = "+alias+"id AND fl.label = ANY(%s))", f.Labels)
+	}
+	if f.StartDate != nil {
+		b.where(alias+"created_at >= %s", *f.StartDate)
+	}
+	if f.EndDate != nil {
+		b.where(alias+"created_at <= %s", *f.EndDate)
+	}
+}
+
+// applyTaskFilter adiciona as condições do filtro de tarefas
+func applyTaskFilter(b *queryBuilder, f *entities.TaskFilter, alias string) {
+	b.whereAny(alias+"priority", priorityStrings(f.Priorities))
+	b.whereAny(alias+"status", f.Statuses)
+	if f.AssigneeID != "" {
+		b.where(alias+"assignee_id = %s", f.AssigneeID)
+	}
+	if f.UserID != "" {
+		b.where("COALESCE("+alias+"assignee_id, e.user_id) = %s", f.UserID)
+	}
+	if f.StartDate != nil {
+		b.where(alias+"due_date >= %s", *f.StartDate)
+	}
+	if f.EndDate != nil {
+		b.where(alias+"due_date <= %s", *f.EndDate)
+	}
+}
@@ -0,0 +1,163 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+func TestApplyEmailFilterSQL(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name   string
+		filter entities.EmailFilter
+		where  string
+		args   []interface{}
+	}{
+		{
+			name:  "sem filtros",
+			where: "",
+		},
+		{
+			name: "vários valores por campo",
+			filter: entities.EmailFilter{
+				Categories: []string{"work", "finance"},
+				Priorities: []entities.Priority{entities.PriorityHigh, entities.PriorityMedium},
+				Folders:    []string{"INBOX"},
+			},
+			where: " WHERE e.category = ANY($1) AND e.priority = ANY($2) AND e.folder = ANY($3)",
+			args:  []interface{}{[]string{"work", "finance"}, []string{"high", "medium"}, []string{"INBOX"}},
+		},
+		{
+			name:   "labels e período",
+			filter: entities.EmailFilter{Labels: []string{"a fazer"}, StartDate: &start, EndDate: &end},
+			where: " WHERE EXISTS (SELECT 1 FROM email_labels fl WHERE fl.email_id = e.id AND fl.label = ANY($1))" +
+				" AND e.created_at >= $2 AND e.created_at <= $3",
+			args: []interface{}{[]string{"a fazer"}, start, end},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &queryBuilder{}
+			applyEmailFilter(b, &tt.filter, "e.")
+			if got := b.whereClause(); got != tt.where {
+				t.Errorf("where = %q, esperado %q", got, tt.where)
+			}
+			if !reflect.DeepEqual(b.args, tt.args) {
+				t.Errorf("args = %#v, esperado %#v", b.args, tt.args)
+			}
+		})
+	}
+}
+
+func TestApplyTaskFilterSQL(t *testing.T) {
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	b := &queryBuilder{}
+	b.where("e.tenant_id = %s", "tenant")
+	applyTaskFilter(b, &entities.TaskFilter{
+		Priorities: []entities.Priority{entities.PriorityLow},
+		Statuses:   []string{"pending", "in_progress"},
+		AssigneeID: "user",
+		UserID:     "owner",
+		EndDate:    &due,
+	}, "t.")
+
+	want := " WHERE e.tenant_id = $1 AND t.priority = ANY($2) AND t.status = ANY($3) AND t.assignee_id = $4 AND COALESCE(t.assignee_id, e.user_id) = $5 AND t.due_date <= $6"
+	if got := b.whereClause(); got != want {
+		t.Errorf("where = %q, esperado %q", got, want)
+	}
+	args := []interface{}{"tenant", []string{"low"}, []string{"pending", "in_progress"}, "user", "owner", due}
+	if !reflect.DeepEqual(b.args, args) {
+		t.Errorf("args = %#v, esperado %#v", b.args, args)
+	}
+}
+
+func TestOrderBySQL(t *testing.T) {
+	tests := []struct {
+		expr  string
+		order entities.SortOrder
+		alias string
+		want  string
+	}{
+		{emailSortExpr(entities.EmailSortCreatedAt, "e."), entities.SortDesc, "e.", " ORDER BY e.created_at DESC, e.id DESC"},
+		{emailSortExpr(entities.EmailSortSubject, ""), entities.SortAsc, "", " ORDER BY subject ASC, id ASC"},
+		{
+			taskSortExpr(entities.TaskSortPriority, "t."), entities.SortDesc, "t.",
+			" ORDER BY CASE t.priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END DESC, t.id DESC",
+		},
+	}
+	for _, tt := range tests {
+		if got := orderBy(tt.expr, tt.order, tt.alias); got != tt.want {
+			t.Errorf("orderBy(%q) = %q, esperado %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestPaginationSQL(t *testing.T) {
+	created := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	cursor := &entities.Cursor{CreatedAt: created, ID: "id-1"}
+
+	tests := []struct {
+		name  string
+		pager entities.Pager
+		order entities.SortOrder
+		sql   string
+		args  []interface{}
+	}{
+		{
+			name:  "primeira página",
+			pager: entities.Pager{Size: 20, Keyset: true},
+			order: entities.SortDesc,
+			sql:   " WHERE e.tenant_id = $1 LIMIT $2 OFFSET $3",
+			args:  []interface{}{"tenant", 21, 0},
+		},
+		{
+			name:  "próxima página keyset",
+			pager: entities.Pager{Size: 20, Keyset: true, Cursor: cursor},
+			order: entities.SortDesc,
+			sql:   " WHERE e.tenant_id = $1 AND (e.created_at, e.id) < ($2, $3) LIMIT $4 OFFSET $5",
+			args:  []interface{}{"tenant", created, "id-1", 21, 0},
+		},
+		{
+			name:  "página anterior keyset inverte o sentido",
+			pager: entities.Pager{Size: 20, Keyset: true, Cursor: cursor, Backward: true},
+			order: entities.SortDesc,
+			sql:   " WHERE e.tenant_id = $1 AND (e.created_at, e.id) > ($2, $3) LIMIT $4 OFFSET $5",
+			args:  []interface{}{"tenant", created, "id-1", 21, 0},
+		},
+		{
+			name:  "deslocamento ignora o cursor",
+			pager: entities.Pager{Size: 10, Cursor: cursor, Offset: 30},
+			order: entities.SortAsc,
+			sql:   " WHERE e.tenant_id = $1 LIMIT $2 OFFSET $3",
+			args:  []interface{}{"tenant", 11, 30},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &queryBuilder{}
+			b.where("e.tenant_id = %s", "tenant")
+			countSQL, countArgs := b.count("emails e")
+			cursorWhere(b, &tt.pager, tt.order, "e.")
+			sql := b.whereClause() + pageLimit(b, &tt.pager)
+
+			if sql != tt.sql {
+				t.Errorf("sql = %q, esperado %q", sql, tt.sql)
+			}
+			if !reflect.DeepEqual(b.args, tt.args) {
+				t.Errorf("args = %#v, esperado %#v", b.args, tt.args)
+			}
+			// A contagem não inclui o cursor nem a paginação
+			if want := "SELECT COUNT(*) FROM emails e WHERE e.tenant_id = $1"; countSQL != want {
+				t.Errorf("count = %q, esperado %q", countSQL, want)
+			}
+			if !reflect.DeepEqual(countArgs, []interface{}{"tenant"}) {
+				t.Errorf("count args = %#v", countArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ entities.ReminderRepository = (*ReminderRepository)(nil)
+
+// ReminderRepository consulta tarefas de todos os tenants; as operações rodam
+// com SystemContext
+type ReminderRepository struct {
+	db *Database
+}
+
+func NewReminderRepository(db *Database) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+func (r *ReminderRepository) Due(ctx context.Context, now time.Time, lead time.Duration, channels []string, limit int) ([]*entities.Reminder, error) {
+	// O destinatário é o responsável ou, na falta dele, o dono do email
+	query := `
+		SELECT` + taskColumns + `,
+			   e.tenant_id, u.id, u.email, e.subject
+		FROM tasks t
+		JOIN emails e ON t.email_id = e.id
+		JOIN users u ON u.id = COALESCE(t.assignee_id, e.user_id)
+		WHERE t.status IN ('pending', 'in_progress') AND t.due_date <= $2 AND u.active
+		  AND (
+			SELECT COUNT(*) FROM task_reminders r
+			WHERE r.task_id = t.id AND r.due_date = t.due_date AND r.channel = ANY($3)
+			  AND r.kind = CASE WHEN t.due_date <= $1 THEN 'overdue' ELSE 'due_soon' END
+		  ) < $4
+		ORDER BY t.due_date, t.id
+		LIMIT $5`
+
+	var reminders []*entities.Reminder
+	err := r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, now, now.Add(lead), channels, len(channels), limit)
+		if err != nil {
+			return fmt.Errorf("erro ao listar lembretes: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var rem entities.Reminder
+			t := &rem.Task
+			err := rows.Scan(
+				&t.ID, &t.EmailID, &t.Description, &t.DueDate,
+				&t.Priority, &t.Status, &t.AssigneeID, &t.SnoozedUntil, &t.OverdueAt,
+				&t.CreatedAt, &t.UpdatedAt,
+				&rem.TenantID, &rem.UserID, &rem.Recipient, &rem.EmailSubject,
+			)
+			if err != nil {
+				return fmt.Errorf("erro ao ler lembrete: %v", err)
+			}
+			rem.Kind = entities.ReminderDueSoon
+			if !t.DueDate.After(now) {
+				rem.Kind = entities.ReminderOverdue
+			}
+			reminders = append(reminders, &rem)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+func (r *ReminderRepository) Claim(ctx context.Context, reminder *entities.Reminder, channel string) (bool, error) {
+	var claimed bool
+	err := r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, `
+			INSERT INTO task_reminders (task_id, kind, due_date, channel)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT DO NOTHING`,
+			reminder.Task.ID, reminder.Kind, reminder.Task.DueDate, channel,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar lembrete: %v", err)
+		}
+		claimed = result.RowsAffected() == 1
+		return nil
+	})
+	return claimed, err
+}
+
+func (r *ReminderRepository) Release(ctx context.Context, reminder *entities.Reminder, channel string) error {
+	return r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			DELETE FROM task_reminders
+			WHERE task_id = $1 AND kind = $2 AND due_date = $3 AND channel = $4`,
+			reminder.Task.ID, reminder.Kind, reminder.Task.DueDate, channel,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao remover registro de lembrete: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *ReminderRepository) MarkOverdue(ctx context.Context, now time.Time) (int64, error) {
+	var marked int64
+	err := r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			UPDATE tasks t SET overdue_at = $1
+			FROM emails e
+			WHERE e.id = t.email_id AND t.status IN ('pending', 'in_progress')
+			  AND t.due_date <= $1 AND t.overdue_at IS NULL
+			RETURNING`+taskColumns+`, e.tenant_id, e.subject`,
+			now,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao marcar tarefas atrasadas: %v", err)
+		}
+
+		// task.overdue de cada tarefa marcada, gravado no outbox na mesma transação
+		var events []*entities.Event
+		for rows.Next() {
+			var t entities.Task
+			var tenantID, subject string
+			err := rows.Scan(
+				&t.ID, &t.EmailID, &t.Description, &t.DueDate,
+				&t.Priority, &t.Status, &t.AssigneeID, &t.SnoozedUntil, &t.OverdueAt,
+				&t.CreatedAt, &t.UpdatedAt, &tenantID, &subject,
+			)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("erro ao ler tarefa atrasada: %v", err)
+			}
+			event, err := entities.NewTaskEvent(tenantID, entities.EventTaskOverdue, t, subject)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			events = append(events, event)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("erro ao marcar tarefas atrasadas: %v", err)
+		}
+
+		marked = int64(len(events))
+		return insertOutbox(ctx, tx, events)
+	})
+	return marked, err
+}
+
+func (r *ReminderRepository) WakeSnoozed(ctx context.Context, now time.Time) (int64, error) {
+	var woken int64
+	err := r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, `
+			WITH woken AS (
+				UPDATE tasks SET status = 'pending', snoozed_until = NULL, updated_at = NOW()
+				WHERE status = 'snoozed' AND snoozed_until <= $1
+				RETURNING id
+			)
+			INSERT INTO task_status_history (task_id, from_status, to_status, note)
+			SELECT id, 'snoozed', 'pending', $2 FROM woken`,
+			now, entities.SnoozeExpiredNote,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao reabrir tarefas adiadas: %v", err)
+		}
+		woken = result.RowsAffected()
+		return nil
+	})
+	return woken, err
+}
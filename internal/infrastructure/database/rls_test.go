@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestRowLevelSecurityIsolatesTenants(t *testing.T) {
+	db := openTestDatabase(t)
+	emails := NewEmailRepository(db)
+
+	tenantA, userA := createTestTenant(t, db, "rls-a")
+	tenantB, userB := createTestTenant(t, db, "rls-b")
+	ctxA := SetTenantContext(context.Background(), tenantA.ID)
+	ctxB := SetTenantContext(context.Background(), tenantB.ID)
+
+	newEmail := func(tenantID, userID, subject string) *entities.Email {
+		return &entities.Email{
+			TenantID:    tenantID,
+			UserID:      userID,
+			Subject:     subject,
+			From:        "sender@example.com",
+			To:          "rcpt@example.com",
+			Content:     subject,
+			Priority:    entities.PriorityMedium,
+			Category:    "work",
+			Labels:      []string{"rls"},
+			Tasks:       []entities.Task{{Description: subject, DueDate: time.Now().Add(time.Hour), Priority: entities.PriorityMedium, Status: entities.TaskPending}},
+			ProcessedAt: time.Now(),
+		}
+	}
+	emailA := newEmail(tenantA.ID, userA.ID, "do tenant A")
+	if _, err := emails.Create(ctxA, emailA); err != nil {
+		t.Fatal(err)
+	}
+	emailB := newEmail(tenantB.ID, userB.ID, "do tenant B")
+	if _, err := emails.Create(ctxB, emailB); err != nil {
+		t.Fatal(err)
+	}
+
+	// Consultas sem filtro por tenant: apenas as políticas restringem as linhas
+	count := func(ctx context.Context, query string) int {
+		t.Helper()
+		var n int
+		err := db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			return tx.QueryRow(ctx, query, []string{tenantA.ID, tenantB.ID}).Scan(&n)
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		return n
+	}
+	queries := []string{
+		"SELECT count(*) FROM emails WHERE tenant_id::text = ANY($1)",
+		"SELECT count(*) FROM users WHERE tenant_id::text = ANY($1)",
+		"SELECT count(*) FROM tasks t JOIN emails e ON e.id = t.email_id WHERE e.tenant_id::text = ANY($1)",
+		"SELECT count(*) FROM email_labels l JOIN emails e ON e.id = l.email_id WHERE e.tenant_id::text = ANY($1)",
+		"SELECT count(*) FROM outbox WHERE tenant_id::text = ANY($1)",
+	}
+	for _, query := range queries {
+		if n := count(ctxA, query); n == 0 || n != count(ctxB, query) {
+			t.Errorf("%s: tenant A vê %d linhas, tenant B vê %d", query, n, count(ctxB, query))
+		}
+		if n, all := count(ctxA, query), count(SystemContext(context.Background()), query); all != 2*n {
+			t.Errorf("%s: tenant A vê %d linhas de %d", query, n, all)
+		}
+		if n := count(context.Background(), query); n != 0 {
+			t.Errorf("%s: sem tenant vê %d linhas", query, n)
+		}
+	}
+
+	// Leitura e escrita de linhas de outro tenant
+	if _, err := emails.GetByID(ctxA, emailB.ID); err == nil {
+		t.Error("tenant A leu o email do tenant B")
+	}
+	err := db.ExecuteInTransaction(ctxA, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "UPDATE emails SET subject = 'alterado' WHERE id = $1", emailB.ID)
+		if err == nil && tag.RowsAffected() != 0 {
+			t.Error("tenant A alterou o email do tenant B")
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.ExecuteInTransaction(ctxA, func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO emails (tenant_id, user_id, content_hash, subject, from_address, to_address, content, folder, priority, category, processed_at)
+			VALUES ($1, $2, 'rls', 'intruso', '', '', '', '', 'low', 'work', now())`,
+			tenantB.ID, userB.ID)
+		return err
+	})
+	if err == nil {
+		t.Error("tenant A inseriu um email no tenant B")
+	}
+}
+
+func TestRowLevelSecurityProtectsJobState(t *testing.T) {
+	db := openTestDatabase(t)
+	backfill := NewBackfillRepository(db)
+
+	tenantA, userA := createTestTenant(t, db, "rls-job-a")
+	tenantB, _ := createTestTenant(t, db, "rls-job-b")
+	ctxA := SetTenantContext(context.Background(), tenantA.ID)
+	ctxB := SetTenantContext(context.Background(), tenantB.ID)
+
+	now := time.Now().UTC()
+	job := &entities.BackfillJob{
+		TenantID: tenantA.ID, UserID: userA.ID, Folder: "INBOX",
+		Since: now.AddDate(0, 0, -7), Until: now, Cursor: now, Status: entities.BackfillPending,
+	}
+	if err := backfill.Create(ctxA, job); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := backfill.GetByID(ctxA, job.ID); err != nil {
+		t.Errorf("tenant A não leu o próprio job: %v", err)
+	}
+	if _, err := backfill.GetByID(ctxB, job.ID); err == nil {
+		t.Error("tenant B leu o job do tenant A")
+	}
+	if err := backfill.UpdateStatus(ctxB, job.ID, entities.BackfillPaused); err == nil {
+		t.Error("tenant B pausou o job do tenant A")
+	}
+
+	// Progresso das caixas: acessível pelos repositórios, invisível fora do escopo de sistema
+	account := "imap:" + tenantA.ID + "@mail.example.com"
+	if err := NewSeenMessageRepository(db).MarkSeen(ctxA, account, "uid-1"); err != nil {
+		t.Fatal(err)
+	}
+	states := NewFolderStateRepository(db)
+	if err := states.SaveFolderState(ctxA, &entities.FolderState{Account: account, Folder: "INBOX", UIDValidity: 1, LastUID: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := states.GetFolderState(ctxA, account, "INBOX"); err != nil || state == nil || state.LastUID != 10 {
+		t.Errorf("GetFolderState = %+v, %v", state, err)
+	}
+
+	for _, query := range []string{
+		"SELECT count(*) FROM seen_messages WHERE account = $1",
+		"SELECT count(*) FROM folder_states WHERE account = $1",
+	} {
+		for name, ctx := range map[string]context.Context{"tenant A": ctxA, "sem tenant": context.Background()} {
+			var n int
+			err := db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+				return tx.QueryRow(ctx, query, account).Scan(&n)
+			})
+			if err != nil || n != 0 {
+				t.Errorf("%s (%s): %d linhas, %v", query, name, n, err)
+			}
+		}
+	}
+	err := db.ExecuteInTransaction(ctxA, func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "INSERT INTO seen_messages (account, uid) VALUES ($1, 'intruso')", account)
+		return err
+	})
+	if err == nil {
+		t.Error("tenant inseriu em seen_messages fora do escopo de sistema")
+	}
+}
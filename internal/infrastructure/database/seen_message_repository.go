@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ entities.SeenMessageRepository = (*SeenMessageRepository)(nil)
+
+// SeenMessageRepository mensagens já baixadas por conta, sem tenant: as
+// políticas de RLS só liberam a tabela no escopo de sistema
+type SeenMessageRepository struct {
+	db *Database
+}
+
+func NewSeenMessageRepository(db *Database) *SeenMessageRepository {
+	return &SeenMessageRepository{db: db}
+}
+
+func (r *SeenMessageRepository) IsSeen(ctx context.Context, account, uid string) (bool, error) {
+	var exists bool
+	err := r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		return tx.QueryRow(ctx,
+			"SELECT EXISTS(SELECT 1 FROM seen_messages WHERE account = $1 AND uid = $2)",
+			account, uid,
+		).Scan(&exists)
+	})
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar mensagem processada: %v", err)
+	}
+	return exists, nil
+}
+
+func (r *SeenMessageRepository) MarkSeen(ctx context.Context, account, uid string) error {
+	err := r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			"INSERT INTO seen_messages (account, uid) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			account, uid,
+		)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao registrar mensagem processada: %v", err)
+	}
+	return nil
+}
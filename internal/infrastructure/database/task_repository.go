@@ -2,12 +2,16 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/enzo010/email-filter/internal/domain/entities"
 	"github.com/jackc/pgx/v5"
 )
 
+var _ entities.TaskRepository = (*TaskRepository)(nil)
+
 type TaskRepository struct {
 	db *Database
 }
@@ -16,58 +20,81 @@ func NewTaskRepository(db *Database) *TaskRepository {
 	return &TaskRepository{db: db}
 }
 
-func (r *TaskRepository) Create(ctx context.Context, task *entities.Task, emailID string) error {
+const taskColumns = `
+	t.id, t.email_id, t.description, t.due_date, t.priority, t.status,
+	COALESCE(t.assignee_id::text, ''), t.snoozed_until, t.overdue_at,
+	t.created_at, t.updated_at`
+
+func (r *TaskRepository) Create(ctx context.Context, task *entities.Task) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
 	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		// Verificar se o email existe
-		var exists bool
-		err := tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM emails WHERE id = $1)", emailID).Scan(&exists)
+		// Verificar se o email existe no tenant; o assunto vai no evento
+		var subject string
+		err := tx.QueryRow(ctx,
+			"SELECT subject FROM emails WHERE id = $1 AND tenant_id = $2",
+			task.EmailID, tenantID,
+		).Scan(&subject)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("email não encontrado com id: %s", task.EmailID)
+		}
 		if err != nil {
 			return fmt.Errorf("erro ao verificar email: %v", err)
 		}
-		if !exists {
-			return fmt.Errorf("email não encontrado com id: %s", emailID)
-		}
 
 		// Validar status
-		if task.Status != "pending" && task.Status != "completed" {
+		if !task.Status.Valid() {
 			return fmt.Errorf("status inválido: %s", task.Status)
 		}
+		if err := checkAssignee(ctx, tx, tenantID, task.AssigneeID); err != nil {
+			return err
+		}
 
 		query := `
 			INSERT INTO tasks (
 				email_id, description, due_date,
-				priority, status
-			) VALUES ($1, $2, $3, $4, $5)
+				priority, status, assignee_id, snoozed_until
+			) VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::uuid, $7)
 			RETURNING id, created_at, updated_at`
 
 		err = tx.QueryRow(
 			ctx, query,
-			emailID, task.Description, task.DueDate,
-			task.Priority, task.Status,
+			task.EmailID, task.Description, task.DueDate,
+			task.Priority, task.Status, task.AssigneeID, task.SnoozedUntil,
 		).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt)
 
 		if err != nil {
 			return fmt.Errorf("erro ao criar tarefa: %v", err)
 		}
 
-		return nil
+		event, err := entities.NewTaskEvent(tenantID, entities.EventTaskCreated, *task, subject)
+		if err != nil {
+			return err
+		}
+		return insertOutbox(ctx, tx, []*entities.Event{event})
 	})
 }
 
 func (r *TaskRepository) GetByID(ctx context.Context, id string) (*entities.Task, error) {
-	task := &entities.Task{}
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+	var task *entities.Task
+
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
 		query := `
-			SELECT id, description, due_date,
-				   priority, status, created_at, updated_at
-			FROM tasks WHERE id = $1`
-
-		err := tx.QueryRow(ctx, query, id).Scan(
-			&task.ID, &task.Description, &task.DueDate,
-			&task.Priority, &task.Status,
-			&task.CreatedAt, &task.UpdatedAt,
-		)
+			SELECT` + taskColumns + `
+			FROM tasks t
+			JOIN emails e ON t.email_id = e.id
+			WHERE t.id = $1 AND e.tenant_id = $2`
+
+		var err error
+		task, err = scanTask(tx.QueryRow(ctx, query, id, tenantID))
 		if err != nil {
 			return fmt.Errorf("erro ao buscar tarefa: %v", err)
 		}
@@ -83,46 +110,45 @@ func (r *TaskRepository) GetByID(ctx context.Context, id string) (*entities.Task
 }
 
 func (r *TaskRepository) Update(ctx context.Context, task *entities.Task) error {
-	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		// Verificar se a tarefa existe
-		var exists bool
-		err := tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1)", task.ID).Scan(&exists)
-		if err != nil {
-			return fmt.Errorf("erro ao verificar tarefa: %v", err)
-		}
-		if !exists {
-			return fmt.Errorf("tarefa não encontrada com id: %s", task.ID)
-		}
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
 
-		// Validar status
-		if task.Status != "pending" && task.Status != "completed" {
-			return fmt.Errorf("status inválido: %s", task.Status)
-		}
+	// Validar prioridade
+	if task.Priority != entities.PriorityHigh &&
+		task.Priority != entities.PriorityMedium &&
+		task.Priority != entities.PriorityLow {
+		return fmt.Errorf("prioridade inválida: %s", task.Priority)
+	}
 
-		// Validar prioridade
-		if task.Priority != entities.PriorityHigh &&
-			task.Priority != entities.PriorityMedium &&
-			task.Priority != entities.PriorityLow {
-			return fmt.Errorf("prioridade inválida: %s", task.Priority)
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := checkAssignee(ctx, tx, tenantID, task.AssigneeID); err != nil {
+			return err
 		}
 
 		query := `
-			UPDATE tasks SET
+			UPDATE tasks t SET
 				description = $1,
 				due_date = $2,
 				priority = $3,
-				status = $4,
+				assignee_id = NULLIF($4, '')::uuid,
+				overdue_at = CASE WHEN t.due_date = $2 THEN t.overdue_at END,
 				updated_at = NOW()
-			WHERE id = $5
-			RETURNING updated_at`
+			FROM emails e
+			WHERE t.id = $5 AND t.email_id = e.id AND e.tenant_id = $6
+			RETURNING t.overdue_at, t.updated_at`
 
-		err = tx.QueryRow(
+		err := tx.QueryRow(
 			ctx, query,
 			task.Description, task.DueDate,
-			task.Priority, task.Status,
-			task.ID,
-		).Scan(&task.UpdatedAt)
+			task.Priority, task.AssigneeID,
+			task.ID, tenantID,
+		).Scan(&task.OverdueAt, &task.UpdatedAt)
 
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("tarefa não encontrada com id: %s", task.ID)
+		}
 		if err != nil {
 			return fmt.Errorf("erro ao atualizar tarefa: %v", err)
 		}
@@ -132,156 +158,237 @@ func (r *TaskRepository) Update(ctx context.Context, task *entities.Task) error
 }
 
 func (r *TaskRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
 	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		_, err := tx.Exec(ctx, "DELETE FROM tasks WHERE id = $1", id)
+		result, err := tx.Exec(ctx, `
+			DELETE FROM tasks t USING emails e
+			WHERE t.id = $1 AND t.email_id = e.id AND e.tenant_id = $2`,
+			id, tenantID,
+		)
 		if err != nil {
 			return fmt.Errorf("erro ao deletar tarefa: %v", err)
 		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("tarefa não encontrada com id: %s", id)
+		}
 		return nil
 	})
 }
 
-func (r *TaskRepository) ListByEmail(ctx context.Context, emailID string, filters map[string]interface{}) ([]*entities.Task, error) {
-	var tasks []*entities.Task
-
-	// Extrair parâmetros de paginação
-	page := 1
-	pageSize := 20
-	if p, ok := filters["page"].(int); ok && p > 0 {
-		page = p
+func (r *TaskRepository) ListByTenant(ctx context.Context, tenantID string, filter *entities.TaskFilter) (*entities.PageResult[*entities.Task], error) {
+	if filter == nil {
+		filter = &entities.TaskFilter{}
 	}
-	if ps, ok := filters["page_size"].(int); ok && ps > 0 && ps <= 100 {
-		pageSize = ps
-	}
-	offset := (page - 1) * pageSize
-
-	// Construir query base
-	query := `
-		WITH filtered_tasks AS (
-			SELECT id, description, due_date,
-				   priority, status, created_at, updated_at
-			FROM tasks 
-			WHERE email_id = $1`
-
-	// Adicionar filtros
-	args := []interface{}{emailID}
-	argCount := 2
-
-	if priority, ok := filters["priority"]; ok {
-		query += fmt.Sprintf(" AND priority = $%d", argCount)
-		args = append(args, priority)
-		argCount++
+	if err := filter.Validate(); err != nil {
+		return nil, err
 	}
 
-	if status, ok := filters["status"]; ok {
-		query += fmt.Sprintf(" AND status = $%d", argCount)
-		args = append(args, status)
-		argCount++
-	}
+	b := &queryBuilder{}
+	b.where("e.tenant_id = %s", tenantID)
+	applyTaskFilter(b, filter, "t.")
+	return r.list(ctx, b, filter)
+}
 
-	// Adicionar ordenação e paginação
-	query += fmt.Sprintf(`
-		ORDER BY created_at DESC
-		LIMIT $%d OFFSET $%d
-	)
-	SELECT * FROM filtered_tasks`, argCount, argCount+1)
+func (r *TaskRepository) ListByEmail(ctx context.Context, emailID string, filter *entities.TaskFilter) (*entities.PageResult[*entities.Task], error) {
+	if filter == nil {
+		filter = &entities.TaskFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
 
-	args = append(args, pageSize, offset)
+	b := &queryBuilder{}
+	b.where("t.email_id = %s", emailID)
+	applyTaskFilter(b, filter, "t.")
+	return r.list(ctx, b, filter)
+}
 
-	rows, err := r.db.pool.Query(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao listar tarefas: %v", err)
+func (r *TaskRepository) ListPendingTasks(ctx context.Context, userID string, filter *entities.TaskFilter) (*entities.PageResult[*entities.Task], error) {
+	if filter == nil {
+		filter = &entities.TaskFilter{}
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		task := &entities.Task{}
-		err := rows.Scan(
-			&task.ID, &task.Description, &task.DueDate,
-			&task.Priority, &task.Status,
-			&task.CreatedAt, &task.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler tarefa: %v", err)
+	// Por padrão, as tarefas com prazo mais próximo primeiro
+	if filter.Sort == "" {
+		filter.Sort = entities.TaskSortDueDate
+		if filter.Order == "" {
+			filter.Order = entities.SortAsc
 		}
-		tasks = append(tasks, task)
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
 	}
 
-	return tasks, nil
+	b := &queryBuilder{}
+	b.conditions = append(b.conditions, "t.status IN ('pending', 'in_progress', 'snoozed')")
+	applyTaskFilter(b, &entities.TaskFilter{
+		UserID:     userID,
+		Priorities: filter.Priorities,
+		StartDate:  filter.StartDate,
+		EndDate:    filter.EndDate,
+	}, "t.")
+	return r.list(ctx, b, filter)
 }
 
-func (r *TaskRepository) ListPendingTasks(ctx context.Context, userID string, filters map[string]interface{}) ([]*entities.Task, error) {
-	var tasks []*entities.Task
-
-	// Extrair parâmetros de paginação
-	page := 1
-	pageSize := 20
-	if p, ok := filters["page"].(int); ok && p > 0 {
-		page = p
-	}
-	if ps, ok := filters["page_size"].(int); ok && ps > 0 && ps <= 100 {
-		pageSize = ps
+// list ordena e pagina as tarefas que atendem às condições de b
+func (r *TaskRepository) list(ctx context.Context, b *queryBuilder, filter *entities.TaskFilter) (*entities.PageResult[*entities.Task], error) {
+	p, err := entities.NewPager(filter.Page, filter.Sort == entities.TaskSortCreatedAt)
+	if err != nil {
+		return nil, err
 	}
-	offset := (page - 1) * pageSize
 
-	// Construir query base
-	query := `
-		WITH filtered_tasks AS (
-			SELECT t.id, t.description, t.due_date,
-				   t.priority, t.status, t.created_at, t.updated_at
-			FROM tasks t
-			JOIN emails e ON t.email_id = e.id
-			WHERE e.user_id = $1 AND t.status = 'pending'`
+	const from = "tasks t JOIN emails e ON t.email_id = e.id"
+	countQuery, countArgs := b.count(from)
+	cursorWhere(b, p, filter.Order, "t.")
 
-	// Adicionar filtros
-	args := []interface{}{userID}
-	argCount := 2
+	query := "SELECT" + taskColumns + " FROM " + from + b.whereClause() +
+		orderBy(taskSortExpr(filter.Sort, "t."), p.Order(filter.Order), "t.") +
+		pageLimit(b, p)
 
-	if priority, ok := filters["priority"]; ok {
-		query += fmt.Sprintf(" AND t.priority = $%d", argCount)
-		args = append(args, priority)
-		argCount++
-	}
+	var tasks []*entities.Task
+	var total *int64
 
-	if startDate, ok := filters["start_date"]; ok {
-		query += fmt.Sprintf(" AND t.due_date >= $%d", argCount)
-		args = append(args, startDate)
-		argCount++
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		if total, err = countTotal(ctx, tx, filter.Page, countQuery, countArgs); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, query, b.args...)
+		if err != nil {
+			return fmt.Errorf("erro ao listar tarefas: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			task, err := scanTask(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao ler tarefa: %v", err)
+			}
+			tasks = append(tasks, task)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if endDate, ok := filters["end_date"]; ok {
-		query += fmt.Sprintf(" AND t.due_date <= $%d", argCount)
-		args = append(args, endDate)
-		argCount++
+	result := entities.Paginate(p, tasks, taskKey)
+	result.Total = total
+	return result, nil
+}
+
+func (r *TaskRepository) Transition(ctx context.Context, task *entities.Task, change *entities.TaskStatusChange) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
 	}
 
-	// Adicionar ordenação e paginação
-	query += fmt.Sprintf(`
-		ORDER BY t.due_date ASC
-		LIMIT $%d OFFSET $%d
-	)
-	SELECT * FROM filtered_tasks`, argCount, argCount+1)
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// A condição sobre o status atual descarta mudanças concorrentes
+		result, err := tx.Exec(ctx, `
+			UPDATE tasks t SET status = $1, snoozed_until = $2, updated_at = NOW()
+			FROM emails e
+			WHERE t.id = $3 AND t.status = $4 AND t.email_id = e.id AND e.tenant_id = $5`,
+			change.ToStatus, task.SnoozedUntil, task.ID, change.FromStatus, tenantID,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar status da tarefa: %v", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("%w: tarefa %s não está mais em %s", entities.ErrInvalidTransition, task.ID, change.FromStatus)
+		}
 
-	args = append(args, pageSize, offset)
+		err = tx.QueryRow(ctx, `
+			INSERT INTO task_status_history (task_id, from_status, to_status, actor_id, note)
+			VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5)
+			RETURNING id, created_at`,
+			task.ID, change.FromStatus, change.ToStatus, change.ActorID, change.Note,
+		).Scan(&change.ID, &change.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar histórico da tarefa: %v", err)
+		}
+		change.TaskID = task.ID
+		task.UpdatedAt = change.CreatedAt
+		return nil
+	})
+}
 
-	rows, err := r.db.pool.Query(ctx, query, args...)
+func (r *TaskRepository) History(ctx context.Context, taskID string) ([]*entities.TaskStatusChange, error) {
+	tenantID, err := requireTenant(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao listar tarefas pendentes: %v", err)
+		return nil, err
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		task := &entities.Task{}
-		err := rows.Scan(
-			&task.ID, &task.Description, &task.DueDate,
-			&task.Priority, &task.Status,
-			&task.CreatedAt, &task.UpdatedAt,
+
+	var history []*entities.TaskStatusChange
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT h.id, h.task_id, h.from_status, h.to_status,
+				   COALESCE(h.actor_id::text, ''), h.note, h.created_at
+			FROM task_status_history h
+			JOIN tasks t ON h.task_id = t.id
+			JOIN emails e ON t.email_id = e.id
+			WHERE h.task_id = $1 AND e.tenant_id = $2
+			ORDER BY h.created_at, h.id`,
+			taskID, tenantID,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao ler tarefa: %v", err)
+			return fmt.Errorf("erro ao listar histórico da tarefa: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var c entities.TaskStatusChange
+			err := rows.Scan(&c.ID, &c.TaskID, &c.FromStatus, &c.ToStatus, &c.ActorID, &c.Note, &c.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("erro ao ler histórico da tarefa: %v", err)
+			}
+			history = append(history, &c)
 		}
-		tasks = append(tasks, task)
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// checkAssignee confirma que o responsável, se informado, pertence ao tenant
+func checkAssignee(ctx context.Context, tx pgx.Tx, tenantID, assigneeID string) error {
+	if assigneeID == "" {
+		return nil
 	}
+	var exists bool
+	err := tx.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM users WHERE id::text = $1 AND tenant_id = $2)",
+		assigneeID, tenantID,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar responsável: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("usuário não encontrado com id: %s", assigneeID)
+	}
+	return nil
+}
+
+func scanTask(row pgx.Row) (*entities.Task, error) {
+	var t entities.Task
+	err := row.Scan(
+		&t.ID, &t.EmailID, &t.Description, &t.DueDate,
+		&t.Priority, &t.Status, &t.AssigneeID, &t.SnoozedUntil, &t.OverdueAt,
+		&t.CreatedAt, &t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
 
-	return tasks, nil
+// taskKey chave de paginação de uma tarefa
+func taskKey(t *entities.Task) (time.Time, string) {
+	return t.CreatedAt, t.ID
 }
@@ -8,6 +8,10 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+var _ entities.TenantRepository = (*TenantRepository)(nil)
+
+// TenantRepository acessa a tabela de tenants, que não está sujeita às
+// políticas de RLS
 type TenantRepository struct {
 	db *Database
 }
@@ -18,17 +22,22 @@ func NewTenantRepository(db *Database) *TenantRepository {
 
 func (r *TenantRepository) Create(ctx context.Context, tenant *entities.Tenant) error {
 	query := `
-		INSERT INTO tenants (id, name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO tenants (id, name, plan, active)
+		VALUES (COALESCE(NULLIF($1, '')::uuid, uuid_generate_v4()), $2, $3, $4)
+		RETURNING id, created_at, updated_at
 	`
 
+	if tenant.Plan == "" {
+		tenant.Plan = "free"
+	}
+
 	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		_, err := tx.Exec(ctx, query,
+		err := tx.QueryRow(ctx, query,
 			tenant.ID,
 			tenant.Name,
-			tenant.CreatedAt,
-			tenant.UpdatedAt,
-		)
+			tenant.Plan,
+			tenant.Active,
+		).Scan(&tenant.ID, &tenant.CreatedAt, &tenant.UpdatedAt)
 		if err != nil {
 			return fmt.Errorf("error creating tenant: %w", err)
 		}
@@ -38,7 +47,7 @@ func (r *TenantRepository) Create(ctx context.Context, tenant *entities.Tenant)
 
 func (r *TenantRepository) GetByID(ctx context.Context, id string) (*entities.Tenant, error) {
 	query := `
-		SELECT id, name, plan, created_at, updated_at
+		SELECT id, name, plan, active, created_at, updated_at
 		FROM tenants
 		WHERE id = $1
 	`
@@ -47,12 +56,77 @@ func (r *TenantRepository) GetByID(ctx context.Context, id string) (*entities.Te
 	return scanTenant(row)
 }
 
+func (r *TenantRepository) Update(ctx context.Context, tenant *entities.Tenant) error {
+	query := `
+		UPDATE tenants SET
+			name = $1,
+			plan = $2,
+			active = $3,
+			updated_at = NOW()
+		WHERE id = $4
+		RETURNING updated_at
+	`
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query,
+			tenant.Name,
+			tenant.Plan,
+			tenant.Active,
+			tenant.ID,
+		).Scan(&tenant.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error updating tenant: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete remove o tenant; usuários, emails e tarefas são removidos em cascata
+func (r *TenantRepository) Delete(ctx context.Context, id string) error {
+	// A cascata precisa alcançar as linhas de todas as tabelas com RLS
+	return r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, "DELETE FROM tenants WHERE id = $1", id)
+		if err != nil {
+			return fmt.Errorf("error deleting tenant: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("tenant not found: %s", id)
+		}
+		return nil
+	})
+}
+
+func (r *TenantRepository) List(ctx context.Context) ([]*entities.Tenant, error) {
+	query := `
+		SELECT id, name, plan, active, created_at, updated_at
+		FROM tenants
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenants []*entities.Tenant
+	for rows.Next() {
+		tenant, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
+
 func scanTenant(row pgx.Row) (*entities.Tenant, error) {
 	var t entities.Tenant
 	err := row.Scan(
 		&t.ID,
 		&t.Name,
 		&t.Plan,
+		&t.Active,
 		&t.CreatedAt,
 		&t.UpdatedAt,
 	)
@@ -8,6 +8,8 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+var _ entities.UserRepository = (*UserRepository)(nil)
+
 type UserRepository struct {
 	db *Database
 }
@@ -18,20 +20,29 @@ func NewUserRepository(db *Database) *UserRepository {
 
 func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
 	query := `
-		INSERT INTO users (id, tenant_id, name, email, password_hash, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (id, tenant_id, name, email, password_hash, role, active)
+		VALUES (COALESCE(NULLIF($1, '')::uuid, uuid_generate_v4()), $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
 	`
 
+	if user.Role == "" {
+		user.Role = "user"
+	}
+	// Fora de uma requisição (ex: cadastro), o escopo é o tenant do próprio usuário
+	if GetTenantID(ctx) == "" {
+		ctx = SetTenantContext(ctx, user.TenantID)
+	}
+
 	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		_, err := tx.Exec(ctx, query,
+		err := tx.QueryRow(ctx, query,
 			user.ID,
 			user.TenantID,
 			user.Name,
 			user.Email,
 			user.PasswordHash,
-			user.CreatedAt,
-			user.UpdatedAt,
-		)
+			user.Role,
+			user.Active,
+		).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
 			return fmt.Errorf("error creating user: %w", err)
 		}
@@ -41,24 +52,139 @@ func (r *UserRepository) Create(ctx context.Context, user *entities.User) error
 
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*entities.User, error) {
 	query := `
-		SELECT id, tenant_id, name, email, password_hash, created_at, updated_at
+		SELECT id, tenant_id, name, email, password_hash, role, active, feed_token_version, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
 
-	row := r.db.pool.QueryRow(ctx, query, id)
-	return scanUser(row)
+	var user *entities.User
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		user, err = scanUser(tx.QueryRow(ctx, query, id))
+		return err
+	})
+	return user, err
 }
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
 	query := `
-		SELECT id, tenant_id, name, email, password_hash, created_at, updated_at
+		SELECT id, tenant_id, name, email, password_hash, role, active, feed_token_version, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
 
-	row := r.db.pool.QueryRow(ctx, query, email)
-	return scanUser(row)
+	// Usado no login, quando o tenant ainda não é conhecido
+	var user *entities.User
+	err := r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		user, err = scanUser(tx.QueryRow(ctx, query, email))
+		return err
+	})
+	return user, err
+}
+
+func (r *UserRepository) ListByTenant(ctx context.Context, tenantID string) ([]*entities.User, error) {
+	query := `
+		SELECT id, tenant_id, name, email, password_hash, role, active, feed_token_version, created_at, updated_at
+		FROM users
+		WHERE tenant_id = $1
+		ORDER BY created_at ASC
+	`
+
+	var users []*entities.User
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, tenantID)
+		if err != nil {
+			return fmt.Errorf("error listing users: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			user, err := scanUser(rows)
+			if err != nil {
+				return err
+			}
+			users = append(users, user)
+		}
+		return rows.Err()
+	})
+	return users, err
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	query := `
+		UPDATE users SET
+			name = $1,
+			email = $2,
+			password_hash = $3,
+			role = $4,
+			active = $5,
+			updated_at = NOW()
+		WHERE id = $6 AND tenant_id = $7
+		RETURNING updated_at
+	`
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query,
+			user.Name,
+			user.Email,
+			user.PasswordHash,
+			user.Role,
+			user.Active,
+			user.ID,
+			user.TenantID,
+		).Scan(&user.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error updating user: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *UserRepository) RotateFeedToken(ctx context.Context, id string) (int, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			UPDATE users SET feed_token_version = feed_token_version + 1, updated_at = NOW()
+			WHERE id = $1 AND tenant_id = $2
+			RETURNING feed_token_version`,
+			id, tenantID,
+		).Scan(&version)
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("user not found: %s", id)
+		}
+		if err != nil {
+			return fmt.Errorf("error rotating feed token: %w", err)
+		}
+		return nil
+	})
+	return version, err
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		result, err := tx.Exec(ctx,
+			"DELETE FROM users WHERE id = $1 AND tenant_id = $2",
+			id, tenantID,
+		)
+		if err != nil {
+			return fmt.Errorf("error deleting user: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("user not found: %s", id)
+		}
+		return nil
+	})
 }
 
 func scanUser(row pgx.Row) (*entities.User, error) {
@@ -69,6 +195,9 @@ func scanUser(row pgx.Row) (*entities.User, error) {
 		&u.Name,
 		&u.Email,
 		&u.PasswordHash,
+		&u.Role,
+		&u.Active,
+		&u.FeedTokenVersion,
 		&u.CreatedAt,
 		&u.UpdatedAt,
 	)
@@ -0,0 +1,346 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ entities.WebhookRepository = (*WebhookRepository)(nil)
+
+// WebhookRepository acessa webhooks e entregas. O cadastro é escopado pelo
+// tenant do contexto; ClaimDue e RecordAttempt rodam com SystemContext.
+type WebhookRepository struct {
+	db *Database
+}
+
+func NewWebhookRepository(db *Database) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const webhookColumns = `
+	id, tenant_id, url, secret, events, active, created_at, updated_at`
+
+const deliveryColumns = `
+	id, webhook_id, tenant_id, event_id, event, payload, status, attempts,
+	next_attempt_at, last_attempt_at, response_status, last_error, created_at, updated_at`
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *entities.Webhook) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO webhooks (tenant_id, url, secret, events, active)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at, updated_at`,
+			webhook.TenantID, webhook.URL, webhook.Secret, eventStrings(webhook.Events), webhook.Active,
+		).Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao criar webhook: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*entities.Webhook, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhook *entities.Webhook
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		webhook, err = scanWebhook(tx.QueryRow(ctx,
+			`SELECT`+webhookColumns+` FROM webhooks WHERE id = $1 AND tenant_id = $2`,
+			id, tenantID,
+		))
+		if err != nil {
+			return fmt.Errorf("erro ao buscar webhook: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (r *WebhookRepository) ListByTenant(ctx context.Context, tenantID string) ([]*entities.Webhook, error) {
+	return r.list(ctx, `
+		SELECT`+webhookColumns+` FROM webhooks
+		WHERE tenant_id = $1
+		ORDER BY created_at, id`,
+		tenantID,
+	)
+}
+
+func (r *WebhookRepository) Subscribers(ctx context.Context, tenantID string, event entities.EventType) ([]*entities.Webhook, error) {
+	return r.list(ctx, `
+		SELECT`+webhookColumns+` FROM webhooks
+		WHERE tenant_id = $1 AND active AND $2 = ANY(events)
+		ORDER BY created_at, id`,
+		tenantID, string(event),
+	)
+}
+
+// list executa a consulta de webhooks informada
+func (r *WebhookRepository) list(ctx context.Context, query string, args ...interface{}) ([]*entities.Webhook, error) {
+	var webhooks []*entities.Webhook
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("erro ao listar webhooks: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			webhook, err := scanWebhook(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao ler webhook: %v", err)
+			}
+			webhooks = append(webhooks, webhook)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, webhook *entities.Webhook) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			UPDATE webhooks SET url = $1, events = $2, active = $3, updated_at = NOW()
+			WHERE id = $4 AND tenant_id = $5
+			RETURNING updated_at`,
+			webhook.URL, eventStrings(webhook.Events), webhook.Active, webhook.ID, tenantID,
+		).Scan(&webhook.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar webhook: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, "DELETE FROM webhooks WHERE id = $1 AND tenant_id = $2", id, tenantID)
+		if err != nil {
+			return fmt.Errorf("erro ao deletar webhook: %v", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("webhook não encontrado com id: %s", id)
+		}
+		return nil
+	})
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO webhook_deliveries (
+				webhook_id, tenant_id, event_id, event, payload, status, next_attempt_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, created_at, updated_at`,
+			delivery.WebhookID, delivery.TenantID, delivery.EventID, delivery.Event,
+			string(delivery.Payload), delivery.Status, delivery.NextAttemptAt,
+		).Scan(&delivery.ID, &delivery.CreatedAt, &delivery.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar entrega: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id string) (*entities.WebhookDelivery, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var delivery *entities.WebhookDelivery
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		delivery, err = scanDelivery(tx.QueryRow(ctx,
+			`SELECT`+deliveryColumns+` FROM webhook_deliveries WHERE id = $1 AND tenant_id = $2`,
+			id, tenantID,
+		))
+		if err != nil {
+			return fmt.Errorf("erro ao buscar entrega: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID string, page entities.Page) (*entities.PageResult[*entities.WebhookDelivery], error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := page.NormalizeKeyset(); err != nil {
+		return nil, err
+	}
+	p, err := entities.NewPager(page, true)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &queryBuilder{}
+	b.where("webhook_id = %s", webhookID)
+	b.where("tenant_id = %s", tenantID)
+	countQuery, countArgs := b.count("webhook_deliveries")
+	cursorWhere(b, p, entities.SortDesc, "")
+	query := `SELECT` + deliveryColumns + ` FROM webhook_deliveries` + b.whereClause() +
+		orderBy("created_at", p.Order(entities.SortDesc), "") + pageLimit(b, p)
+
+	var deliveries []*entities.WebhookDelivery
+	var total *int64
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		if total, err = countTotal(ctx, tx, page, countQuery, countArgs); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, query, b.args...)
+		if err != nil {
+			return fmt.Errorf("erro ao listar entregas: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			delivery, err := scanDelivery(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao ler entrega: %v", err)
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := entities.Paginate(p, deliveries, deliveryKey)
+	result.Total = total
+	return result, nil
+}
+
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	// SKIP LOCKED evita que instâncias concorrentes reservem as mesmas entregas
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = $2, updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING` + deliveryColumns
+
+	var deliveries []*entities.WebhookDelivery
+	err := r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, now, now.Add(lease), limit)
+		if err != nil {
+			return fmt.Errorf("erro ao reservar entregas: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			delivery, err := scanDelivery(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao ler entrega: %v", err)
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	return r.db.ExecuteInTransaction(SystemContext(ctx), func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			UPDATE webhook_deliveries SET
+				status = $1,
+				attempts = $2,
+				next_attempt_at = $3,
+				last_attempt_at = $4,
+				response_status = $5,
+				last_error = $6,
+				updated_at = NOW()
+			WHERE id = $7
+			RETURNING updated_at`,
+			delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastAttemptAt,
+			delivery.ResponseStatus, delivery.LastError, delivery.ID,
+		).Scan(&delivery.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar tentativa de entrega: %v", err)
+		}
+		return nil
+	})
+}
+
+func scanWebhook(row pgx.Row) (*entities.Webhook, error) {
+	var webhook entities.Webhook
+	var events []string
+	err := row.Scan(
+		&webhook.ID, &webhook.TenantID, &webhook.URL, &webhook.Secret,
+		&events, &webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		webhook.Events = append(webhook.Events, entities.EventType(e))
+	}
+	return &webhook, nil
+}
+
+func scanDelivery(row pgx.Row) (*entities.WebhookDelivery, error) {
+	var d entities.WebhookDelivery
+	var payload string
+	err := row.Scan(
+		&d.ID, &d.WebhookID, &d.TenantID, &d.EventID, &d.Event, &payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastAttemptAt, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = []byte(payload)
+	return &d, nil
+}
+
+// deliveryKey chave de paginação de uma entrega
+func deliveryKey(d *entities.WebhookDelivery) (time.Time, string) {
+	return d.CreatedAt, d.ID
+}
+
+// eventStrings converte os tipos de evento para gravação em TEXT[]
+func eventStrings(events []entities.EventType) []string {
+	values := make([]string, len(events))
+	for i, e := range events {
+		values[i] = string(e)
+	}
+	return values
+}
@@ -0,0 +1,84 @@
+package inbound
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// Mailgun webhook da ação forward() das Routes do Mailgun
+type Mailgun struct {
+	signingKey []byte
+	now        func() time.Time
+}
+
+// NewMailgun cria o provedor a partir da HTTP webhook signing key da conta
+func NewMailgun(signingKey string) *Mailgun {
+	return &Mailgun{signingKey: []byte(signingKey), now: time.Now}
+}
+
+func (p *Mailgun) Name() string {
+	return "mailgun"
+}
+
+// Verify confere o HMAC-SHA256 de timestamp + token enviado no próprio formulário
+func (p *Mailgun) Verify(header http.Header, body []byte) error {
+	form, err := parseForm(header, body)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	timestamp, token := form.Get("timestamp"), form.Get("token")
+	signature, err := hex.DecodeString(form.Get("signature"))
+	if err != nil || timestamp == "" || token == "" {
+		return ErrInvalidSignature
+	}
+
+	if !freshTimestamp(timestamp, p.now()) {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, p.signingKey)
+	mac.Write([]byte(timestamp + token))
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (p *Mailgun) Parse(header http.Header, body []byte) (*Message, error) {
+	form, err := parseForm(header, body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	// recipient contém os destinatários do envelope separados por vírgula
+	var recipients []string
+	for _, r := range strings.Split(form.Get("recipient"), ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			recipients = append(recipients, r)
+		}
+	}
+
+	email := &entities.Email{
+		MessageID: trimMessageID(form.Get("Message-Id")),
+		Subject:   form.Get("subject"),
+		From:      firstAddress(form.Get("from")),
+		To:        firstAddress(form.Get("To")),
+		Content:   form.Get("body-plain"),
+	}
+	if email.From == "" {
+		email.From = form.Get("sender")
+	}
+	if email.Content == "" {
+		email.Content = form.Get("body-html")
+	}
+
+	return &Message{Email: email, Recipients: recipients}, nil
+}
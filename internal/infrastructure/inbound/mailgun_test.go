@@ -0,0 +1,75 @@
+package inbound
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	mailgunContentType = "application/x-www-form-urlencoded"
+	// mailgunFixtureKey chave usada para assinar testdata/mailgun.form
+	mailgunFixtureKey = "key-3ax6xnjp29jd6fds4gc373sgvjxteol0"
+)
+
+// mailgunFixtureTime timestamp assinado em testdata/mailgun.form
+var mailgunFixtureTime = time.Unix(1760866800, 0)
+
+func newTestMailgun(now time.Time) *Mailgun {
+	p := NewMailgun(mailgunFixtureKey)
+	p.now = fixedClock(now)
+	return p
+}
+
+func TestMailgunVerify(t *testing.T) {
+	body := readFixture(t, "mailgun.form")
+	header := headerWith(mailgunContentType)
+
+	if err := newTestMailgun(mailgunFixtureTime.Add(time.Minute)).Verify(header, body); err != nil {
+		t.Fatalf("assinatura válida recusada: %v", err)
+	}
+
+	if err := NewMailgun("outra-chave").Verify(header, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("assinatura com outra chave aceita: %v", err)
+	}
+
+	stale := newTestMailgun(mailgunFixtureTime.Add(maxTimestampSkew + time.Second))
+	if err := stale.Verify(header, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("timestamp antigo aceito: %v", err)
+	}
+
+	// Token trocado invalida o HMAC
+	form, _ := url.ParseQuery(string(body))
+	form.Set("token", strings.Repeat("0", 50))
+	if err := newTestMailgun(mailgunFixtureTime).Verify(header, []byte(form.Encode())); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("token alterado aceito: %v", err)
+	}
+}
+
+func TestMailgunParse(t *testing.T) {
+	msg, err := newTestMailgun(mailgunFixtureTime).Parse(headerWith(mailgunContentType), readFixture(t, "mailgun.form"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		fixtureTenant + "+b1e0c9d2-55aa-4f0e-8c3b-6a7d2e1f9c40@in.example.com",
+		fixtureTenant + "@in.example.com",
+	}
+	if strings.Join(msg.Recipients, ",") != strings.Join(want, ",") {
+		t.Errorf("destinatários = %v, esperava %v", msg.Recipients, want)
+	}
+
+	e := msg.Email
+	if e.MessageID != "planejamento-42@mail.example.com" || e.Subject != "Reunião de planejamento" {
+		t.Errorf("Message-ID/assunto = %q/%q", e.MessageID, e.Subject)
+	}
+	if e.From != "carlos@example.com" || e.To != fixtureTenant+"@in.example.com" {
+		t.Errorf("remetente/destinatário = %q/%q", e.From, e.To)
+	}
+	if !strings.HasPrefix(e.Content, "Vamos revisar o planejamento") {
+		t.Errorf("conteúdo = %q", e.Content)
+	}
+}
@@ -0,0 +1,108 @@
+package inbound
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// Postmark webhook de inbound do Postmark (JSON). O Postmark não assina as
+// requisições; a autenticação é feita por credenciais Basic Auth na URL do webhook.
+type Postmark struct {
+	username string
+	password string
+}
+
+// NewPostmark cria o provedor com as credenciais configuradas na URL do webhook
+func NewPostmark(username, password string) *Postmark {
+	return &Postmark{username: username, password: password}
+}
+
+func (p *Postmark) Name() string {
+	return "postmark"
+}
+
+func (p *Postmark) Verify(header http.Header, body []byte) error {
+	req := http.Request{Header: header}
+	username, password, ok := req.BasicAuth()
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	userOK := subtle.ConstantTimeCompare([]byte(username), []byte(p.username)) == 1
+	passOK := subtle.ConstantTimeCompare([]byte(password), []byte(p.password)) == 1
+	if !userOK || !passOK {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// postmarkAddress endereço no formato FromFull/ToFull
+type postmarkAddress struct {
+	Email string `json:"Email"`
+	Name  string `json:"Name"`
+}
+
+// postmarkPayload campos utilizados do webhook de inbound
+type postmarkPayload struct {
+	FromFull          postmarkAddress   `json:"FromFull"`
+	ToFull            []postmarkAddress `json:"ToFull"`
+	CcFull            []postmarkAddress `json:"CcFull"`
+	BccFull           []postmarkAddress `json:"BccFull"`
+	OriginalRecipient string            `json:"OriginalRecipient"`
+	Subject           string            `json:"Subject"`
+	MessageID         string            `json:"MessageID"`
+	TextBody          string            `json:"TextBody"`
+	HtmlBody          string            `json:"HtmlBody"`
+	Headers           []struct {
+		Name  string `json:"Name"`
+		Value string `json:"Value"`
+	} `json:"Headers"`
+}
+
+func (p *Postmark) Parse(header http.Header, body []byte) (*Message, error) {
+	var payload postmarkPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	// MessageID do payload é o identificador interno do Postmark; o original
+	// está nos cabeçalhos
+	messageID := ""
+	for _, h := range payload.Headers {
+		if strings.EqualFold(h.Name, "Message-ID") {
+			messageID = trimMessageID(h.Value)
+			break
+		}
+	}
+
+	var recipients []string
+	if payload.OriginalRecipient != "" {
+		recipients = append(recipients, payload.OriginalRecipient)
+	} else {
+		for _, list := range [][]postmarkAddress{payload.ToFull, payload.CcFull, payload.BccFull} {
+			for _, a := range list {
+				recipients = append(recipients, a.Email)
+			}
+		}
+	}
+
+	email := &entities.Email{
+		MessageID: messageID,
+		Subject:   payload.Subject,
+		From:      payload.FromFull.Email,
+		Content:   payload.TextBody,
+	}
+	if len(payload.ToFull) > 0 {
+		email.To = payload.ToFull[0].Email
+	}
+	if email.Content == "" {
+		email.Content = payload.HtmlBody
+	}
+
+	return &Message{Email: email, Recipients: recipients}, nil
+}
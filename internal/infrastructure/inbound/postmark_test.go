@@ -0,0 +1,70 @@
+package inbound
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPostmarkVerify(t *testing.T) {
+	p := NewPostmark("inbound", "s3nha")
+	body := readFixture(t, "postmark.json")
+
+	req, _ := http.NewRequest(http.MethodPost, "/", nil)
+	req.SetBasicAuth("inbound", "s3nha")
+	if err := p.Verify(req.Header, body); err != nil {
+		t.Fatalf("credenciais válidas recusadas: %v", err)
+	}
+
+	req.SetBasicAuth("inbound", "errada")
+	if err := p.Verify(req.Header, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("senha errada aceita: %v", err)
+	}
+	if err := p.Verify(http.Header{}, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("requisição sem credenciais aceita: %v", err)
+	}
+}
+
+func TestPostmarkParse(t *testing.T) {
+	msg, err := NewPostmark("inbound", "s3nha").Parse(headerWith("application/json"), readFixture(t, "postmark.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sem OriginalRecipient, To, Cc e Bcc são usados
+	want := []string{fixtureTenant + "@in.example.com", fixtureTenant + "+financeiro@in.example.com"}
+	if strings.Join(msg.Recipients, ",") != strings.Join(want, ",") {
+		t.Errorf("destinatários = %v, esperava %v", msg.Recipients, want)
+	}
+
+	e := msg.Email
+	// O MessageID do payload é interno do Postmark; vale o cabeçalho original
+	if e.MessageID != "contrato-77@mail.example.com" {
+		t.Errorf("Message-ID = %q", e.MessageID)
+	}
+	if e.Subject != "Contrato para assinatura" || e.From != "support@postmarkapp.com" || e.To != fixtureTenant+"@in.example.com" {
+		t.Errorf("email = %+v", e)
+	}
+	if e.Content != "Segue o contrato para assinatura até amanhã." {
+		t.Errorf("conteúdo = %q", e.Content)
+	}
+}
+
+func TestPostmarkParseOriginalRecipient(t *testing.T) {
+	body := strings.Replace(string(readFixture(t, "postmark.json")),
+		`"OriginalRecipient": ""`, `"OriginalRecipient": "`+fixtureTenant+`@in.example.com"`, 1)
+	msg, err := NewPostmark("inbound", "s3nha").Parse(headerWith("application/json"), []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msg.Recipients) != 1 || msg.Recipients[0] != fixtureTenant+"@in.example.com" {
+		t.Errorf("destinatários = %v", msg.Recipients)
+	}
+}
+
+func TestPostmarkParseInvalidPayload(t *testing.T) {
+	if _, err := NewPostmark("a", "b").Parse(headerWith("application/json"), []byte("{")); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("esperava ErrInvalidPayload, obteve %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+// Package inbound interpreta os webhooks de recebimento de emails enviados por
+// provedores transacionais (SendGrid Inbound Parse, Mailgun Routes, Postmark).
+package inbound
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var (
+	ErrInvalidSignature = errors.New("assinatura do webhook inválida")
+	ErrInvalidPayload   = errors.New("payload do webhook inválido")
+)
+
+// maxTimestampSkew diferença máxima aceita entre o timestamp assinado e o
+// relógio local; requisições capturadas não podem ser reenviadas depois dela
+const maxTimestampSkew = 5 * time.Minute
+
+// maxFormMemory limite de memória para formulários multipart (anexos vão para disco)
+const maxFormMemory = 32 << 20
+
+// Message email recebido via webhook. Quando o provedor envia a mensagem MIME
+// completa, Raw é preenchido e deve ser interpretado pelo chamador.
+type Message struct {
+	Email      *entities.Email
+	Raw        []byte
+	Recipients []string // Destinatários do envelope
+}
+
+// Provider formato de webhook de um provedor
+type Provider interface {
+	Name() string
+	// Verify confere a autenticidade da requisição a partir do corpo original
+	Verify(header http.Header, body []byte) error
+	Parse(header http.Header, body []byte) (*Message, error)
+}
+
+// parseForm interpreta corpos multipart/form-data ou x-www-form-urlencoded
+func parseForm(header http.Header, body []byte) (url.Values, error) {
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+
+	if strings.HasPrefix(header.Get("Content-Type"), "multipart/") {
+		if err := req.ParseMultipartForm(maxFormMemory); err != nil {
+			return nil, err
+		}
+		defer req.MultipartForm.RemoveAll()
+		return req.MultipartForm.Value, nil
+	}
+
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+	return req.PostForm, nil
+}
+
+// freshTimestamp indica se o timestamp assinado (segundos Unix) está dentro de
+// maxTimestampSkew de now
+func freshTimestamp(timestamp string, now time.Time) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	skew := now.Sub(time.Unix(seconds, 0))
+	return skew <= maxTimestampSkew && skew >= -maxTimestampSkew
+}
+
+// addresses extrai os endereços de uma lista no formato de cabeçalho
+func addresses(list string) []string {
+	parsed, err := mail.ParseAddressList(list)
+	if err != nil {
+		// Listas malformadas: aproveitar o que parecer endereço
+		var result []string
+		for _, part := range strings.Split(list, ",") {
+			if part = strings.Trim(strings.TrimSpace(part), "<>"); strings.Contains(part, "@") {
+				result = append(result, part)
+			}
+		}
+		return result
+	}
+
+	result := make([]string, 0, len(parsed))
+	for _, a := range parsed {
+		result = append(result, a.Address)
+	}
+	return result
+}
+
+// firstAddress retorna o primeiro endereço da lista
+func firstAddress(list string) string {
+	if all := addresses(list); len(all) > 0 {
+		return all[0]
+	}
+	return ""
+}
+
+// headerValue busca um cabeçalho em um bloco de cabeçalhos RFC 822
+func headerValue(rawHeaders, name string) string {
+	msg, err := mail.ReadMessage(strings.NewReader(rawHeaders + "\r\n\r\n"))
+	if err != nil {
+		return ""
+	}
+	return msg.Header.Get(name)
+}
+
+// trimMessageID remove os delimitadores < > do Message-ID
+func trimMessageID(id string) string {
+	return strings.Trim(strings.TrimSpace(id), "<>")
+}
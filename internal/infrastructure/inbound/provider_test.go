@@ -0,0 +1,54 @@
+package inbound
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fixtureTenant tenant usado nos endereços das fixtures
+const fixtureTenant = "7d9f0c62-3a4e-4b8e-9a51-2f6c1d0e8b11"
+
+// readFixture lê um payload de testdata
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+// fixedClock retorna um relógio parado em t
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func headerWith(contentType string) http.Header {
+	h := http.Header{}
+	h.Set("Content-Type", contentType)
+	return h
+}
+
+func TestFreshTimestamp(t *testing.T) {
+	now := time.Unix(1760866800, 0)
+	cases := []struct {
+		timestamp string
+		want      bool
+	}{
+		{"1760866800", true},
+		{"1760866500", true},  // 5 minutos antes
+		{"1760867100", true},  // 5 minutos depois
+		{"1760866499", false}, // Antigo demais
+		{"1760867101", false}, // No futuro
+		{"", false},
+		{"abc", false},
+	}
+	for _, tc := range cases {
+		if got := freshTimestamp(tc.timestamp, now); got != tc.want {
+			t.Errorf("freshTimestamp(%q) = %v, esperava %v", tc.timestamp, got, tc.want)
+		}
+	}
+}
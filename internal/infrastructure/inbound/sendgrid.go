@@ -0,0 +1,138 @@
+package inbound
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-message/charset"
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+const (
+	sendGridSignatureHeader = "X-Twilio-Email-Event-Webhook-Signature"
+	sendGridTimestampHeader = "X-Twilio-Email-Event-Webhook-Timestamp"
+)
+
+// SendGrid webhook do SendGrid Inbound Parse (multipart/form-data), com ou sem
+// a opção "POST the raw, full MIME message"
+type SendGrid struct {
+	publicKey *ecdsa.PublicKey
+	now       func() time.Time
+}
+
+// NewSendGrid cria o provedor a partir da chave pública de verificação (base64, DER)
+func NewSendGrid(publicKey string) (*SendGrid, error) {
+	der, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("chave pública do SendGrid inválida: %v", err)
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("chave pública do SendGrid inválida: %v", err)
+	}
+	ecKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("chave pública do SendGrid não é ECDSA")
+	}
+	return &SendGrid{publicKey: ecKey, now: time.Now}, nil
+}
+
+func (p *SendGrid) Name() string {
+	return "sendgrid"
+}
+
+// Verify confere a assinatura ECDSA de timestamp + corpo e recusa timestamps
+// fora da tolerância, como no Mailgun
+func (p *SendGrid) Verify(header http.Header, body []byte) error {
+	signature, err := base64.StdEncoding.DecodeString(header.Get(sendGridSignatureHeader))
+	if err != nil || len(signature) == 0 {
+		return ErrInvalidSignature
+	}
+
+	timestamp := header.Get(sendGridTimestampHeader)
+	if !freshTimestamp(timestamp, p.now()) {
+		return ErrInvalidSignature
+	}
+
+	digest := sha256.Sum256(append([]byte(timestamp), body...))
+	if !ecdsa.VerifyASN1(p.publicKey, digest[:], signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (p *SendGrid) Parse(header http.Header, body []byte) (*Message, error) {
+	form, err := parseForm(header, body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	msg := &Message{}
+
+	// Envelope SMTP: {"to": ["..."], "from": "..."}
+	var envelope struct {
+		To   []string `json:"to"`
+		From string   `json:"from"`
+	}
+	if raw := form.Get("envelope"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &envelope); err != nil {
+			return nil, fmt.Errorf("%w: envelope: %v", ErrInvalidPayload, err)
+		}
+	}
+	msg.Recipients = envelope.To
+	if len(msg.Recipients) == 0 {
+		msg.Recipients = addresses(form.Get("to"))
+	}
+
+	// Modo "raw": a mensagem MIME completa vem no campo email
+	if raw := form.Get("email"); raw != "" {
+		msg.Raw = []byte(raw)
+		return msg, nil
+	}
+
+	// Campos decodificados: charsets indica a codificação de cada campo
+	charsets := map[string]string{}
+	if raw := form.Get("charsets"); raw != "" {
+		json.Unmarshal([]byte(raw), &charsets)
+	}
+	field := func(name string) string {
+		return decodeCharset(form.Get(name), charsets[name])
+	}
+
+	msg.Email = &entities.Email{
+		MessageID: trimMessageID(headerValue(form.Get("headers"), "Message-ID")),
+		Subject:   field("subject"),
+		From:      firstAddress(field("from")),
+		To:        firstAddress(field("to")),
+		Content:   field("text"),
+	}
+	if msg.Email.Content == "" {
+		msg.Email.Content = field("html")
+	}
+
+	return msg, nil
+}
+
+// decodeCharset converte o valor para UTF-8 quando o provedor informa outro charset
+func decodeCharset(value, name string) string {
+	if name == "" || strings.EqualFold(name, "utf-8") {
+		return value
+	}
+	r, err := charset.Reader(name, strings.NewReader(value))
+	if err != nil {
+		return value
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return value
+	}
+	return string(decoded)
+}
@@ -0,0 +1,140 @@
+package inbound
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sendGridContentType = "multipart/form-data; boundary=xYzZY"
+
+// newTestSendGrid cria o provedor com uma chave gerada para o teste
+func newTestSendGrid(t *testing.T, now time.Time) (*SendGrid, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewSendGrid(base64.StdEncoding.EncodeToString(der))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.now = fixedClock(now)
+	return p, key
+}
+
+// signSendGrid assina timestamp + corpo como o SendGrid
+func signSendGrid(t *testing.T, key *ecdsa.PrivateKey, timestamp string, body []byte) http.Header {
+	t.Helper()
+	digest := sha256.Sum256(append([]byte(timestamp), body...))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := headerWith(sendGridContentType)
+	h.Set(sendGridSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	h.Set(sendGridTimestampHeader, timestamp)
+	return h
+}
+
+func TestSendGridVerify(t *testing.T) {
+	now := time.Unix(1760866800, 0)
+	p, key := newTestSendGrid(t, now)
+	body := readFixture(t, "sendgrid_parsed.multipart")
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	if err := p.Verify(signSendGrid(t, key, timestamp, body), body); err != nil {
+		t.Fatalf("assinatura válida recusada: %v", err)
+	}
+
+	tampered := append([]byte(nil), body...)
+	tampered[len(tampered)/2] ^= 1
+	if err := p.Verify(signSendGrid(t, key, timestamp, body), tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("corpo alterado aceito: %v", err)
+	}
+
+	other, _ := newTestSendGrid(t, now)
+	if err := other.Verify(signSendGrid(t, key, timestamp, body), body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("assinatura de outra chave aceita: %v", err)
+	}
+
+	// Requisição capturada e reenviada depois da tolerância
+	stale := strconv.FormatInt(now.Add(-maxTimestampSkew-time.Second).Unix(), 10)
+	if err := p.Verify(signSendGrid(t, key, stale, body), body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("timestamp antigo aceito: %v", err)
+	}
+
+	if err := p.Verify(headerWith(sendGridContentType), body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("requisição sem assinatura aceita: %v", err)
+	}
+}
+
+func TestSendGridParseDecodedFields(t *testing.T) {
+	p, _ := newTestSendGrid(t, time.Now())
+	msg, err := p.Parse(headerWith(sendGridContentType), readFixture(t, "sendgrid_parsed.multipart"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Raw != nil {
+		t.Fatal("payload decodificado interpretado como raw")
+	}
+	if want := []string{fixtureTenant + "@in.example.com"}; strings.Join(msg.Recipients, ",") != strings.Join(want, ",") {
+		t.Errorf("destinatários = %v, esperava %v", msg.Recipients, want)
+	}
+
+	e := msg.Email
+	// Os campos vêm em ISO-8859-1, conforme charsets
+	if e.Subject != "Fatura de março vencida" {
+		t.Errorf("assunto = %q", e.Subject)
+	}
+	if !strings.HasPrefix(e.Content, "A fatura de março venceu ontem.") {
+		t.Errorf("conteúdo = %q", e.Content)
+	}
+	if e.From != "joao@example.com" || e.To != fixtureTenant+"@in.example.com" {
+		t.Errorf("remetente/destinatário = %q/%q", e.From, e.To)
+	}
+	if e.MessageID != "CAFx2k9+fatura-0312@mail.example.com" {
+		t.Errorf("Message-ID = %q", e.MessageID)
+	}
+}
+
+func TestSendGridParseRawMIME(t *testing.T) {
+	p, _ := newTestSendGrid(t, time.Now())
+	msg, err := p.Parse(headerWith(sendGridContentType), readFixture(t, "sendgrid_raw.multipart"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Email != nil {
+		t.Error("modo raw não deve preencher Email")
+	}
+	if !strings.Contains(string(msg.Raw), "Subject: Relatório trimestral") {
+		t.Errorf("mensagem raw incompleta: %q", msg.Raw)
+	}
+	// O envelope tem precedência sobre o campo to
+	if len(msg.Recipients) != 2 || msg.Recipients[1] != "outro@in.example.com" {
+		t.Errorf("destinatários = %v", msg.Recipients)
+	}
+}
+
+func TestSendGridParseInvalidPayload(t *testing.T) {
+	p, _ := newTestSendGrid(t, time.Now())
+	body := []byte("--xYzZY\r\nContent-Disposition: form-data; name=\"envelope\"\r\n\r\n{nao e json\r\n--xYzZY--\r\n")
+	if _, err := p.Parse(headerWith(sendGridContentType), body); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("esperava ErrInvalidPayload, obteve %v", err)
+	}
+}
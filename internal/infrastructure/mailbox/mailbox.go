@@ -0,0 +1,14 @@
+// Package mailbox lê mensagens de arquivos mbox e diretórios Maildir.
+package mailbox
+
+// Reader interface comum aos formatos de caixa de correio
+type Reader interface {
+	// Next retorna a próxima mensagem e seu índice; io.EOF ao final
+	Next() (int, []byte, error)
+	Close() error
+}
+
+var (
+	_ Reader = (*MboxReader)(nil)
+	_ Reader = (*MaildirReader)(nil)
+)
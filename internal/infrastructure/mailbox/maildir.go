@@ -0,0 +1,62 @@
+package mailbox
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// MaildirReader percorre as mensagens dos subdiretórios cur/ e new/ de um Maildir
+type MaildirReader struct {
+	files []string
+	index int
+}
+
+// OpenMaildir lista as mensagens do Maildir em ordem determinística
+func OpenMaildir(dir string) (*MaildirReader, error) {
+	var files []string
+	for _, sub := range []string{"cur", "new"} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.Type().IsRegular() {
+				files = append(files, filepath.Join(dir, sub, e.Name()))
+			}
+		}
+	}
+
+	// Nomes de arquivos do Maildir começam pelo timestamp de entrega
+	sort.Slice(files, func(i, j int) bool {
+		return filepath.Base(files[i]) < filepath.Base(files[j])
+	})
+
+	return &MaildirReader{files: files}, nil
+}
+
+// Next retorna a próxima mensagem e seu índice (a partir de 0).
+// Retorna io.EOF quando não há mais mensagens.
+func (m *MaildirReader) Next() (int, []byte, error) {
+	if m.index >= len(m.files) {
+		return 0, nil, io.EOF
+	}
+
+	index := m.index
+	m.index++
+
+	data, err := os.ReadFile(m.files[index])
+	if err != nil {
+		return index, nil, err
+	}
+	return index, data, nil
+}
+
+// Close não mantém recursos abertos
+func (m *MaildirReader) Close() error {
+	return nil
+}
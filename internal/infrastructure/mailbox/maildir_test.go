@@ -0,0 +1,66 @@
+package mailbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMaildirReaderReadsCurAndNew(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"cur/1700000002.M2.host:2,S": "Subject: lida\n",
+		"new/1700000001.M1.host":     "Subject: nova\n",
+		"new/1700000003.M3.host":     "Subject: mais nova\n",
+		"tmp/1700000000.M0.host":     "Subject: em entrega\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Subdiretórios dentro de cur/ não são mensagens
+	if err := os.Mkdir(filepath.Join(dir, "cur", "sub"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := OpenMaildir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// Ordem de entrega, independente do subdiretório; tmp/ é ignorado
+	want := []string{"Subject: nova\n", "Subject: lida\n", "Subject: mais nova\n"}
+	got := readAll(t, r)
+	if len(got) != len(want) {
+		t.Fatalf("lidas %q, esperado %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("mensagem %d = %q, esperado %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMaildirReaderMissingSubdirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "new"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new", "1.M1.host"), []byte("Subject: nova\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := OpenMaildir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readAll(t, r); len(got) != 1 {
+		t.Errorf("lidas %d mensagens sem cur/, esperado 1", len(got))
+	}
+}
@@ -0,0 +1,90 @@
+package mailbox
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+)
+
+// MboxReader percorre as mensagens de um arquivo mbox (variantes mboxo/mboxrd)
+type MboxReader struct {
+	file    *os.File
+	r       *bufio.Reader
+	pending []byte // Linha "From " já lida que inicia a próxima mensagem
+	index   int
+}
+
+// OpenMbox abre um arquivo mbox para leitura
+func OpenMbox(path string) (*MboxReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &MboxReader{file: f, r: bufio.NewReaderSize(f, 64*1024)}, nil
+}
+
+// Next retorna a próxima mensagem e seu índice (a partir de 0).
+// Retorna io.EOF quando não há mais mensagens.
+func (m *MboxReader) Next() (int, []byte, error) {
+	var msg bytes.Buffer
+	started := m.pending != nil
+	m.pending = nil
+
+	for {
+		line, err := m.r.ReadBytes('\n')
+		if len(line) > 0 {
+			if isFromLine(line) {
+				if started {
+					m.pending = line
+					return m.emit(&msg)
+				}
+				started = true
+			} else if started {
+				msg.Write(unescapeFrom(line))
+			}
+		}
+
+		if err == io.EOF {
+			if started {
+				return m.emit(&msg)
+			}
+			return 0, nil, io.EOF
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+}
+
+// Close fecha o arquivo
+func (m *MboxReader) Close() error {
+	return m.file.Close()
+}
+
+func (m *MboxReader) emit(msg *bytes.Buffer) (int, []byte, error) {
+	index := m.index
+	m.index++
+
+	// Remover a linha em branco que separa as mensagens
+	data := msg.Bytes()
+	if bytes.HasSuffix(data, []byte("\r\n\r\n")) {
+		data = data[:len(data)-2]
+	} else if bytes.HasSuffix(data, []byte("\n\n")) {
+		data = data[:len(data)-1]
+	}
+	return index, data, nil
+}
+
+func isFromLine(line []byte) bool {
+	return bytes.HasPrefix(line, []byte("From "))
+}
+
+// unescapeFrom desfaz o escape ">From " aplicado a linhas do corpo
+func unescapeFrom(line []byte) []byte {
+	trimmed := bytes.TrimLeft(line, ">")
+	if len(trimmed) < len(line) && isFromLine(trimmed) {
+		return line[1:]
+	}
+	return line
+}
@@ -0,0 +1,86 @@
+package mailbox
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readAll lê todas as mensagens do leitor
+func readAll(t *testing.T, r Reader) []string {
+	t.Helper()
+	var msgs []string
+	for {
+		index, data, err := r.Next()
+		if err == io.EOF {
+			return msgs
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index != len(msgs) {
+			t.Errorf("índice %d, esperado %d", index, len(msgs))
+		}
+		msgs = append(msgs, string(data))
+	}
+}
+
+func TestMboxReader(t *testing.T) {
+	tests := []struct {
+		name string
+		mbox string
+		want []string
+	}{
+		{
+			name: "vazio",
+			mbox: "",
+		},
+		{
+			name: "separa mensagens e remove a linha em branco final",
+			mbox: "From a@example.com Mon Jan  1 00:00:00 2024\nSubject: um\n\ncorpo um\n\n" +
+				"From b@example.com Mon Jan  1 00:00:00 2024\nSubject: dois\n\ncorpo dois\n",
+			want: []string{"Subject: um\n\ncorpo um\n", "Subject: dois\n\ncorpo dois\n"},
+		},
+		{
+			name: "desfaz o escape de linhas From do corpo",
+			mbox: "From a@example.com Mon Jan  1 00:00:00 2024\nSubject: um\n\n>From o escritório\n>>From citado\n>Outra linha\n",
+			want: []string{"Subject: um\n\nFrom o escritório\n>From citado\n>Outra linha\n"},
+		},
+		{
+			name: "quebras de linha CRLF",
+			mbox: "From a@example.com Mon Jan  1 00:00:00 2024\r\nSubject: um\r\n\r\ncorpo\r\n\r\n" +
+				"From b@example.com Mon Jan  1 00:00:00 2024\r\nSubject: dois\r\n\r\ncorpo\r\n",
+			want: []string{"Subject: um\r\n\r\ncorpo\r\n", "Subject: dois\r\n\r\ncorpo\r\n"},
+		},
+		{
+			name: "ignora conteúdo antes da primeira linha From",
+			mbox: "lixo\nFrom a@example.com Mon Jan  1 00:00:00 2024\nSubject: um\n\ncorpo",
+			want: []string{"Subject: um\n\ncorpo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "caixa.mbox")
+			if err := os.WriteFile(path, []byte(tt.mbox), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			r, err := OpenMbox(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			got := readAll(t, r)
+			if len(got) != len(tt.want) {
+				t.Fatalf("lidas %d mensagens %q, esperado %d", len(got), got, len(tt.want))
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("mensagem %d = %q, esperado %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.BackfillRepository = (*BackfillRepository)(nil)
+
+type BackfillRepository struct {
+	store *Store
+}
+
+func NewBackfillRepository(store *Store) *BackfillRepository {
+	return &BackfillRepository{store: store}
+}
+
+func (r *BackfillRepository) Create(ctx context.Context, job *entities.BackfillJob) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job.ID = newID()
+	job.CreatedAt = now()
+	job.UpdatedAt = job.CreatedAt
+	stored := *job
+	s.backfill[job.ID] = &stored
+	return nil
+}
+
+func (r *BackfillRepository) GetByID(ctx context.Context, id string) (*entities.BackfillJob, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	j, ok := s.backfill[id]
+	if !ok {
+		return nil, fmt.Errorf("erro ao buscar job de backfill: job não encontrado com id: %s", id)
+	}
+	job := *j
+	return &job, nil
+}
+
+func (r *BackfillRepository) UpdateProgress(ctx context.Context, job *entities.BackfillJob) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.backfill[job.ID]
+	if !ok {
+		return fmt.Errorf("erro ao atualizar progresso do backfill: job não encontrado com id: %s", job.ID)
+	}
+	j.Cursor = job.Cursor
+	j.Scanned = job.Scanned
+	j.Classified = job.Classified
+	j.Failed = job.Failed
+	j.LastError = job.LastError
+	j.UpdatedAt = now()
+	job.UpdatedAt = j.UpdatedAt
+	return nil
+}
+
+func (r *BackfillRepository) UpdateStatus(ctx context.Context, id string, status entities.BackfillStatus) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.backfill[id]
+	if !ok {
+		return fmt.Errorf("job de backfill não encontrado com id: %s", id)
+	}
+	j.Status = status
+	j.UpdatedAt = now()
+	return nil
+}
+
+func (r *BackfillRepository) ListUnfinished(ctx context.Context, tenantID, userID string) ([]*entities.BackfillJob, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var jobs []*entities.BackfillJob
+	for _, j := range s.backfill {
+		if j.TenantID == tenantID && j.UserID == userID &&
+			(j.Status == entities.BackfillPending || j.Status == entities.BackfillRunning) {
+			job := *j
+			jobs = append(jobs, &job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
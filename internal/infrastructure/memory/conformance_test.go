@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/enzo010/email-filter/internal/infrastructure/repotest"
+)
+
+func TestRepositoryConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Repositories {
+		store := NewStore()
+		return &repotest.Repositories{
+			Tenants:      NewTenantRepository(store),
+			Users:        NewUserRepository(store),
+			Emails:       NewEmailRepository(store),
+			Tasks:        NewTaskRepository(store),
+			Reminders:    NewReminderRepository(store),
+			Outbox:       NewOutboxRepository(store),
+			Webhooks:     NewWebhookRepository(store),
+			Integrations: NewIntegrationRepository(store),
+			Backfill:     NewBackfillRepository(store),
+			FolderStates: NewFolderStateRepository(store),
+		}
+	})
+}
@@ -0,0 +1,455 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.EmailRepository = (*EmailRepository)(nil)
+
+type EmailRepository struct {
+	store *Store
+}
+
+func NewEmailRepository(store *Store) *EmailRepository {
+	return &EmailRepository{store: store}
+}
+
+func (r *EmailRepository) Create(ctx context.Context, email *entities.Email) (bool, error) {
+	created, err := r.CreateAll(ctx, []*entities.Email{email})
+	if err != nil {
+		return false, err
+	}
+	return created[0], nil
+}
+
+func (r *EmailRepository) CreateAll(ctx context.Context, emails []*entities.Email) ([]bool, error) {
+	for _, email := range emails {
+		if email.TenantID == "" || email.UserID == "" {
+			return nil, entities.ErrEmailOwnerRequired
+		}
+		if !visible(ctx, email.TenantID) {
+			return nil, fmt.Errorf("erro ao inserir email: tenant %s fora do escopo", email.TenantID)
+		}
+		if email.ContentHash == "" {
+			email.ContentHash = email.ComputeContentHash()
+		}
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ids e eventos gerados antes de qualquer alteração, para que a gravação
+	// seja atômica como a transação do banco
+	events := make([][]*entities.Event, len(emails))
+	for i, email := range emails {
+		email.ID = newID()
+		email.CreatedAt = now()
+		email.UpdatedAt = email.CreatedAt
+		for j := range email.Tasks {
+			task := &email.Tasks[j]
+			task.ID = newID()
+			task.EmailID = email.ID
+			task.CreatedAt = email.CreatedAt
+			task.UpdatedAt = email.CreatedAt
+		}
+
+		var err error
+		if events[i], err = entities.EmailEvents(email); err != nil {
+			return nil, err
+		}
+	}
+
+	created := make([]bool, len(emails))
+	for i, email := range emails {
+		// Ignorar mensagens já armazenadas no tenant, inclusive repetidas no lote
+		if existing := s.findDuplicate(email); existing != nil {
+			email.ID, email.CreatedAt, email.UpdatedAt = existing.ID, existing.CreatedAt, existing.UpdatedAt
+			continue
+		}
+		s.insertEmail(ctx, email, events[i])
+		created[i] = true
+	}
+	return created, nil
+}
+
+// insertEmail grava o email já preparado com suas tarefas, reunião e eventos
+func (s *Store) insertEmail(ctx context.Context, email *entities.Email, events []*entities.Event) {
+	stored := cloneEmail(email)
+	stored.Tasks = nil
+	s.emails[email.ID] = stored
+	for i := range email.Tasks {
+		task := email.Tasks[i]
+		s.tasks[task.ID] = &task
+	}
+	if m := email.Meeting; m != nil {
+		if m.Cancelled() {
+			s.cancelMeeting(email.TenantID, m)
+		}
+		s.cancelPrepTasks(email)
+	}
+	s.appendOutbox(ctx, events)
+}
+
+// cancelMeeting marca como canceladas as versões anteriores da reunião no tenant
+func (s *Store) cancelMeeting(tenantID string, cancel *entities.Meeting) {
+	for _, e := range s.emails {
+		if m := e.Meeting; e.TenantID == tenantID && m != nil && m.UID == cancel.UID && m.Sequence <= cancel.Sequence {
+			m.Status = entities.MeetingCancelled
+		}
+	}
+}
+
+// cancelPrepTasks cancela as tarefas de preparação abertas das versões
+// anteriores da reunião do email, registrando a mudança no histórico
+func (s *Store) cancelPrepTasks(email *entities.Email) {
+	meeting := email.Meeting
+	for _, t := range s.tasks {
+		e := s.emails[t.EmailID]
+		if e == nil || e.ID == email.ID || e.TenantID != email.TenantID || !strings.HasPrefix(t.Description, entities.MeetingPrepTask) {
+			continue
+		}
+		if m := e.Meeting; m == nil || m.UID != meeting.UID || m.Sequence > meeting.Sequence {
+			continue
+		}
+		if t.Status != entities.TaskPending && t.Status != entities.TaskInProgress && t.Status != entities.TaskSnoozed {
+			continue
+		}
+
+		change := &entities.TaskStatusChange{
+			ID:         newID(),
+			TaskID:     t.ID,
+			FromStatus: t.Status,
+			ToStatus:   entities.TaskCancelled,
+			Note:       meeting.SupersededNote(),
+			CreatedAt:  now(),
+		}
+		t.Status = entities.TaskCancelled
+		t.SnoozedUntil = nil
+		t.UpdatedAt = change.CreatedAt
+		s.history[t.ID] = append(s.history[t.ID], change)
+	}
+}
+
+// findDuplicate retorna o email do tenant com o mesmo Message-ID ou, na falta
+// dele, o mesmo hash de conteúdo
+func (s *Store) findDuplicate(email *entities.Email) *entities.Email {
+	for _, e := range s.emails {
+		if e.TenantID != email.TenantID {
+			continue
+		}
+		if email.MessageID != "" && e.MessageID == email.MessageID {
+			return e
+		}
+		if email.MessageID == "" && e.MessageID == "" && e.ContentHash == email.ContentHash {
+			return e
+		}
+	}
+	return nil
+}
+
+func (r *EmailRepository) GetByID(ctx context.Context, id string) (*entities.Email, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.emails[id]
+	if !ok || e.TenantID != tenantID {
+		return nil, fmt.Errorf("erro ao buscar email: email não encontrado com id: %s", id)
+	}
+	return s.withTasks(e), nil
+}
+
+// withTasks retorna uma cópia do email com as suas tarefas
+func (s *Store) withTasks(e *entities.Email) *entities.Email {
+	email := cloneEmail(e)
+	for _, t := range s.tasks {
+		if t.EmailID == e.ID {
+			email.Tasks = append(email.Tasks, *t)
+		}
+	}
+	return email
+}
+
+func (r *EmailRepository) Update(ctx context.Context, email *entities.Email) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.emails[email.ID]
+	if !ok || e.TenantID != email.TenantID || e.UserID != email.UserID || !visible(ctx, e.TenantID) {
+		return fmt.Errorf("erro ao atualizar email: email não encontrado com id: %s", email.ID)
+	}
+
+	updatedAt := now()
+	for i := range email.Tasks {
+		task := &email.Tasks[i]
+		task.EmailID = email.ID
+		if task.ID != "" {
+			existing, ok := s.tasks[task.ID]
+			if !ok || existing.EmailID != email.ID {
+				return fmt.Errorf("erro ao atualizar tarefa: tarefa não encontrada com id: %s", task.ID)
+			}
+			task.CreatedAt = existing.CreatedAt
+		} else {
+			task.ID = newID()
+			task.CreatedAt = updatedAt
+		}
+		task.UpdatedAt = updatedAt
+		stored := *task
+		s.tasks[task.ID] = &stored
+	}
+
+	e.Subject = email.Subject
+	e.From = email.From
+	e.To = email.To
+	e.Content = email.Content
+	e.Folder = email.Folder
+	e.Priority = email.Priority
+	e.Category = email.Category
+	e.ProcessedAt = email.ProcessedAt
+	e.Labels = append([]string(nil), email.Labels...)
+	e.UpdatedAt = updatedAt
+	email.UpdatedAt = updatedAt
+	return nil
+}
+
+func (r *EmailRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.emails[id]
+	if !ok || e.TenantID != tenantID {
+		return fmt.Errorf("email não encontrado com id: %s", id)
+	}
+	s.deleteEmail(id)
+	return nil
+}
+
+// deleteEmail remove o email, as suas tarefas e o histórico delas
+func (s *Store) deleteEmail(id string) {
+	for taskID, t := range s.tasks {
+		if t.EmailID == id {
+			s.deleteTask(taskID)
+		}
+	}
+	delete(s.emails, id)
+}
+
+func (r *EmailRepository) ListByTenant(ctx context.Context, tenantID string, filter *entities.EmailFilter) (*entities.PageResult[*entities.Email], error) {
+	return r.list(ctx, filter, func(e *entities.Email) bool { return e.TenantID == tenantID }, true)
+}
+
+func (r *EmailRepository) ListByUser(ctx context.Context, userID string, filter *entities.EmailFilter) (*entities.PageResult[*entities.Email], error) {
+	// Como no banco, a listagem por usuário não inclui labels e tarefas
+	return r.list(ctx, filter, func(e *entities.Email) bool { return e.UserID == userID }, false)
+}
+
+// list filtra, ordena e pagina os emails visíveis que atendem a match
+func (r *EmailRepository) list(ctx context.Context, filter *entities.EmailFilter, match func(*entities.Email) bool, details bool) (*entities.PageResult[*entities.Email], error) {
+	if filter == nil {
+		filter = &entities.EmailFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var emails []*entities.Email
+	for _, e := range s.emails {
+		if !visible(ctx, e.TenantID) || !match(e) || !matchEmailFilter(e, filter) {
+			continue
+		}
+		if details {
+			emails = append(emails, s.withTasks(e))
+		} else {
+			email := cloneEmail(e)
+			email.Labels = nil
+			emails = append(emails, email)
+		}
+	}
+
+	return paginate(filter.Page, filter.Sort == entities.EmailSortCreatedAt, filter.Order, emails,
+		emailCompare(filter.Sort), emailKey)
+}
+
+// matchEmailFilter aplica as condições do filtro de emails
+func matchEmailFilter(e *entities.Email, f *entities.EmailFilter) bool {
+	if !containsAny(f.IDs, e.ID) ||
+		!containsAny(f.Categories, e.Category) ||
+		!containsAny(priorityStrings(f.Priorities), string(e.Priority)) ||
+		!containsAny(f.Folders, e.Folder) ||
+		!inRange(e.CreatedAt, f.StartDate, f.EndDate) {
+		return false
+	}
+	if len(f.Labels) == 0 {
+		return true
+	}
+	for _, label := range e.Labels {
+		if containsAny(f.Labels, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// emailCompare compara emails pelo campo de ordenação
+func emailCompare(sort string) func(a, b *entities.Email) int {
+	switch sort {
+	case entities.EmailSortProcessedAt:
+		return func(a, b *entities.Email) int { return compareTime(a.ProcessedAt, b.ProcessedAt) }
+	case entities.EmailSortPriority:
+		return func(a, b *entities.Email) int { return priorityRank(a.Priority) - priorityRank(b.Priority) }
+	case entities.EmailSortSubject:
+		return func(a, b *entities.Email) int { return strings.Compare(a.Subject, b.Subject) }
+	}
+	return func(a, b *entities.Email) int { return compareTime(a.CreatedAt, b.CreatedAt) }
+}
+
+// emailKey chave de paginação de um email
+func emailKey(e *entities.Email) (time.Time, string) {
+	return e.CreatedAt, e.ID
+}
+
+// Search busca os termos no assunto, remetente e conteúdo, sem stemming. Termos
+// com "-" excluem resultados; a relevância é o número de ocorrências.
+func (r *EmailRepository) Search(ctx context.Context, tenantID string, query *entities.EmailSearchQuery, page entities.Page) (*entities.PageResult[*entities.EmailSearchResult], error) {
+	if err := page.Normalize(); err != nil {
+		return nil, err
+	}
+	include, exclude := searchTerms(query.Text)
+
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var results []*entities.EmailSearchResult
+	for _, e := range s.emails {
+		if e.TenantID != tenantID || !visible(ctx, e.TenantID) || !matchSearchFilters(e, query) {
+			continue
+		}
+
+		text := strings.ToLower(e.Subject + "\n" + e.From + "\n" + e.Content)
+		rank, matched := 0, true
+		for _, term := range include {
+			n := strings.Count(text, term)
+			if n == 0 {
+				matched = false
+				break
+			}
+			rank += n
+		}
+		for _, term := range exclude {
+			if strings.Contains(text, term) {
+				matched = false
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		results = append(results, &entities.EmailSearchResult{
+			Email:     cloneEmail(e),
+			Rank:      float64(rank),
+			Subject:   html.EscapeString(e.Subject),
+			Highlight: html.EscapeString(truncate(e.Content, 200)),
+		})
+	}
+
+	// Mais relevantes primeiro e, entre eles, os mais recentes
+	return paginate(page, false, entities.SortDesc, results,
+		func(a, b *entities.EmailSearchResult) int {
+			if a.Rank != b.Rank {
+				if a.Rank < b.Rank {
+					return -1
+				}
+				return 1
+			}
+			return compareTime(a.Email.CreatedAt, b.Email.CreatedAt)
+		},
+		func(r *entities.EmailSearchResult) (time.Time, string) {
+			return emailKey(r.Email)
+		})
+}
+
+// searchTerms separa os termos da busca em incluídos e excluídos ("-termo")
+func searchTerms(text string) (include, exclude []string) {
+	for _, field := range strings.Fields(strings.ToLower(text)) {
+		field = strings.Trim(field, `"`)
+		switch {
+		case field == "" || field == "or":
+		case strings.HasPrefix(field, "-") && len(field) > 1:
+			exclude = append(exclude, field[1:])
+		default:
+			include = append(include, field)
+		}
+	}
+	return include, exclude
+}
+
+// matchSearchFilters aplica os filtros from: e label: da busca
+func matchSearchFilters(e *entities.Email, query *entities.EmailSearchQuery) bool {
+	for _, sender := range query.From {
+		if !strings.Contains(strings.ToLower(e.From), strings.ToLower(sender)) {
+			return false
+		}
+	}
+	for _, label := range query.Labels {
+		if !slices.Contains(e.Labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// truncate limita o texto a n caracteres
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// priorityStrings converte prioridades para comparação com os valores armazenados
+func priorityStrings(priorities []entities.Priority) []string {
+	values := make([]string, len(priorities))
+	for i, p := range priorities {
+		values[i] = string(p)
+	}
+	return values
+}
+
+// cloneEmail copia o email para que o chamador não altere o armazenamento
+func cloneEmail(e *entities.Email) *entities.Email {
+	email := *e
+	email.Labels = append([]string(nil), e.Labels...)
+	email.Tasks = append([]entities.Task(nil), e.Tasks...)
+	if e.Meeting != nil {
+		meeting := *e.Meeting
+		meeting.Attendees = append([]string(nil), e.Meeting.Attendees...)
+		email.Meeting = &meeting
+	}
+	return &email
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.FolderStateRepository = (*FolderStateRepository)(nil)
+
+// folderKey identifica uma pasta de uma caixa de correio
+type folderKey struct {
+	account string
+	folder  string
+}
+
+type FolderStateRepository struct {
+	store *Store
+}
+
+func NewFolderStateRepository(store *Store) *FolderStateRepository {
+	return &FolderStateRepository{store: store}
+}
+
+func (r *FolderStateRepository) GetFolderState(ctx context.Context, account, folder string) (*entities.FolderState, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored, ok := s.folderStates[folderKey{account, folder}]
+	if !ok {
+		return nil, nil
+	}
+	state := *stored
+	return &state, nil
+}
+
+func (r *FolderStateRepository) SaveFolderState(ctx context.Context, state *entities.FolderState) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *state
+	s.folderStates[folderKey{state.Account, state.Folder}] = &stored
+	return nil
+}
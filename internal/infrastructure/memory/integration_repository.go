@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.IntegrationRepository = (*IntegrationRepository)(nil)
+
+type IntegrationRepository struct {
+	store *Store
+}
+
+func NewIntegrationRepository(store *Store) *IntegrationRepository {
+	return &IntegrationRepository{store: store}
+}
+
+func (r *IntegrationRepository) Create(ctx context.Context, integration *entities.Integration) error {
+	if !visible(ctx, integration.TenantID) {
+		return fmt.Errorf("erro ao criar integração: tenant %s fora do escopo", integration.TenantID)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants[integration.TenantID]; !ok {
+		return fmt.Errorf("erro ao criar integração: tenant não encontrado: %s", integration.TenantID)
+	}
+	integration.ID = newID()
+	integration.CreatedAt = now()
+	integration.UpdatedAt = integration.CreatedAt
+	s.integrations[integration.ID] = cloneIntegration(integration)
+	return nil
+}
+
+func (r *IntegrationRepository) GetByID(ctx context.Context, id string) (*entities.Integration, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i, ok := s.integrations[id]
+	if !ok || i.TenantID != tenantID {
+		return nil, fmt.Errorf("erro ao buscar integração: integração não encontrada com id: %s", id)
+	}
+	return cloneIntegration(i), nil
+}
+
+func (r *IntegrationRepository) ListByTenant(ctx context.Context, tenantID string) ([]*entities.Integration, error) {
+	return r.list(ctx, func(i *entities.Integration) bool { return i.TenantID == tenantID })
+}
+
+func (r *IntegrationRepository) ListActive(ctx context.Context, tenantID string) ([]*entities.Integration, error) {
+	return r.list(ctx, func(i *entities.Integration) bool { return i.TenantID == tenantID && i.Active })
+}
+
+// list retorna as integrações visíveis que atendem a match, das mais antigas para as mais novas
+func (r *IntegrationRepository) list(ctx context.Context, match func(*entities.Integration) bool) ([]*entities.Integration, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var integrations []*entities.Integration
+	for _, i := range s.integrations {
+		if visible(ctx, i.TenantID) && match(i) {
+			integrations = append(integrations, cloneIntegration(i))
+		}
+	}
+	sort.Slice(integrations, func(i, j int) bool {
+		a, b := integrations[i], integrations[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+	return integrations, nil
+}
+
+func (r *IntegrationRepository) Update(ctx context.Context, integration *entities.Integration) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, ok := s.integrations[integration.ID]
+	if !ok || i.TenantID != tenantID {
+		return fmt.Errorf("erro ao atualizar integração: integração não encontrada com id: %s", integration.ID)
+	}
+	updated := cloneIntegration(integration)
+	i.Name = updated.Name
+	i.WebhookURL = updated.WebhookURL
+	i.Criteria = updated.Criteria
+	i.Active = updated.Active
+	i.UpdatedAt = now()
+	integration.UpdatedAt = i.UpdatedAt
+	return nil
+}
+
+func (r *IntegrationRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, ok := s.integrations[id]
+	if !ok || i.TenantID != tenantID {
+		return fmt.Errorf("integração não encontrada com id: %s", id)
+	}
+	delete(s.integrations, id)
+	return nil
+}
+
+// cloneIntegration copia a integração para que o chamador não altere o armazenamento
+func cloneIntegration(i *entities.Integration) *entities.Integration {
+	integration := *i
+	integration.Criteria.Priorities = append([]entities.Priority(nil), i.Criteria.Priorities...)
+	integration.Criteria.Categories = append([]string(nil), i.Criteria.Categories...)
+	return &integration
+}
@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.OutboxRepository = (*OutboxRepository)(nil)
+
+type OutboxRepository struct {
+	store *Store
+}
+
+func NewOutboxRepository(store *Store) *OutboxRepository {
+	return &OutboxRepository{store: store}
+}
+
+// appendOutbox grava os eventos no outbox; não faz nada com os eventos
+// desativados no contexto. Deve ser chamada com o lock de escrita.
+func (s *Store) appendOutbox(ctx context.Context, events []*entities.Event) {
+	if entities.EventsDisabled(ctx) {
+		return
+	}
+	for _, event := range events {
+		s.outboxSeq++
+		s.outbox = append(s.outbox, &entities.OutboxEntry{
+			ID:            s.outboxSeq,
+			Event:         event,
+			NextAttemptAt: event.CreatedAt,
+		})
+	}
+}
+
+func (r *OutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.OutboxEntry, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A entrada mais antiga de cada tenant decide se ele pode ser publicado
+	due := make(map[string]bool)
+	for _, e := range s.outbox {
+		if e.ParkedAt != nil {
+			continue
+		}
+		if _, seen := due[e.Event.TenantID]; !seen {
+			due[e.Event.TenantID] = !e.NextAttemptAt.After(now)
+		}
+	}
+
+	var entries []*entities.OutboxEntry
+	for _, e := range s.outbox {
+		if len(entries) == limit {
+			break
+		}
+		if e.ParkedAt == nil && due[e.Event.TenantID] {
+			e.NextAttemptAt = now.Add(lease)
+			entries = append(entries, cloneOutboxEntry(e))
+		}
+	}
+	return entries, nil
+}
+
+func (r *OutboxRepository) Complete(ctx context.Context, id int64) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, e := range s.outbox {
+		if e.ID == id {
+			s.outbox = append(s.outbox[:i], s.outbox[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *OutboxRepository) RecordFailure(ctx context.Context, entry *entities.OutboxEntry) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.outbox {
+		if e.ID == entry.ID {
+			e.Delivered = append([]string(nil), entry.Delivered...)
+			e.Attempts = entry.Attempts
+			e.LastError = entry.LastError
+			e.NextAttemptAt = entry.NextAttemptAt
+			e.ParkedAt = nil
+			if entry.ParkedAt != nil {
+				at := *entry.ParkedAt
+				e.ParkedAt = &at
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// cloneOutboxEntry copia a entrada para que o chamador não altere o armazenamento
+func cloneOutboxEntry(e *entities.OutboxEntry) *entities.OutboxEntry {
+	entry := *e
+	event := *e.Event
+	entry.Event = &event
+	entry.Delivered = append([]string(nil), e.Delivered...)
+	if e.ParkedAt != nil {
+		at := *e.ParkedAt
+		entry.ParkedAt = &at
+	}
+	return &entry
+}
@@ -0,0 +1,180 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.ReminderRepository = (*ReminderRepository)(nil)
+
+type ReminderRepository struct {
+	store *Store
+}
+
+func NewReminderRepository(store *Store) *ReminderRepository {
+	return &ReminderRepository{store: store}
+}
+
+// reminderKey identifica o envio de um lembrete em um canal
+type reminderKey struct {
+	taskID  string
+	kind    entities.ReminderKind
+	dueDate int64 // UnixNano do prazo
+	channel string
+}
+
+func newReminderKey(reminder *entities.Reminder, channel string) reminderKey {
+	return reminderKey{reminder.Task.ID, reminder.Kind, reminder.Task.DueDate.UnixNano(), channel}
+}
+
+// open indica se a tarefa está aberta para lembretes e marcação de atraso
+func open(t *entities.Task) bool {
+	return t.Status == entities.TaskPending || t.Status == entities.TaskInProgress
+}
+
+// recipient destinatário da tarefa: o responsável ou, na falta dele, o dono do email
+func recipient(t *entities.Task, e *entities.Email) string {
+	if t.AssigneeID != "" {
+		return t.AssigneeID
+	}
+	return e.UserID
+}
+
+func (r *ReminderRepository) Due(ctx context.Context, now time.Time, lead time.Duration, channels []string, limit int) ([]*entities.Reminder, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var reminders []*entities.Reminder
+	for _, t := range s.tasks {
+		if !open(t) || t.DueDate.After(now.Add(lead)) {
+			continue
+		}
+		e, ok := s.emails[t.EmailID]
+		if !ok {
+			continue
+		}
+		u, ok := s.users[recipient(t, e)]
+		if !ok || !u.Active {
+			continue
+		}
+
+		rem := &entities.Reminder{
+			Kind:         entities.ReminderDueSoon,
+			Task:         *t,
+			TenantID:     e.TenantID,
+			UserID:       u.ID,
+			Recipient:    u.Email,
+			EmailSubject: e.Subject,
+		}
+		if !t.DueDate.After(now) {
+			rem.Kind = entities.ReminderOverdue
+		}
+
+		pending := false
+		for _, channel := range channels {
+			if _, sent := s.reminders[newReminderKey(rem, channel)]; !sent {
+				pending = true
+				break
+			}
+		}
+		if pending {
+			reminders = append(reminders, rem)
+		}
+	}
+
+	sort.Slice(reminders, func(i, j int) bool {
+		a, b := reminders[i].Task, reminders[j].Task
+		if !a.DueDate.Equal(b.DueDate) {
+			return a.DueDate.Before(b.DueDate)
+		}
+		return a.ID < b.ID
+	})
+	if len(reminders) > limit {
+		reminders = reminders[:limit]
+	}
+	return reminders, nil
+}
+
+func (r *ReminderRepository) Claim(ctx context.Context, reminder *entities.Reminder, channel string) (bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := newReminderKey(reminder, channel)
+	if _, sent := s.reminders[key]; sent {
+		return false, nil
+	}
+	if _, ok := s.tasks[reminder.Task.ID]; !ok {
+		return false, nil
+	}
+	s.reminders[key] = now()
+	return true, nil
+}
+
+func (r *ReminderRepository) Release(ctx context.Context, reminder *entities.Reminder, channel string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.reminders, newReminderKey(reminder, channel))
+	return nil
+}
+
+func (r *ReminderRepository) MarkOverdue(ctx context.Context, now time.Time) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var marked []*entities.Task
+	var events []*entities.Event
+	for _, t := range s.tasks {
+		if open(t) && !t.DueDate.After(now) && t.OverdueAt == nil {
+			task := *t
+			at := now
+			task.OverdueAt = &at
+			email := s.emails[t.EmailID]
+			event, err := entities.NewTaskEvent(email.TenantID, entities.EventTaskOverdue, task, email.Subject)
+			if err != nil {
+				return 0, err
+			}
+			marked = append(marked, t)
+			events = append(events, event)
+		}
+	}
+	for _, t := range marked {
+		at := now
+		t.OverdueAt = &at
+	}
+	s.appendOutbox(ctx, events)
+	return int64(len(marked)), nil
+}
+
+func (r *ReminderRepository) WakeSnoozed(ctx context.Context, at time.Time) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var woken int64
+	for _, t := range s.tasks {
+		if t.Status != entities.TaskSnoozed || t.SnoozedUntil == nil || t.SnoozedUntil.After(at) {
+			continue
+		}
+		t.Status = entities.TaskPending
+		t.SnoozedUntil = nil
+		t.UpdatedAt = now()
+		s.history[t.ID] = append(s.history[t.ID], &entities.TaskStatusChange{
+			ID:         newID(),
+			TaskID:     t.ID,
+			FromStatus: entities.TaskSnoozed,
+			ToStatus:   entities.TaskPending,
+			Note:       entities.SnoozeExpiredNote,
+			CreatedAt:  t.UpdatedAt,
+		})
+		woken++
+	}
+	return woken, nil
+}
@@ -0,0 +1,168 @@
+// Package memory implementa os repositórios em memória, para testes e
+// demonstrações locais sem PostgreSQL. Os dados são perdidos ao encerrar o
+// processo.
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// Store armazena os dados compartilhados pelos repositórios, permitindo as
+// mesmas verificações entre tabelas feitas no banco (ex: tarefa pertence a um
+// email do tenant, remoção em cascata)
+type Store struct {
+	mu           sync.RWMutex
+	tenants      map[string]*entities.Tenant
+	users        map[string]*entities.User
+	emails       map[string]*entities.Email
+	tasks        map[string]*entities.Task
+	history      map[string][]*entities.TaskStatusChange // Por tarefa
+	reminders    map[reminderKey]time.Time               // Envios registrados
+	backfill     map[string]*entities.BackfillJob
+	webhooks     map[string]*entities.Webhook
+	deliveries   map[string]*entities.WebhookDelivery
+	integrations map[string]*entities.Integration
+	outbox       []*entities.OutboxEntry // Em ordem de ID
+	outboxSeq    int64
+	folderStates map[folderKey]*entities.FolderState
+}
+
+// NewStore cria um armazenamento vazio
+func NewStore() *Store {
+	return &Store{
+		tenants:      make(map[string]*entities.Tenant),
+		users:        make(map[string]*entities.User),
+		emails:       make(map[string]*entities.Email),
+		tasks:        make(map[string]*entities.Task),
+		history:      make(map[string][]*entities.TaskStatusChange),
+		reminders:    make(map[reminderKey]time.Time),
+		backfill:     make(map[string]*entities.BackfillJob),
+		webhooks:     make(map[string]*entities.Webhook),
+		deliveries:   make(map[string]*entities.WebhookDelivery),
+		integrations: make(map[string]*entities.Integration),
+		folderStates: make(map[folderKey]*entities.FolderState),
+	}
+}
+
+// newID gera um UUID v4, no mesmo formato dos IDs gerados pelo banco
+func newID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// now retorna o horário atual com a precisão de timestamps do PostgreSQL
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// requireTenant retorna o tenant do contexto ou ErrNoTenantContext
+func requireTenant(ctx context.Context) (string, error) {
+	tenantID := entities.TenantFromContext(ctx)
+	if tenantID == "" {
+		return "", entities.ErrNoTenantContext
+	}
+	return tenantID, nil
+}
+
+// visible emula as políticas de RLS: com tenant no contexto, apenas as linhas
+// desse tenant são visíveis
+func visible(ctx context.Context, tenantID string) bool {
+	scope := entities.TenantFromContext(ctx)
+	return scope == "" || scope == tenantID
+}
+
+// paginate ordena os itens já filtrados e aplica cursor, deslocamento e limite.
+// compare compara a expressão de ordenação de dois itens em ordem crescente.
+func paginate[T any](page entities.Page, keyset bool, order entities.SortOrder, items []T,
+	compare func(a, b T) int, key func(T) (time.Time, string)) (*entities.PageResult[T], error) {
+	p, err := entities.NewPager(page, keyset)
+	if err != nil {
+		return nil, err
+	}
+	total := int64(len(items))
+
+	// Ordenação com o id como desempate, no sentido de leitura
+	desc := p.Order(order) != entities.SortAsc
+	sort.SliceStable(items, func(i, j int) bool {
+		c := compare(items[i], items[j])
+		if c == 0 {
+			_, a := key(items[i])
+			_, b := key(items[j])
+			c = strings.Compare(a, b)
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	var selected []T
+	for _, item := range items {
+		createdAt, id := key(item)
+		if p.Includes(order, createdAt, id) {
+			selected = append(selected, item)
+		}
+	}
+	if p.Offset >= len(selected) {
+		selected = nil
+	} else {
+		selected = selected[p.Offset:min(len(selected), p.Offset+p.Size+1)]
+	}
+
+	result := entities.Paginate(p, selected, key)
+	if page.WithTotal {
+		result.Total = &total
+	}
+	return result, nil
+}
+
+// compareTime compara datas para ordenação
+func compareTime(a, b time.Time) int {
+	return a.Compare(b)
+}
+
+// priorityRank ordena prioridades por importância, como no banco
+func priorityRank(p entities.Priority) int {
+	switch p {
+	case entities.PriorityHigh:
+		return 3
+	case entities.PriorityMedium:
+		return 2
+	}
+	return 1
+}
+
+// containsAny informa se value está em values; lista vazia não filtra
+func containsAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// inRange informa se t está entre as datas do filtro
+func inRange(t time.Time, start, end *time.Time) bool {
+	if start != nil && t.Before(*start) {
+		return false
+	}
+	if end != nil && t.After(*end) {
+		return false
+	}
+	return true
+}
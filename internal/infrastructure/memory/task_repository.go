@@ -0,0 +1,301 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.TaskRepository = (*TaskRepository)(nil)
+
+type TaskRepository struct {
+	store *Store
+}
+
+func NewTaskRepository(store *Store) *TaskRepository {
+	return &TaskRepository{store: store}
+}
+
+func (r *TaskRepository) Create(ctx context.Context, task *entities.Task) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+	if !task.Status.Valid() {
+		return fmt.Errorf("status inválido: %s", task.Status)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email, ok := s.emails[task.EmailID]
+	if !ok || email.TenantID != tenantID {
+		return fmt.Errorf("email não encontrado com id: %s", task.EmailID)
+	}
+	if err := s.checkAssignee(tenantID, task.AssigneeID); err != nil {
+		return err
+	}
+
+	task.ID = newID()
+	task.CreatedAt = now()
+	task.UpdatedAt = task.CreatedAt
+	event, err := entities.NewTaskEvent(tenantID, entities.EventTaskCreated, *task, email.Subject)
+	if err != nil {
+		return err
+	}
+	stored := *task
+	s.tasks[task.ID] = &stored
+	s.appendOutbox(ctx, []*entities.Event{event})
+	return nil
+}
+
+func (r *TaskRepository) GetByID(ctx context.Context, id string) (*entities.Task, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.lookupTask(id, tenantID)
+	if !ok {
+		return nil, fmt.Errorf("erro ao buscar tarefa: tarefa não encontrada com id: %s", id)
+	}
+	task := *t
+	return &task, nil
+}
+
+// lookupTask retorna a tarefa se o seu email pertencer ao tenant
+func (s *Store) lookupTask(id, tenantID string) (*entities.Task, bool) {
+	t, ok := s.tasks[id]
+	if !ok {
+		return nil, false
+	}
+	e, ok := s.emails[t.EmailID]
+	if !ok || e.TenantID != tenantID {
+		return nil, false
+	}
+	return t, true
+}
+
+func (r *TaskRepository) Update(ctx context.Context, task *entities.Task) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.lookupTask(task.ID, tenantID)
+	if !ok {
+		return fmt.Errorf("tarefa não encontrada com id: %s", task.ID)
+	}
+	if task.Priority != entities.PriorityHigh &&
+		task.Priority != entities.PriorityMedium &&
+		task.Priority != entities.PriorityLow {
+		return fmt.Errorf("prioridade inválida: %s", task.Priority)
+	}
+	if err := s.checkAssignee(tenantID, task.AssigneeID); err != nil {
+		return err
+	}
+
+	t.Description = task.Description
+	t.Priority = task.Priority
+	t.AssigneeID = task.AssigneeID
+	if !t.DueDate.Equal(task.DueDate) {
+		t.OverdueAt = nil
+	}
+	t.DueDate = task.DueDate
+	t.UpdatedAt = now()
+	task.OverdueAt = t.OverdueAt
+	task.UpdatedAt = t.UpdatedAt
+	return nil
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lookupTask(id, tenantID); !ok {
+		return fmt.Errorf("tarefa não encontrada com id: %s", id)
+	}
+	s.deleteTask(id)
+	return nil
+}
+
+// deleteTask remove a tarefa, o seu histórico e os lembretes registrados
+func (s *Store) deleteTask(id string) {
+	delete(s.tasks, id)
+	delete(s.history, id)
+	for key := range s.reminders {
+		if key.taskID == id {
+			delete(s.reminders, key)
+		}
+	}
+}
+
+func (r *TaskRepository) ListByTenant(ctx context.Context, tenantID string, filter *entities.TaskFilter) (*entities.PageResult[*entities.Task], error) {
+	if filter == nil {
+		filter = &entities.TaskFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, filter, func(t *entities.Task, e *entities.Email) bool {
+		return e.TenantID == tenantID && containsAny(filter.Statuses, string(t.Status)) &&
+			(filter.AssigneeID == "" || t.AssigneeID == filter.AssigneeID) &&
+			(filter.UserID == "" || recipient(t, e) == filter.UserID)
+	})
+}
+
+func (r *TaskRepository) ListByEmail(ctx context.Context, emailID string, filter *entities.TaskFilter) (*entities.PageResult[*entities.Task], error) {
+	if filter == nil {
+		filter = &entities.TaskFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, filter, func(t *entities.Task, e *entities.Email) bool {
+		return t.EmailID == emailID && containsAny(filter.Statuses, string(t.Status)) &&
+			(filter.AssigneeID == "" || t.AssigneeID == filter.AssigneeID) &&
+			(filter.UserID == "" || recipient(t, e) == filter.UserID)
+	})
+}
+
+func (r *TaskRepository) ListPendingTasks(ctx context.Context, userID string, filter *entities.TaskFilter) (*entities.PageResult[*entities.Task], error) {
+	if filter == nil {
+		filter = &entities.TaskFilter{}
+	}
+	// Por padrão, as tarefas com prazo mais próximo primeiro
+	if filter.Sort == "" {
+		filter.Sort = entities.TaskSortDueDate
+		if filter.Order == "" {
+			filter.Order = entities.SortAsc
+		}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, filter, func(t *entities.Task, e *entities.Email) bool {
+		return recipient(t, e) == userID && t.Status.Open()
+	})
+}
+
+// list filtra, ordena e pagina as tarefas visíveis que atendem a match
+func (r *TaskRepository) list(ctx context.Context, filter *entities.TaskFilter, match func(*entities.Task, *entities.Email) bool) (*entities.PageResult[*entities.Task], error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tasks []*entities.Task
+	for _, t := range s.tasks {
+		e, ok := s.emails[t.EmailID]
+		if !ok || !visible(ctx, e.TenantID) || !match(t, e) {
+			continue
+		}
+		if !containsAny(priorityStrings(filter.Priorities), string(t.Priority)) ||
+			!inRange(t.DueDate, filter.StartDate, filter.EndDate) {
+			continue
+		}
+		task := *t
+		tasks = append(tasks, &task)
+	}
+
+	return paginate(filter.Page, filter.Sort == entities.TaskSortCreatedAt, filter.Order, tasks,
+		taskCompare(filter.Sort), taskKey)
+}
+
+func (r *TaskRepository) Transition(ctx context.Context, task *entities.Task, change *entities.TaskStatusChange) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.lookupTask(task.ID, tenantID)
+	if !ok {
+		return fmt.Errorf("tarefa não encontrada com id: %s", task.ID)
+	}
+	if t.Status != change.FromStatus {
+		return fmt.Errorf("%w: tarefa %s não está mais em %s", entities.ErrInvalidTransition, task.ID, change.FromStatus)
+	}
+
+	t.Status = change.ToStatus
+	t.SnoozedUntil = task.SnoozedUntil
+	t.UpdatedAt = now()
+	task.UpdatedAt = t.UpdatedAt
+
+	change.ID = newID()
+	change.TaskID = task.ID
+	change.CreatedAt = t.UpdatedAt
+	stored := *change
+	s.history[task.ID] = append(s.history[task.ID], &stored)
+	return nil
+}
+
+func (r *TaskRepository) History(ctx context.Context, taskID string) ([]*entities.TaskStatusChange, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.lookupTask(taskID, tenantID); !ok {
+		return nil, fmt.Errorf("tarefa não encontrada com id: %s", taskID)
+	}
+	history := make([]*entities.TaskStatusChange, 0, len(s.history[taskID]))
+	for _, c := range s.history[taskID] {
+		change := *c
+		history = append(history, &change)
+	}
+	return history, nil
+}
+
+// checkAssignee confirma que o responsável, se informado, pertence ao tenant
+func (s *Store) checkAssignee(tenantID, assigneeID string) error {
+	if assigneeID == "" {
+		return nil
+	}
+	if u, ok := s.users[assigneeID]; !ok || u.TenantID != tenantID {
+		return fmt.Errorf("usuário não encontrado com id: %s", assigneeID)
+	}
+	return nil
+}
+
+// taskCompare compara tarefas pelo campo de ordenação
+func taskCompare(sort string) func(a, b *entities.Task) int {
+	switch sort {
+	case entities.TaskSortDueDate:
+		return func(a, b *entities.Task) int { return compareTime(a.DueDate, b.DueDate) }
+	case entities.TaskSortPriority:
+		return func(a, b *entities.Task) int { return priorityRank(a.Priority) - priorityRank(b.Priority) }
+	}
+	return func(a, b *entities.Task) int { return compareTime(a.CreatedAt, b.CreatedAt) }
+}
+
+// taskKey chave de paginação de uma tarefa
+func taskKey(t *entities.Task) (time.Time, string) {
+	return t.CreatedAt, t.ID
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.TenantRepository = (*TenantRepository)(nil)
+
+type TenantRepository struct {
+	store *Store
+}
+
+func NewTenantRepository(store *Store) *TenantRepository {
+	return &TenantRepository{store: store}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, tenant *entities.Tenant) error {
+	if tenant.Plan == "" {
+		tenant.Plan = "free"
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tenant.ID == "" {
+		tenant.ID = newID()
+	}
+	if _, exists := s.tenants[tenant.ID]; exists {
+		return fmt.Errorf("error creating tenant: duplicate id %s", tenant.ID)
+	}
+	tenant.CreatedAt = now()
+	tenant.UpdatedAt = tenant.CreatedAt
+	stored := *tenant
+	s.tenants[tenant.ID] = &stored
+	return nil
+}
+
+func (r *TenantRepository) GetByID(ctx context.Context, id string) (*entities.Tenant, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.tenants[id]
+	if !ok {
+		return nil, fmt.Errorf("error scanning tenant: tenant not found: %s", id)
+	}
+	tenant := *t
+	return &tenant, nil
+}
+
+func (r *TenantRepository) Update(ctx context.Context, tenant *entities.Tenant) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tenants[tenant.ID]
+	if !ok {
+		return fmt.Errorf("error updating tenant: tenant not found: %s", tenant.ID)
+	}
+	t.Name = tenant.Name
+	t.Plan = tenant.Plan
+	t.Active = tenant.Active
+	t.UpdatedAt = now()
+	tenant.UpdatedAt = t.UpdatedAt
+	return nil
+}
+
+// Delete remove o tenant; usuários, emails, tarefas, jobs de backfill,
+// webhooks, integrações e eventos do outbox são removidos em cascata
+func (r *TenantRepository) Delete(ctx context.Context, id string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants[id]; !ok {
+		return fmt.Errorf("tenant not found: %s", id)
+	}
+	for emailID, e := range s.emails {
+		if e.TenantID == id {
+			s.deleteEmail(emailID)
+		}
+	}
+	for userID, u := range s.users {
+		if u.TenantID == id {
+			delete(s.users, userID)
+		}
+	}
+	for jobID, job := range s.backfill {
+		if job.TenantID == id {
+			delete(s.backfill, jobID)
+		}
+	}
+	for webhookID, w := range s.webhooks {
+		if w.TenantID == id {
+			s.deleteWebhook(webhookID)
+		}
+	}
+	for integrationID, i := range s.integrations {
+		if i.TenantID == id {
+			delete(s.integrations, integrationID)
+		}
+	}
+	s.outbox = slices.DeleteFunc(s.outbox, func(e *entities.OutboxEntry) bool {
+		return e.Event.TenantID == id
+	})
+	delete(s.tenants, id)
+	return nil
+}
+
+func (r *TenantRepository) List(ctx context.Context) ([]*entities.Tenant, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tenants []*entities.Tenant
+	for _, t := range s.tenants {
+		tenant := *t
+		tenants = append(tenants, &tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].CreatedAt.Before(tenants[j].CreatedAt)
+	})
+	return tenants, nil
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.UserRepository = (*UserRepository)(nil)
+
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	if user.Role == "" {
+		user.Role = "user"
+	}
+	if !visible(ctx, user.TenantID) {
+		return fmt.Errorf("error creating user: tenant %s out of scope", user.TenantID)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants[user.TenantID]; !ok {
+		return fmt.Errorf("error creating user: tenant not found: %s", user.TenantID)
+	}
+	for _, u := range s.users {
+		if u.Email == user.Email {
+			return fmt.Errorf("error creating user: email already registered: %s", user.Email)
+		}
+	}
+
+	if user.ID == "" {
+		user.ID = newID()
+	}
+	user.CreatedAt = now()
+	user.UpdatedAt = user.CreatedAt
+	stored := *user
+	s.users[user.ID] = &stored
+	return nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*entities.User, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[id]
+	if !ok || !visible(ctx, u.TenantID) {
+		return nil, fmt.Errorf("error scanning user: user not found: %s", id)
+	}
+	user := *u
+	return &user, nil
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Usado no login, quando o tenant ainda não é conhecido
+	for _, u := range s.users {
+		if u.Email == email {
+			user := *u
+			return &user, nil
+		}
+	}
+	return nil, fmt.Errorf("error scanning user: user not found: %s", email)
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[user.ID]
+	if !ok || u.TenantID != user.TenantID || !visible(ctx, u.TenantID) {
+		return fmt.Errorf("error updating user: user not found: %s", user.ID)
+	}
+	for _, other := range s.users {
+		if other.ID != u.ID && other.Email == user.Email {
+			return fmt.Errorf("error updating user: email already registered: %s", user.Email)
+		}
+	}
+
+	u.Name = user.Name
+	u.Email = user.Email
+	u.PasswordHash = user.PasswordHash
+	u.Role = user.Role
+	u.Active = user.Active
+	u.UpdatedAt = now()
+	user.UpdatedAt = u.UpdatedAt
+	return nil
+}
+
+func (r *UserRepository) RotateFeedToken(ctx context.Context, id string) (int, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok || u.TenantID != tenantID {
+		return 0, fmt.Errorf("user not found: %s", id)
+	}
+	u.FeedTokenVersion++
+	u.UpdatedAt = now()
+	return u.FeedTokenVersion, nil
+}
+
+// Delete remove o usuário e, em cascata, os seus emails
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok || u.TenantID != tenantID {
+		return fmt.Errorf("user not found: %s", id)
+	}
+	for emailID, e := range s.emails {
+		if e.UserID == id {
+			s.deleteEmail(emailID)
+		}
+	}
+	for _, t := range s.tasks {
+		if t.AssigneeID == id {
+			t.AssigneeID = ""
+		}
+	}
+	delete(s.users, id)
+	return nil
+}
+
+func (r *UserRepository) ListByTenant(ctx context.Context, tenantID string) ([]*entities.User, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var users []*entities.User
+	for _, u := range s.users {
+		if u.TenantID == tenantID && visible(ctx, u.TenantID) {
+			user := *u
+			users = append(users, &user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.Before(users[j].CreatedAt)
+	})
+	return users, nil
+}
@@ -0,0 +1,279 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.WebhookRepository = (*WebhookRepository)(nil)
+
+type WebhookRepository struct {
+	store *Store
+}
+
+func NewWebhookRepository(store *Store) *WebhookRepository {
+	return &WebhookRepository{store: store}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *entities.Webhook) error {
+	if !visible(ctx, webhook.TenantID) {
+		return fmt.Errorf("erro ao criar webhook: tenant %s fora do escopo", webhook.TenantID)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants[webhook.TenantID]; !ok {
+		return fmt.Errorf("erro ao criar webhook: tenant não encontrado: %s", webhook.TenantID)
+	}
+	webhook.ID = newID()
+	webhook.CreatedAt = now()
+	webhook.UpdatedAt = webhook.CreatedAt
+	s.webhooks[webhook.ID] = cloneWebhook(webhook)
+	return nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*entities.Webhook, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w, ok := s.webhooks[id]
+	if !ok || w.TenantID != tenantID {
+		return nil, fmt.Errorf("erro ao buscar webhook: webhook não encontrado com id: %s", id)
+	}
+	return cloneWebhook(w), nil
+}
+
+func (r *WebhookRepository) ListByTenant(ctx context.Context, tenantID string) ([]*entities.Webhook, error) {
+	return r.list(ctx, func(w *entities.Webhook) bool { return w.TenantID == tenantID })
+}
+
+func (r *WebhookRepository) Subscribers(ctx context.Context, tenantID string, event entities.EventType) ([]*entities.Webhook, error) {
+	return r.list(ctx, func(w *entities.Webhook) bool {
+		return w.TenantID == tenantID && w.Active && w.Subscribed(event)
+	})
+}
+
+// list retorna os webhooks visíveis que atendem a match, dos mais antigos para os mais novos
+func (r *WebhookRepository) list(ctx context.Context, match func(*entities.Webhook) bool) ([]*entities.Webhook, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var webhooks []*entities.Webhook
+	for _, w := range s.webhooks {
+		if visible(ctx, w.TenantID) && match(w) {
+			webhooks = append(webhooks, cloneWebhook(w))
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		a, b := webhooks[i], webhooks[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, webhook *entities.Webhook) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.webhooks[webhook.ID]
+	if !ok || w.TenantID != tenantID {
+		return fmt.Errorf("erro ao atualizar webhook: webhook não encontrado com id: %s", webhook.ID)
+	}
+	w.URL = webhook.URL
+	w.Events = append([]entities.EventType(nil), webhook.Events...)
+	w.Active = webhook.Active
+	w.UpdatedAt = now()
+	webhook.UpdatedAt = w.UpdatedAt
+	return nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.webhooks[id]
+	if !ok || w.TenantID != tenantID {
+		return fmt.Errorf("webhook não encontrado com id: %s", id)
+	}
+	s.deleteWebhook(id)
+	return nil
+}
+
+// deleteWebhook remove o webhook e as suas entregas
+func (s *Store) deleteWebhook(id string) {
+	for deliveryID, d := range s.deliveries {
+		if d.WebhookID == id {
+			delete(s.deliveries, deliveryID)
+		}
+	}
+	delete(s.webhooks, id)
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.webhooks[delivery.WebhookID]
+	if !ok || !visible(ctx, delivery.TenantID) {
+		return fmt.Errorf("erro ao registrar entrega: webhook não encontrado com id: %s", delivery.WebhookID)
+	}
+	delivery.TenantID = w.TenantID
+	delivery.ID = newID()
+	delivery.CreatedAt = now()
+	delivery.UpdatedAt = delivery.CreatedAt
+	s.deliveries[delivery.ID] = cloneDelivery(delivery)
+	return nil
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id string) (*entities.WebhookDelivery, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d, ok := s.deliveries[id]
+	if !ok || d.TenantID != tenantID {
+		return nil, fmt.Errorf("erro ao buscar entrega: entrega não encontrada com id: %s", id)
+	}
+	return cloneDelivery(d), nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID string, page entities.Page) (*entities.PageResult[*entities.WebhookDelivery], error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := page.NormalizeKeyset(); err != nil {
+		return nil, err
+	}
+
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var deliveries []*entities.WebhookDelivery
+	for _, d := range s.deliveries {
+		if d.WebhookID == webhookID && d.TenantID == tenantID {
+			deliveries = append(deliveries, cloneDelivery(d))
+		}
+	}
+
+	return paginate(page, true, entities.SortDesc, deliveries,
+		func(a, b *entities.WebhookDelivery) int { return compareTime(a.CreatedAt, b.CreatedAt) },
+		deliveryKey)
+}
+
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*entities.WebhookDelivery
+	for _, d := range s.deliveries {
+		if d.Status == entities.DeliveryPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		a, b := due[i], due[j]
+		if !a.NextAttemptAt.Equal(*b.NextAttemptAt) {
+			return a.NextAttemptAt.Before(*b.NextAttemptAt)
+		}
+		return a.ID < b.ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*entities.WebhookDelivery, len(due))
+	for i, d := range due {
+		next := now.Add(lease)
+		d.NextAttemptAt = &next
+		d.UpdatedAt = now
+		claimed[i] = cloneDelivery(d)
+	}
+	return claimed, nil
+}
+
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.deliveries[delivery.ID]
+	if !ok {
+		return fmt.Errorf("erro ao registrar tentativa de entrega: entrega não encontrada com id: %s", delivery.ID)
+	}
+	d.Status = delivery.Status
+	d.Attempts = delivery.Attempts
+	d.NextAttemptAt = cloneTime(delivery.NextAttemptAt)
+	d.LastAttemptAt = cloneTime(delivery.LastAttemptAt)
+	d.ResponseStatus = delivery.ResponseStatus
+	d.LastError = delivery.LastError
+	d.UpdatedAt = now()
+	delivery.UpdatedAt = d.UpdatedAt
+	return nil
+}
+
+// deliveryKey chave de paginação de uma entrega
+func deliveryKey(d *entities.WebhookDelivery) (time.Time, string) {
+	return d.CreatedAt, d.ID
+}
+
+// cloneWebhook copia o webhook para que o chamador não altere o armazenamento
+func cloneWebhook(w *entities.Webhook) *entities.Webhook {
+	webhook := *w
+	webhook.Events = append([]entities.EventType(nil), w.Events...)
+	return &webhook
+}
+
+// cloneDelivery copia a entrega para que o chamador não altere o armazenamento
+func cloneDelivery(d *entities.WebhookDelivery) *entities.WebhookDelivery {
+	delivery := *d
+	delivery.Payload = append([]byte(nil), d.Payload...)
+	delivery.NextAttemptAt = cloneTime(d.NextAttemptAt)
+	delivery.LastAttemptAt = cloneTime(d.LastAttemptAt)
+	return &delivery
+}
+
+// cloneTime copia uma data opcional
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
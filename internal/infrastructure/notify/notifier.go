@@ -0,0 +1,43 @@
+// Package notify envia os lembretes de tarefas pelos canais configurados
+// (email via SMTP, webhook HTTP).
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// Notifier canal de envio de lembretes
+type Notifier interface {
+	// Name identifica o canal no registro de envios; deve ser estável entre versões
+	Name() string
+	Notify(ctx context.Context, reminder *entities.Reminder) error
+}
+
+// subject assunto do lembrete
+func subject(r *entities.Reminder) string {
+	if r.Kind == entities.ReminderOverdue {
+		return "Tarefa atrasada: " + r.Task.Description
+	}
+	return "Tarefa com prazo próximo: " + r.Task.Description
+}
+
+// body texto do lembrete
+func body(r *entities.Reminder) string {
+	var b strings.Builder
+	if r.Kind == entities.ReminderOverdue {
+		fmt.Fprintf(&b, "O prazo da tarefa abaixo venceu em %s.\n\n", r.Task.DueDate.Format("02/01/2006 15:04 MST"))
+	} else {
+		fmt.Fprintf(&b, "O prazo da tarefa abaixo vence em %s.\n\n", r.Task.DueDate.Format("02/01/2006 15:04 MST"))
+	}
+	fmt.Fprintf(&b, "Tarefa: %s\n", r.Task.Description)
+	fmt.Fprintf(&b, "Prioridade: %s\n", r.Task.Priority)
+	fmt.Fprintf(&b, "Status: %s\n", r.Task.Status)
+	if r.EmailSubject != "" {
+		fmt.Fprintf(&b, "Email de origem: %s\n", r.EmailSubject)
+	}
+	return b.String()
+}
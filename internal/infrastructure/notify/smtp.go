@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// SMTPConfig servidor usado para enviar os lembretes por email
+type SMTPConfig struct {
+	Addr     string // host:porta
+	From     string
+	Username string // Autenticação PLAIN, opcional
+	Password string
+}
+
+// SMTPNotifier envia lembretes por email ao destinatário da tarefa
+type SMTPNotifier struct {
+	config SMTPConfig
+}
+
+// NewSMTPNotifier cria o canal de email
+func NewSMTPNotifier(config SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{config: config}
+}
+
+func (n *SMTPNotifier) Name() string {
+	return "email"
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, r *entities.Reminder) error {
+	if r.Recipient == "" {
+		return fmt.Errorf("lembrete da tarefa %s sem destinatário", r.Task.ID)
+	}
+
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		host, _, _ := net.SplitHostPort(n.config.Addr)
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, host)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", r.Recipient)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject(r)))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.Write(bytes.ReplaceAll([]byte(body(r)), []byte("\n"), []byte("\r\n")))
+
+	if err := smtp.SendMail(n.config.Addr, auth, n.config.From, []string{r.Recipient}, msg.Bytes()); err != nil {
+		return fmt.Errorf("erro ao enviar lembrete por email: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// WebhookNotifier envia os lembretes como JSON para uma URL
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier cria o canal de webhook
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// webhookPayload corpo enviado ao webhook
+type webhookPayload struct {
+	Event    string             `json:"event"`
+	Reminder *entities.Reminder `json:"reminder"`
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, r *entities.Reminder) error {
+	payload, err := json.Marshal(webhookPayload{Event: "task." + string(r.Kind), Reminder: r})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao enviar lembrete ao webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook de lembretes respondeu %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Package pop3 implementa um cliente POP3 mínimo (RFC 1939) com suporte a UIDL.
+package pop3
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrServer erro retornado pelo servidor (-ERR)
+var ErrServer = errors.New("pop3: erro do servidor")
+
+// Message identifica uma mensagem na caixa pelo número da sessão e pelo UIDL
+type Message struct {
+	Number int
+	UID    string
+}
+
+// Client cliente POP3
+type Client struct {
+	conn net.Conn
+	text *textproto.Conn
+}
+
+// Dial conecta ao servidor POP3, opcionalmente via TLS implícito (porta 995)
+func Dial(addr string, useTLS bool, timeout time.Duration) (*Client, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	var err error
+	if useTLS {
+		host, _, _ := net.SplitHostPort(addr)
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(conn)
+}
+
+// NewClient cria um cliente sobre uma conexão já estabelecida e lê a saudação
+func NewClient(conn net.Conn) (*Client, error) {
+	c := &Client{conn: conn, text: textproto.NewConn(conn)}
+	if _, err := c.readResponse(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Auth autentica com USER/PASS
+func (c *Client) Auth(username, password string) error {
+	if _, err := c.cmd("USER %s", username); err != nil {
+		return err
+	}
+	_, err := c.cmd("PASS %s", password)
+	return err
+}
+
+// Uidl lista as mensagens com seus identificadores únicos
+func (c *Client) Uidl() ([]Message, error) {
+	if _, err := c.cmd("UIDL"); err != nil {
+		return nil, err
+	}
+
+	lines, err := c.text.ReadDotLines()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("pop3: resposta UIDL inválida: %q", line)
+		}
+		n, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("pop3: número de mensagem inválido: %q", line)
+		}
+		messages = append(messages, Message{Number: n, UID: fields[1]})
+	}
+
+	return messages, nil
+}
+
+// Retr baixa o conteúdo completo da mensagem. O leitor retornado precisa ser
+// consumido até o fim antes do próximo comando.
+func (c *Client) Retr(number int) (io.Reader, error) {
+	if _, err := c.cmd("RETR %d", number); err != nil {
+		return nil, err
+	}
+	return c.text.DotReader(), nil
+}
+
+// Dele marca a mensagem para remoção ao final da sessão
+func (c *Client) Dele(number int) error {
+	_, err := c.cmd("DELE %d", number)
+	return err
+}
+
+// Noop verifica se a conexão continua ativa
+func (c *Client) Noop() error {
+	_, err := c.cmd("NOOP")
+	return err
+}
+
+// Quit encerra a sessão, efetivando as remoções, e fecha a conexão
+func (c *Client) Quit() error {
+	_, err := c.cmd("QUIT")
+	if closeErr := c.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Close fecha a conexão sem efetivar remoções pendentes
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) cmd(format string, args ...interface{}) (string, error) {
+	if err := c.text.PrintfLine(format, args...); err != nil {
+		return "", err
+	}
+	return c.readResponse()
+}
+
+func (c *Client) readResponse() (string, error) {
+	line, err := c.text.ReadLine()
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case strings.HasPrefix(line, "+OK"):
+		return strings.TrimSpace(strings.TrimPrefix(line, "+OK")), nil
+	case strings.HasPrefix(line, "-ERR"):
+		return "", fmt.Errorf("%w: %s", ErrServer, strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+	default:
+		return "", fmt.Errorf("pop3: resposta inesperada: %q", line)
+	}
+}
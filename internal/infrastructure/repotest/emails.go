@@ -0,0 +1,334 @@
+package repotest
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+func testEmails(t *testing.T, r *Repositories) {
+	a := newFixture(t, r, "emails-a")
+	b := newFixture(t, r, "emails-b")
+
+	invoice := a.email("Fatura de março", "finance", entities.PriorityHigh, "financeiro", "urgente")
+	invoice.Tasks = []entities.Task{{
+		Description: "Pagar a fatura",
+		DueDate:     time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second),
+		Priority:    entities.PriorityHigh,
+		Status:      entities.TaskPending,
+	}}
+	a.createEmail(t, invoice)
+	meeting := a.createEmail(t, a.email("Reunião de equipe", "work", entities.PriorityMedium, "reuniões"))
+	newsletter := a.createEmail(t, a.email("Novidades da semana", "newsletter", entities.PriorityLow))
+	other := b.createEmail(t, b.email("Fatura do tenant B", "finance", entities.PriorityHigh, "financeiro"))
+
+	t.Run("deduplicação", func(t *testing.T) {
+		again := a.email("Fatura de março (reenvio)", "finance", entities.PriorityHigh)
+		again.MessageID = invoice.MessageID
+		created, err := r.Emails.Create(a.ctx, again)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created || again.ID != invoice.ID {
+			t.Errorf("mesmo Message-ID: created = %v, id = %s, esperado %s", created, again.ID, invoice.ID)
+		}
+
+		// Sem Message-ID, o hash do conteúdo identifica a mensagem
+		first := a.email("Sem Message-ID", "work", entities.PriorityLow)
+		first.MessageID = ""
+		a.createEmail(t, first)
+		second := a.email("Sem Message-ID", "work", entities.PriorityLow)
+		second.MessageID = ""
+		created, err = r.Emails.Create(a.ctx, second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created || second.ID != first.ID {
+			t.Errorf("mesmo conteúdo: created = %v, id = %s, esperado %s", created, second.ID, first.ID)
+		}
+
+		// O mesmo Message-ID em outro tenant é outra mensagem
+		copied := b.email("Cópia no tenant B", "finance", entities.PriorityHigh)
+		copied.MessageID = invoice.MessageID
+		b.createEmail(t, copied)
+
+		if err := r.Emails.Delete(a.ctx, first.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Emails.Delete(b.ctx, copied.ID); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("CreateAll", func(t *testing.T) {
+		c := newFixture(t, r, "emails-batch")
+		existing := c.createEmail(t, c.email("Já importado", "work", entities.PriorityLow))
+
+		again := c.email("Já importado (reenvio)", "work", entities.PriorityLow)
+		again.MessageID = existing.MessageID
+		first := c.email("Primeiro do lote", "work", entities.PriorityMedium)
+		first.Tasks = []entities.Task{{Description: "Responder", DueDate: time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+			Priority: entities.PriorityMedium, Status: entities.TaskPending}}
+		repeated := c.email("Primeiro do lote (cópia)", "work", entities.PriorityMedium)
+		repeated.MessageID = first.MessageID
+
+		created, err := r.Emails.CreateAll(c.ctx, []*entities.Email{again, first, repeated})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(created, []bool{false, true, false}) {
+			t.Errorf("created = %v, esperado [false true false]", created)
+		}
+		if again.ID != existing.ID || repeated.ID != first.ID {
+			t.Errorf("duplicatas com ids %s e %s, esperado %s e %s", again.ID, repeated.ID, existing.ID, first.ID)
+		}
+		if got, err := r.Emails.GetByID(c.ctx, first.ID); err != nil || len(got.Tasks) != 1 {
+			t.Errorf("email do lote = %+v, %v", got, err)
+		}
+
+		// Um email inválido descarta o lote inteiro
+		valid := c.email("Lote recusado", "work", entities.PriorityLow)
+		invalid := c.email("Sem dono", "work", entities.PriorityLow)
+		invalid.UserID = ""
+		if _, err := r.Emails.CreateAll(c.ctx, []*entities.Email{valid, invalid}); err == nil {
+			t.Fatal("lote com email sem dono aceito")
+		}
+		result, err := r.Emails.ListByTenant(c.ctx, c.tenant.ID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(result.Items, emailID); !sameSet(got, []string{existing.ID, first.ID}) {
+			t.Errorf("emails após o lote recusado = %v, esperado %v", got, []string{existing.ID, first.ID})
+		}
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		got, err := r.Emails.GetByID(a.ctx, invoice.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Subject != invoice.Subject || got.Priority != entities.PriorityHigh || got.Category != "finance" || got.Folder != "INBOX" {
+			t.Errorf("email lido = %+v", got)
+		}
+		if !sameSet(got.Labels, []string{"financeiro", "urgente"}) {
+			t.Errorf("labels = %v", got.Labels)
+		}
+		if len(got.Tasks) != 1 || got.Tasks[0].Description != "Pagar a fatura" || got.Tasks[0].ID == "" {
+			t.Errorf("tarefas = %+v", got.Tasks)
+		}
+
+		if _, err := r.Emails.GetByID(b.ctx, invoice.ID); err == nil {
+			t.Error("tenant B leu o email do tenant A")
+		}
+		if _, err := r.Emails.GetByID(context.Background(), invoice.ID); err == nil {
+			t.Error("GetByID sem tenant no contexto deveria falhar")
+		}
+	})
+
+	t.Run("filtros", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			filter entities.EmailFilter
+			want   []string
+		}{
+			{"sem filtros", entities.EmailFilter{}, []string{invoice.ID, meeting.ID, newsletter.ID}},
+			{"ids", entities.EmailFilter{IDs: []string{invoice.ID, newsletter.ID}}, []string{invoice.ID, newsletter.ID}},
+			{"várias categorias", entities.EmailFilter{Categories: []string{"finance", "work"}}, []string{invoice.ID, meeting.ID}},
+			{"prioridade", entities.EmailFilter{Priorities: []entities.Priority{entities.PriorityLow}}, []string{newsletter.ID}},
+			{"labels", entities.EmailFilter{Labels: []string{"urgente", "reuniões"}}, []string{invoice.ID, meeting.ID}},
+			{"pasta", entities.EmailFilter{Folders: []string{"Arquivo"}}, []string{}},
+			{"categoria e prioridade", entities.EmailFilter{
+				Categories: []string{"finance", "work"},
+				Priorities: []entities.Priority{entities.PriorityMedium},
+			}, []string{meeting.ID}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := r.Emails.ListByTenant(a.ctx, a.tenant.ID, &tt.filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := ids(result.Items, emailID); !sameSet(got, tt.want) {
+					t.Errorf("ListByTenant = %v, esperado %v", got, tt.want)
+				}
+			})
+		}
+
+		future := time.Now().Add(time.Hour)
+		result, err := r.Emails.ListByTenant(a.ctx, a.tenant.ID, &entities.EmailFilter{StartDate: &future})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Items) != 0 {
+			t.Errorf("StartDate no futuro retornou %d emails", len(result.Items))
+		}
+
+		if _, err := r.Emails.ListByTenant(a.ctx, a.tenant.ID, &entities.EmailFilter{Sort: "from_address"}); err == nil {
+			t.Error("ordenação desconhecida aceita")
+		}
+
+		// Outro tenant não enxerga os emails, mesmo pedindo pelo ID do tenant
+		result, err = r.Emails.ListByTenant(b.ctx, a.tenant.ID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Items) != 0 {
+			t.Errorf("tenant B listou %d emails do tenant A", len(result.Items))
+		}
+	})
+
+	t.Run("ListByUser", func(t *testing.T) {
+		result, err := r.Emails.ListByUser(a.ctx, a.user.ID, &entities.EmailFilter{Categories: []string{"work", "newsletter"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(result.Items, emailID); !sameSet(got, []string{meeting.ID, newsletter.ID}) {
+			t.Errorf("ListByUser = %v", got)
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		result, err := r.Emails.Search(a.ctx, a.tenant.ID, entities.ParseSearchQuery("fatura"), entities.Page{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(result.Items, func(r *entities.EmailSearchResult) string { return r.Email.ID }); !sameSet(got, []string{invoice.ID}) {
+			t.Errorf("Search(fatura) = %v, esperado apenas %s", got, invoice.ID)
+		}
+
+		result, err = r.Emails.Search(a.ctx, a.tenant.ID, entities.ParseSearchQuery("label:reuniões"), entities.Page{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(result.Items, func(r *entities.EmailSearchResult) string { return r.Email.ID }); !sameSet(got, []string{meeting.ID}) {
+			t.Errorf("Search(label:reuniões) = %v", got)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		meeting.Subject = "Reunião remarcada"
+		meeting.Priority = entities.PriorityHigh
+		meeting.Labels = []string{"reuniões", "agenda"}
+		if err := r.Emails.Update(a.ctx, meeting); err != nil {
+			t.Fatal(err)
+		}
+		got, err := r.Emails.GetByID(a.ctx, meeting.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Subject != "Reunião remarcada" || got.Priority != entities.PriorityHigh || !sameSet(got.Labels, meeting.Labels) {
+			t.Errorf("email após Update = %+v", got)
+		}
+
+		stolen := *other
+		stolen.Subject = "alterado pelo tenant A"
+		if err := r.Emails.Update(a.ctx, &stolen); err == nil {
+			t.Error("tenant A alterou o email do tenant B")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := r.Emails.Delete(a.ctx, other.ID); err == nil {
+			t.Error("tenant A removeu o email do tenant B")
+		}
+		if err := r.Emails.Delete(a.ctx, invoice.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Emails.GetByID(a.ctx, invoice.ID); err == nil {
+			t.Error("email removido ainda encontrado")
+		}
+		// As tarefas são removidas com o email
+		if _, err := r.Tasks.GetByID(a.ctx, invoice.Tasks[0].ID); err == nil {
+			t.Error("tarefa do email removido ainda encontrada")
+		}
+	})
+}
+
+func testEmailPagination(t *testing.T, r *Repositories) {
+	f := newFixture(t, r, "pagination")
+	priorities := []entities.Priority{entities.PriorityLow, entities.PriorityHigh, entities.PriorityMedium, entities.PriorityHigh, entities.PriorityLow}
+	var created []string
+	for i, p := range priorities {
+		email := f.createEmail(t, f.email("Paginação "+string(rune('A'+i)), "work", p))
+		created = append(created, email.ID)
+	}
+	newestFirst := slices.Clone(created)
+	slices.Reverse(newestFirst)
+
+	// Ordenação padrão: created_at decrescente, paginada por keyset
+	var seen []string
+	page := entities.Page{PageSize: 2, WithTotal: true}
+	var pages []*entities.PageResult[*entities.Email]
+	for i := 0; i < 5; i++ {
+		result, err := r.Emails.ListByTenant(f.ctx, f.tenant.ID, &entities.EmailFilter{Page: page})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Total == nil || *result.Total != 5 {
+			t.Errorf("total = %v, esperado 5", result.Total)
+		}
+		pages = append(pages, result)
+		seen = append(seen, ids(result.Items, emailID)...)
+		if result.NextCursor == "" {
+			break
+		}
+		page = entities.Page{PageSize: 2, After: result.NextCursor, WithTotal: true}
+	}
+	if !slices.Equal(seen, newestFirst) {
+		t.Fatalf("páginas = %v, esperado %v", seen, newestFirst)
+	}
+	if len(pages) != 3 || pages[0].PrevCursor != "" {
+		t.Errorf("%d páginas; primeira com PrevCursor %q", len(pages), pages[0].PrevCursor)
+	}
+
+	// Voltar a partir da segunda página retorna a primeira
+	back, err := r.Emails.ListByTenant(f.ctx, f.tenant.ID, &entities.EmailFilter{
+		Page: entities.Page{PageSize: 2, Before: pages[1].PrevCursor},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(back.Items, emailID); !slices.Equal(got, newestFirst[:2]) {
+		t.Errorf("página anterior = %v, esperado %v", got, newestFirst[:2])
+	}
+
+	// Ordem crescente
+	asc, err := r.Emails.ListByTenant(f.ctx, f.tenant.ID, &entities.EmailFilter{Order: entities.SortAsc, Page: entities.Page{PageSize: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(asc.Items, emailID); !slices.Equal(got, created[:3]) {
+		t.Errorf("ordem crescente = %v, esperado %v", got, created[:3])
+	}
+
+	// Ordenação por prioridade, paginada por deslocamento
+	var ranked []entities.Priority
+	page = entities.Page{PageSize: 2}
+	for i := 0; i < 5; i++ {
+		result, err := r.Emails.ListByTenant(f.ctx, f.tenant.ID, &entities.EmailFilter{Sort: entities.EmailSortPriority, Page: page})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range result.Items {
+			ranked = append(ranked, e.Priority)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		page = entities.Page{PageSize: 2, After: result.NextCursor}
+	}
+	want := []entities.Priority{entities.PriorityHigh, entities.PriorityHigh, entities.PriorityMedium, entities.PriorityLow, entities.PriorityLow}
+	if !slices.Equal(ranked, want) {
+		t.Errorf("ordenação por prioridade = %v, esperado %v", ranked, want)
+	}
+
+	// Cursor de outra ordenação é rejeitado
+	if _, err := r.Emails.ListByTenant(f.ctx, f.tenant.ID, &entities.EmailFilter{
+		Sort: entities.EmailSortPriority, Page: entities.Page{After: pages[0].NextCursor},
+	}); err == nil {
+		t.Error("cursor keyset aceito em ordenação por deslocamento")
+	}
+}
@@ -0,0 +1,108 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+func testBackfill(t *testing.T, r *Repositories) {
+	f := newFixture(t, r, "backfill")
+	until := time.Now().UTC().Truncate(time.Second)
+	since := until.AddDate(0, -3, 0)
+
+	job := &entities.BackfillJob{
+		TenantID: f.tenant.ID, UserID: f.user.ID, Folder: "INBOX",
+		Since: since, Until: until, Cursor: until, Status: entities.BackfillPending,
+	}
+	if err := r.Backfill.Create(f.ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	done := &entities.BackfillJob{
+		TenantID: f.tenant.ID, UserID: f.user.ID, Folder: "Arquivo",
+		Since: since, Until: until, Cursor: until, Status: entities.BackfillPending,
+	}
+	if err := r.Backfill.Create(f.ctx, done); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Backfill.UpdateStatus(f.ctx, done.ID, entities.BackfillCompleted); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.Backfill.GetByID(f.ctx, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Folder != "INBOX" || !got.Since.Equal(since) || !got.Cursor.Equal(until) || got.Status != entities.BackfillPending {
+		t.Errorf("job lido = %+v", got)
+	}
+
+	// UpdateProgress grava cursor e contadores sem alterar o status
+	if err := r.Backfill.UpdateStatus(f.ctx, job.ID, entities.BackfillRunning); err != nil {
+		t.Fatal(err)
+	}
+	job.Cursor = until.AddDate(0, 0, -7)
+	job.Scanned, job.Classified, job.Failed = 40, 38, 2
+	job.LastError = "mensagem ilegível"
+	job.Status = entities.BackfillCompleted
+	if err := r.Backfill.UpdateProgress(f.ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	got, err = r.Backfill.GetByID(f.ctx, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Cursor.Equal(job.Cursor) || got.Scanned != 40 || got.Classified != 38 || got.Failed != 2 ||
+		got.LastError != "mensagem ilegível" || got.Status != entities.BackfillRunning {
+		t.Errorf("job após UpdateProgress = %+v", got)
+	}
+
+	unfinished, err := r.Backfill.ListUnfinished(f.ctx, f.tenant.ID, f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unfinished) != 1 || unfinished[0].ID != job.ID {
+		t.Errorf("ListUnfinished = %v, esperado apenas %s", ids(unfinished, func(j *entities.BackfillJob) string { return j.ID }), job.ID)
+	}
+}
+
+func testFolderStates(t *testing.T, r *Repositories) {
+	ctx := context.Background()
+	account := "imap:" + uniqueSuffix() + "@mail.example.com"
+
+	state, err := r.FolderStates.GetFolderState(ctx, account, "INBOX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != nil {
+		t.Fatalf("estado de pasta nova = %+v, esperado nil", state)
+	}
+
+	save := func(folder string, validity, lastUID uint32) {
+		t.Helper()
+		err := r.FolderStates.SaveFolderState(ctx, &entities.FolderState{Account: account, Folder: folder, UIDValidity: validity, LastUID: lastUID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("INBOX", 7, 120)
+	save("INBOX", 7, 180)
+	save("Arquivo", 3, 10)
+
+	state, err = r.FolderStates.GetFolderState(ctx, account, "INBOX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state == nil || state.UIDValidity != 7 || state.LastUID != 180 {
+		t.Errorf("estado de INBOX = %+v", state)
+	}
+	state, err = r.FolderStates.GetFolderState(ctx, account, "Arquivo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state == nil || state.UIDValidity != 3 || state.LastUID != 10 {
+		t.Errorf("estado de Arquivo = %+v", state)
+	}
+}
@@ -0,0 +1,93 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+func testMeetings(t *testing.T, r *Repositories) {
+	a := newFixture(t, r, "meetings-a")
+	b := newFixture(t, r, "meetings-b")
+	start := time.Now().UTC().Add(72 * time.Hour).Truncate(time.Second)
+
+	// invite grava um convite da reunião com a tarefa de preparação e as demais tarefas
+	invite := func(f *fixture, sequence int, method entities.MeetingMethod, tasks ...string) *entities.Email {
+		t.Helper()
+		email := f.email("Planejamento", "meeting", entities.PriorityMedium)
+		email.Meeting = &entities.Meeting{
+			UID: "planejamento@example.com", Sequence: sequence, Method: method, Status: entities.MeetingConfirmed,
+			Summary: "Planejamento", Organizer: "org@example.com", Start: start, End: start.Add(time.Hour),
+			Attendees: []string{f.user.Email},
+		}
+		if method == entities.MeetingCancel {
+			email.Meeting.Status = entities.MeetingCancelled
+		}
+		for _, description := range tasks {
+			email.Tasks = append(email.Tasks, entities.Task{
+				Description: description, DueDate: start.Add(-time.Hour), Priority: entities.PriorityMedium, Status: entities.TaskPending,
+			})
+		}
+		return f.createEmail(t, email)
+	}
+	status := func(f *fixture, task entities.Task) entities.TaskStatus {
+		t.Helper()
+		got, err := r.Tasks.GetByID(f.ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got.Status
+	}
+	lastChange := func(f *fixture, task entities.Task) *entities.TaskStatusChange {
+		t.Helper()
+		history, err := r.Tasks.History(f.ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(history) == 0 {
+			return nil
+		}
+		return history[len(history)-1]
+	}
+
+	prep := entities.MeetingPrepTask + "Planejamento"
+	first := invite(a, 0, entities.MeetingRequest, prep, "Enviar pauta")
+	other := invite(b, 0, entities.MeetingRequest, prep)
+
+	// Convite reagendado: a tarefa de preparação anterior dá lugar à nova
+	rescheduled := invite(a, 1, entities.MeetingRequest, prep)
+	if got := status(a, first.Tasks[0]); got != entities.TaskCancelled {
+		t.Errorf("tarefa de preparação do convite anterior = %s, esperado cancelled", got)
+	}
+	if c := lastChange(a, first.Tasks[0]); c == nil || c.FromStatus != entities.TaskPending || c.ToStatus != entities.TaskCancelled || c.Note == "" {
+		t.Errorf("histórico da tarefa substituída = %+v", c)
+	}
+	if got := status(a, first.Tasks[1]); got != entities.TaskPending {
+		t.Errorf("outra tarefa do convite anterior = %s, esperado pending", got)
+	}
+	if got := status(a, rescheduled.Tasks[0]); got != entities.TaskPending {
+		t.Errorf("tarefa de preparação do novo convite = %s, esperado pending", got)
+	}
+
+	// Cancelamento: a reunião e a tarefa de preparação vigente são canceladas
+	invite(a, 2, entities.MeetingCancel)
+	if got := status(a, rescheduled.Tasks[0]); got != entities.TaskCancelled {
+		t.Errorf("tarefa de preparação após o cancelamento = %s, esperado cancelled", got)
+	}
+	if c := lastChange(a, rescheduled.Tasks[0]); c == nil || c.ToStatus != entities.TaskCancelled || c.Note == "" {
+		t.Errorf("histórico da tarefa cancelada = %+v", c)
+	}
+	got, err := r.Emails.GetByID(a.ctx, rescheduled.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Meeting == nil || !got.Meeting.Cancelled() {
+		t.Errorf("reunião após o cancelamento = %+v", got.Meeting)
+	}
+
+	// A mesma reunião em outro tenant não é afetada
+	if got := status(b, other.Tasks[0]); got != entities.TaskPending {
+		t.Errorf("tarefa do tenant B = %s, esperado pending", got)
+	}
+}
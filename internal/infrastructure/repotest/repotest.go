@@ -0,0 +1,173 @@
+// Package repotest contém a suíte de conformidade dos repositórios: as mesmas
+// verificações de escopo por tenant, filtros, paginação e cascatas executadas
+// contra as implementações em memória, SQLite e PostgreSQL.
+package repotest
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// Repositories implementações testadas pela suíte, sobre o mesmo armazenamento
+type Repositories struct {
+	Tenants      entities.TenantRepository
+	Users        entities.UserRepository
+	Emails       entities.EmailRepository
+	Tasks        entities.TaskRepository
+	Reminders    entities.ReminderRepository
+	Outbox       entities.OutboxRepository
+	Webhooks     entities.WebhookRepository
+	Integrations entities.IntegrationRepository
+	Backfill     entities.BackfillRepository
+	FolderStates entities.FolderStateRepository
+}
+
+// Run executa a suíte. newRepos é chamado a cada subteste; o armazenamento pode
+// ser compartilhado entre chamadas, já que cada subteste cria os próprios
+// tenants e as operações globais (lembretes, outbox, entregas) são conferidas
+// apenas para eles.
+func Run(t *testing.T, newRepos func(t *testing.T) *Repositories) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, r *Repositories)
+	}{
+		{"Tenants", testTenants},
+		{"Users", testUsers},
+		{"Emails", testEmails},
+		{"EmailPagination", testEmailPagination},
+		{"Tasks", testTasks},
+		{"Meetings", testMeetings},
+		{"Reminders", testReminders},
+		{"Outbox", testOutbox},
+		{"Webhooks", testWebhooks},
+		{"Integrations", testIntegrations},
+		{"Backfill", testBackfill},
+		{"FolderStates", testFolderStates},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepos(t))
+		})
+	}
+}
+
+// fixture tenant com um usuário e o contexto escopado por ele
+type fixture struct {
+	repos  *Repositories
+	tenant *entities.Tenant
+	user   *entities.User
+	ctx    context.Context
+}
+
+// newFixture cria um tenant com um usuário, removidos ao final do teste
+func newFixture(t *testing.T, r *Repositories, name string) *fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	tenant := &entities.Tenant{Name: name, Plan: "free", Active: true}
+	if err := r.Tenants.Create(ctx, tenant); err != nil {
+		t.Fatalf("criar tenant: %v", err)
+	}
+	t.Cleanup(func() {
+		// Testes de remoção já podem ter removido o tenant
+		if _, err := r.Tenants.GetByID(ctx, tenant.ID); err == nil {
+			if err := r.Tenants.Delete(ctx, tenant.ID); err != nil {
+				t.Errorf("remover tenant %s: %v", tenant.ID, err)
+			}
+		}
+	})
+
+	f := &fixture{repos: r, tenant: tenant, ctx: entities.WithTenant(ctx, tenant.ID)}
+	f.user = f.newUser(t, name)
+	return f
+}
+
+// newUser cria um usuário no tenant com email único
+func (f *fixture) newUser(t *testing.T, name string) *entities.User {
+	t.Helper()
+	user := &entities.User{
+		TenantID:     f.tenant.ID,
+		Email:        fmt.Sprintf("%s-%s@example.com", name, uniqueSuffix()),
+		Name:         name,
+		PasswordHash: "hash",
+		Active:       true,
+	}
+	if err := f.repos.Users.Create(f.ctx, user); err != nil {
+		t.Fatalf("criar usuário: %v", err)
+	}
+	return user
+}
+
+// email monta um email do usuário do fixture, ainda não gravado
+func (f *fixture) email(subject, category string, priority entities.Priority, labels ...string) *entities.Email {
+	return &entities.Email{
+		TenantID:    f.tenant.ID,
+		UserID:      f.user.ID,
+		MessageID:   fmt.Sprintf("%s@%s.example.com", uniqueSuffix(), f.tenant.ID),
+		Subject:     subject,
+		From:        "remetente@example.com",
+		To:          f.user.Email,
+		Content:     "Conteúdo: " + subject,
+		Folder:      "INBOX",
+		Priority:    priority,
+		Category:    category,
+		Labels:      labels,
+		ProcessedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// createEmail grava o email e falha o teste se ele já existia
+func (f *fixture) createEmail(t *testing.T, email *entities.Email) *entities.Email {
+	t.Helper()
+	created, err := f.repos.Emails.Create(f.ctx, email)
+	if err != nil {
+		t.Fatalf("criar email %q: %v", email.Subject, err)
+	}
+	if !created {
+		t.Fatalf("email %q considerado duplicado", email.Subject)
+	}
+	return email
+}
+
+// uniqueSuffix gera um sufixo para valores únicos entre execuções no mesmo banco
+func uniqueSuffix() string {
+	var b [6]byte
+	rand.Read(b[:])
+	return fmt.Sprintf("%x", b)
+}
+
+// ids retorna os IDs dos itens, na ordem
+func ids[T any](items []T, id func(T) string) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = id(item)
+	}
+	return out
+}
+
+func emailID(e *entities.Email) string { return e.ID }
+
+func taskID(t *entities.Task) string { return t.ID }
+
+// sameSet informa se as listas têm os mesmos elementos, em qualquer ordem
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := make(map[string]int)
+	for _, v := range a {
+		count[v]++
+	}
+	for _, v := range b {
+		count[v]--
+		if count[v] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,312 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+func testTasks(t *testing.T, r *Repositories) {
+	a := newFixture(t, r, "tasks-a")
+	b := newFixture(t, r, "tasks-b")
+	colleague := a.newUser(t, "tasks-colleague")
+	email := a.createEmail(t, a.email("Proposta comercial", "work", entities.PriorityMedium))
+	otherEmail := b.createEmail(t, b.email("Email do tenant B", "work", entities.PriorityLow))
+
+	due := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	review := &entities.Task{EmailID: email.ID, Description: "Revisar proposta", DueDate: due, Priority: entities.PriorityHigh, Status: entities.TaskPending}
+	if err := r.Tasks.Create(a.ctx, review); err != nil {
+		t.Fatal(err)
+	}
+	reply := &entities.Task{EmailID: email.ID, Description: "Responder cliente", DueDate: due.Add(time.Hour), Priority: entities.PriorityLow, Status: entities.TaskPending, AssigneeID: colleague.ID}
+	if err := r.Tasks.Create(a.ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Create valida email e responsável", func(t *testing.T) {
+		if err := r.Tasks.Create(a.ctx, &entities.Task{EmailID: otherEmail.ID, Description: "x", DueDate: due, Priority: entities.PriorityLow, Status: entities.TaskPending}); err == nil {
+			t.Error("tarefa criada em email de outro tenant")
+		}
+		if err := r.Tasks.Create(a.ctx, &entities.Task{EmailID: email.ID, Description: "x", DueDate: due, Priority: entities.PriorityLow, Status: entities.TaskPending, AssigneeID: b.user.ID}); err == nil {
+			t.Error("tarefa atribuída a usuário de outro tenant")
+		}
+	})
+
+	t.Run("GetByID e Update", func(t *testing.T) {
+		got, err := r.Tasks.GetByID(a.ctx, review.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Description != "Revisar proposta" || !got.DueDate.Equal(due) || got.Status != entities.TaskPending || got.EmailID != email.ID {
+			t.Errorf("tarefa lida = %+v", got)
+		}
+		if _, err := r.Tasks.GetByID(b.ctx, review.ID); err == nil {
+			t.Error("tenant B leu a tarefa do tenant A")
+		}
+
+		review.Description = "Revisar proposta v2"
+		review.AssigneeID = colleague.ID
+		if err := r.Tasks.Update(a.ctx, review); err != nil {
+			t.Fatal(err)
+		}
+		got, err = r.Tasks.GetByID(a.ctx, review.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Description != "Revisar proposta v2" || got.AssigneeID != colleague.ID {
+			t.Errorf("tarefa após Update = %+v", got)
+		}
+		if err := r.Tasks.Update(b.ctx, review); err == nil {
+			t.Error("tenant B alterou a tarefa do tenant A")
+		}
+	})
+
+	t.Run("Transition e History", func(t *testing.T) {
+		change, err := review.Transition(entities.TaskInProgress, nil, a.user.ID, "começando")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Tasks.Transition(a.ctx, review, change); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := r.Tasks.GetByID(a.ctx, review.ID); got == nil || got.Status != entities.TaskInProgress {
+			t.Errorf("status após Transition = %+v", got)
+		}
+
+		// Uma transição a partir do status antigo é uma alteração concorrente
+		stale := &entities.TaskStatusChange{FromStatus: entities.TaskPending, ToStatus: entities.TaskCompleted}
+		if err := r.Tasks.Transition(a.ctx, review, stale); !errors.Is(err, entities.ErrInvalidTransition) {
+			t.Errorf("transição concorrente: err = %v, esperado ErrInvalidTransition", err)
+		}
+
+		history, err := r.Tasks.History(a.ctx, review.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(history) != 1 || history[0].FromStatus != entities.TaskPending || history[0].ToStatus != entities.TaskInProgress ||
+			history[0].ActorID != a.user.ID || history[0].Note != "começando" {
+			t.Errorf("histórico = %+v", history)
+		}
+		if history, err := r.Tasks.History(b.ctx, review.ID); err == nil && len(history) != 0 {
+			t.Error("tenant B leu o histórico da tarefa do tenant A")
+		}
+	})
+
+	t.Run("listagens", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			filter entities.TaskFilter
+			want   []string
+		}{
+			{"todas", entities.TaskFilter{}, []string{review.ID, reply.ID}},
+			{"status", entities.TaskFilter{Statuses: []string{"pending"}}, []string{reply.ID}},
+			{"vários status", entities.TaskFilter{Statuses: []string{"pending", "in_progress"}}, []string{review.ID, reply.ID}},
+			{"prioridade", entities.TaskFilter{Priorities: []entities.Priority{entities.PriorityHigh}}, []string{review.ID}},
+			{"responsável", entities.TaskFilter{AssigneeID: colleague.ID, Statuses: []string{"pending"}}, []string{reply.ID}},
+			{"prazo", entities.TaskFilter{EndDate: &due}, []string{review.ID}},
+			{"usuário responsável", entities.TaskFilter{UserID: colleague.ID}, []string{review.ID, reply.ID}},
+			{"dono sem as tarefas atribuídas", entities.TaskFilter{UserID: a.user.ID}, nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := r.Tasks.ListByTenant(a.ctx, a.tenant.ID, &tt.filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := ids(result.Items, taskID); !sameSet(got, tt.want) {
+					t.Errorf("ListByTenant = %v, esperado %v", got, tt.want)
+				}
+			})
+		}
+
+		byEmail, err := r.Tasks.ListByEmail(a.ctx, email.ID, &entities.TaskFilter{Sort: entities.TaskSortDueDate, Order: entities.SortAsc})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(byEmail.Items, taskID); len(got) != 2 || got[0] != review.ID || got[1] != reply.ID {
+			t.Errorf("ListByEmail por prazo = %v", got)
+		}
+
+		// Em aberto pelas quais o usuário responde, do prazo mais próximo ao mais distante
+		pending, err := r.Tasks.ListPendingTasks(a.ctx, colleague.ID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(pending.Items, taskID); len(got) != 2 || got[0] != review.ID || got[1] != reply.ID {
+			t.Errorf("ListPendingTasks do responsável = %v, esperado [%s %s]", got, review.ID, reply.ID)
+		}
+		pending, err = r.Tasks.ListPendingTasks(a.ctx, a.user.ID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pending.Items) != 0 {
+			t.Errorf("ListPendingTasks do dono listou %d tarefas atribuídas a outro usuário", len(pending.Items))
+		}
+
+		other, err := r.Tasks.ListByTenant(b.ctx, a.tenant.ID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(other.Items) != 0 {
+			t.Errorf("tenant B listou %d tarefas do tenant A", len(other.Items))
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := r.Tasks.Delete(b.ctx, reply.ID); err == nil {
+			t.Error("tenant B removeu a tarefa do tenant A")
+		}
+		if err := r.Tasks.Delete(a.ctx, reply.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Tasks.GetByID(a.ctx, reply.ID); err == nil {
+			t.Error("tarefa removida ainda encontrada")
+		}
+	})
+}
+
+func testReminders(t *testing.T, r *Repositories) {
+	f := newFixture(t, r, "reminders")
+	assignee := f.newUser(t, "reminders-assignee")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	email := f.email("Prazos", "work", entities.PriorityMedium)
+	email.Tasks = []entities.Task{
+		{Description: "Vence em breve", DueDate: now.Add(30 * time.Minute), Priority: entities.PriorityMedium, Status: entities.TaskPending, AssigneeID: assignee.ID},
+		{Description: "Atrasada", DueDate: now.Add(-time.Hour), Priority: entities.PriorityHigh, Status: entities.TaskPending},
+		{Description: "Distante", DueDate: now.Add(72 * time.Hour), Priority: entities.PriorityLow, Status: entities.TaskPending},
+	}
+	f.createEmail(t, email)
+	soon, overdue := email.Tasks[0], email.Tasks[1]
+
+	due := func() map[string]*entities.Reminder {
+		t.Helper()
+		reminders, err := r.Reminders.Due(f.ctx, now, time.Hour, []string{"email"}, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := make(map[string]*entities.Reminder)
+		for _, rem := range reminders {
+			if rem.TenantID == f.tenant.ID {
+				found[rem.Task.ID] = rem
+			}
+		}
+		return found
+	}
+
+	reminders := due()
+	if len(reminders) != 2 || reminders[soon.ID] == nil || reminders[overdue.ID] == nil {
+		t.Fatalf("Due = %v, esperado as tarefas %s e %s", reminders, soon.ID, overdue.ID)
+	}
+	if rem := reminders[soon.ID]; rem.Kind != entities.ReminderDueSoon || rem.UserID != assignee.ID || rem.Recipient != assignee.Email || rem.EmailSubject != "Prazos" {
+		t.Errorf("lembrete com responsável = %+v", rem)
+	}
+	if rem := reminders[overdue.ID]; rem.Kind != entities.ReminderOverdue || rem.UserID != f.user.ID {
+		t.Errorf("lembrete sem responsável = %+v", rem)
+	}
+
+	// Claim registra o envio uma única vez; Release permite nova tentativa
+	rem := reminders[soon.ID]
+	if ok, err := r.Reminders.Claim(f.ctx, rem, "email"); err != nil || !ok {
+		t.Fatalf("Claim = %v, %v", ok, err)
+	}
+	if ok, err := r.Reminders.Claim(f.ctx, rem, "email"); err != nil || ok {
+		t.Errorf("segundo Claim = %v, %v", ok, err)
+	}
+	if _, ok := due()[soon.ID]; ok {
+		t.Error("lembrete registrado ainda listado em Due")
+	}
+	if err := r.Reminders.Release(f.ctx, rem, "email"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := due()[soon.ID]; !ok {
+		t.Error("lembrete liberado não voltou a Due")
+	}
+
+	// Tarefas concluídas não geram lembretes
+	task, err := r.Tasks.GetByID(f.ctx, overdue.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	change, err := task.Transition(entities.TaskCompleted, nil, f.user.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Tasks.Transition(f.ctx, task, change); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := due()[overdue.ID]; ok {
+		t.Error("tarefa concluída listada em Due")
+	}
+
+	// MarkOverdue marca apenas as tarefas abertas vencidas, uma única vez
+	late := f.email("Atrasada aberta", "work", entities.PriorityLow)
+	late.Tasks = []entities.Task{{Description: "Atrasada aberta", DueDate: now.Add(-time.Minute), Priority: entities.PriorityLow, Status: entities.TaskPending}}
+	f.createEmail(t, late)
+	if n, err := r.Reminders.MarkOverdue(f.ctx, now); err != nil || n < 1 {
+		t.Fatalf("MarkOverdue = %d, %v", n, err)
+	}
+	got, err := r.Tasks.GetByID(f.ctx, late.Tasks[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.OverdueAt == nil || !got.OverdueAt.Equal(now) {
+		t.Errorf("overdue_at = %v, esperado %v", got.OverdueAt, now)
+	}
+	if got, _ := r.Tasks.GetByID(f.ctx, soon.ID); got == nil || got.OverdueAt != nil {
+		t.Errorf("tarefa no prazo marcada como atrasada: %+v", got)
+	}
+	if got, _ := r.Tasks.GetByID(f.ctx, overdue.ID); got == nil || got.OverdueAt != nil {
+		t.Errorf("tarefa concluída marcada como atrasada: %+v", got)
+	}
+
+	// WakeSnoozed reabre apenas as tarefas com adiamento vencido e registra a mudança
+	snoozed := f.email("Adiadas", "work", entities.PriorityLow)
+	snoozed.Tasks = []entities.Task{
+		{Description: "Adiamento vencido", DueDate: now.Add(-time.Hour), Priority: entities.PriorityLow, Status: entities.TaskPending},
+		{Description: "Adiamento futuro", DueDate: now.Add(-time.Hour), Priority: entities.PriorityLow, Status: entities.TaskPending},
+	}
+	f.createEmail(t, snoozed)
+	for i, until := range []time.Time{now.Add(-time.Minute), now.Add(time.Hour)} {
+		task, err := r.Tasks.GetByID(f.ctx, snoozed.Tasks[i].ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		change, err := task.Transition(entities.TaskSnoozed, &until, f.user.ID, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Tasks.Transition(f.ctx, task, change); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expired, future := snoozed.Tasks[0].ID, snoozed.Tasks[1].ID
+	if _, ok := due()[expired]; ok {
+		t.Error("tarefa adiada listada em Due")
+	}
+
+	if n, err := r.Reminders.WakeSnoozed(f.ctx, now); err != nil || n < 1 {
+		t.Fatalf("WakeSnoozed = %d, %v", n, err)
+	}
+	if got, _ := r.Tasks.GetByID(f.ctx, expired); got == nil || got.Status != entities.TaskPending || got.SnoozedUntil != nil {
+		t.Errorf("tarefa com adiamento vencido = %+v, esperado pending", got)
+	}
+	if got, _ := r.Tasks.GetByID(f.ctx, future); got == nil || got.Status != entities.TaskSnoozed {
+		t.Errorf("tarefa com adiamento futuro = %+v, esperado snoozed", got)
+	}
+	history, err := r.Tasks.History(f.ctx, expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := history[len(history)-1]; last.FromStatus != entities.TaskSnoozed || last.ToStatus != entities.TaskPending || last.ActorID != "" || last.Note != entities.SnoozeExpiredNote {
+		t.Errorf("última mudança = %+v", last)
+	}
+	if _, ok := due()[expired]; !ok {
+		t.Error("tarefa reaberta não voltou a Due")
+	}
+	if n, err := r.Reminders.WakeSnoozed(f.ctx, now); err != nil || n != 0 {
+		t.Errorf("segundo WakeSnoozed = %d, %v", n, err)
+	}
+}
@@ -0,0 +1,146 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+func testTenants(t *testing.T, r *Repositories) {
+	ctx := context.Background()
+	f := newFixture(t, r, "tenants")
+
+	got, err := r.Tenants.GetByID(ctx, f.tenant.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "tenants" || got.Plan != "free" || !got.Active || got.CreatedAt.IsZero() {
+		t.Errorf("tenant lido = %+v", got)
+	}
+
+	f.tenant.Name, f.tenant.Plan = "renomeado", "pro"
+	if err := r.Tenants.Update(ctx, f.tenant); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := r.Tenants.GetByID(ctx, f.tenant.ID); got == nil || got.Name != "renomeado" || got.Plan != "pro" {
+		t.Errorf("tenant após Update = %+v", got)
+	}
+
+	tenants, err := r.Tenants.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, tenant := range tenants {
+		found = found || tenant.ID == f.tenant.ID
+	}
+	if !found {
+		t.Error("List não inclui o tenant criado")
+	}
+
+	// A remoção alcança usuários, emails, tarefas e jobs do tenant
+	email := f.createEmail(t, f.email("Remover tenant", "work", entities.PriorityLow, "rótulo"))
+	job := &entities.BackfillJob{
+		TenantID: f.tenant.ID, UserID: f.user.ID, Folder: "INBOX",
+		Since: time.Now().AddDate(0, -1, 0), Until: time.Now(), Cursor: time.Now(),
+		Status: entities.BackfillPending,
+	}
+	if err := r.Backfill.Create(f.ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Tenants.Delete(ctx, f.tenant.ID); err != nil {
+		t.Fatalf("remover tenant com dados: %v", err)
+	}
+	if _, err := r.Tenants.GetByID(ctx, f.tenant.ID); err == nil {
+		t.Error("tenant removido ainda encontrado")
+	}
+	if _, err := r.Users.GetByEmail(ctx, f.user.Email); err == nil {
+		t.Error("usuário do tenant removido ainda encontrado")
+	}
+	if _, err := r.Emails.GetByID(f.ctx, email.ID); err == nil {
+		t.Error("email do tenant removido ainda encontrado")
+	}
+	if err := r.Tenants.Delete(ctx, f.tenant.ID); err == nil {
+		t.Error("Delete de tenant inexistente deveria falhar")
+	}
+}
+
+func testUsers(t *testing.T, r *Repositories) {
+	a := newFixture(t, r, "users-a")
+	b := newFixture(t, r, "users-b")
+
+	if a.user.ID == "" || a.user.Role != "user" || a.user.CreatedAt.IsZero() {
+		t.Errorf("usuário criado = %+v", a.user)
+	}
+
+	got, err := r.Users.GetByID(a.ctx, a.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Email != a.user.Email || got.TenantID != a.tenant.ID || got.PasswordHash != "hash" {
+		t.Errorf("usuário lido = %+v", got)
+	}
+	if _, err := r.Users.GetByID(b.ctx, a.user.ID); err == nil {
+		t.Error("tenant B leu o usuário do tenant A")
+	}
+
+	// GetByEmail é usado no login, sem tenant no contexto
+	got, err = r.Users.GetByEmail(context.Background(), a.user.Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != a.user.ID {
+		t.Errorf("GetByEmail retornou %s, esperado %s", got.ID, a.user.ID)
+	}
+
+	duplicate := &entities.User{TenantID: a.tenant.ID, Email: a.user.Email, Name: "dup", PasswordHash: "x"}
+	if err := r.Users.Create(a.ctx, duplicate); err == nil {
+		t.Error("email de usuário duplicado aceito")
+	}
+
+	admin := a.newUser(t, "users-admin")
+	admin.Name, admin.Role, admin.Active = "Admin", "admin", false
+	if err := r.Users.Update(a.ctx, admin); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := r.Users.GetByID(a.ctx, admin.ID); got == nil || got.Name != "Admin" || got.Role != "admin" || got.Active {
+		t.Errorf("usuário após Update = %+v", got)
+	}
+
+	// RotateFeedToken incrementa a versão sem alterar os demais campos
+	for want := 1; want <= 2; want++ {
+		version, err := r.Users.RotateFeedToken(a.ctx, admin.ID)
+		if err != nil || version != want {
+			t.Fatalf("RotateFeedToken = %d, %v; esperado %d", version, err, want)
+		}
+	}
+	if got, _ := r.Users.GetByID(a.ctx, admin.ID); got == nil || got.FeedTokenVersion != 2 || got.Name != "Admin" {
+		t.Errorf("usuário após RotateFeedToken = %+v", got)
+	}
+	if _, err := r.Users.RotateFeedToken(b.ctx, admin.ID); err == nil {
+		t.Error("tenant B revogou o feed do usuário do tenant A")
+	}
+
+	users, err := r.Users.ListByTenant(a.ctx, a.tenant.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(users, func(u *entities.User) string { return u.ID }); !sameSet(got, []string{a.user.ID, admin.ID}) {
+		t.Errorf("ListByTenant = %v", got)
+	}
+	if users, err := r.Users.ListByTenant(b.ctx, a.tenant.ID); err != nil || len(users) != 0 {
+		t.Errorf("tenant B listou %d usuários do tenant A (err %v)", len(users), err)
+	}
+
+	if err := r.Users.Delete(b.ctx, admin.ID); err == nil {
+		t.Error("tenant B removeu o usuário do tenant A")
+	}
+	if err := r.Users.Delete(a.ctx, admin.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Users.GetByID(a.ctx, admin.ID); err == nil {
+		t.Error("usuário removido ainda encontrado")
+	}
+}
@@ -0,0 +1,331 @@
+package repotest
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+func testOutbox(t *testing.T, r *Repositories) {
+	f := newFixture(t, r, "outbox")
+	email := f.email("Evento no outbox", "work", entities.PriorityHigh)
+	email.Tasks = []entities.Task{{Description: "Tarefa do evento", DueDate: time.Now().Add(time.Hour), Priority: entities.PriorityHigh, Status: entities.TaskPending}}
+	f.createEmail(t, email)
+
+	// Sem eventos: cargas históricas não notificam as integrações
+	quiet := f.email("Sem eventos", "work", entities.PriorityHigh)
+	if _, err := r.Emails.Create(entities.WithoutEvents(f.ctx), quiet); err != nil {
+		t.Fatal(err)
+	}
+
+	t0 := time.Now().UTC()
+	lease := time.Minute
+	claim := func(now time.Time) []*entities.OutboxEntry {
+		t.Helper()
+		entries, err := r.Outbox.ClaimDue(f.ctx, now, lease, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var own []*entities.OutboxEntry
+		for _, e := range entries {
+			if e.Event.TenantID == f.tenant.ID {
+				own = append(own, e)
+			}
+		}
+		return own
+	}
+
+	entries := claim(t0)
+	var types []entities.EventType
+	for _, e := range entries {
+		types = append(types, e.Event.Type)
+	}
+	want := []entities.EventType{entities.EventEmailClassified, entities.EventEmailHighPriority, entities.EventTaskCreated}
+	if !slices.Equal(types, want) {
+		t.Fatalf("eventos = %v, esperado %v em ordem de ID", types, want)
+	}
+	if !slices.IsSortedFunc(entries, func(a, b *entities.OutboxEntry) int { return int(a.ID - b.ID) }) {
+		t.Error("entradas fora da ordem de ID")
+	}
+
+	// As entradas reservadas ficam com o tenant até o fim do prazo
+	if again := claim(t0); len(again) != 0 {
+		t.Errorf("%d entradas reservadas de novo dentro do prazo", len(again))
+	}
+
+	// Falha em um sink: os sinks concluídos e a próxima tentativa são gravados
+	first := entries[0]
+	first.Delivered = []string{"webhooks"}
+	first.Attempts = 1
+	first.LastError = "sink indisponível"
+	first.NextAttemptAt = t0.Add(5 * time.Minute)
+	if err := r.Outbox.RecordFailure(f.ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries[1:] {
+		if err := r.Outbox.Complete(f.ctx, e.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if early := claim(t0.Add(2 * lease)); len(early) != 0 {
+		t.Errorf("entrada reservada antes da próxima tentativa: %d", len(early))
+	}
+	retried := claim(t0.Add(10 * time.Minute))
+	if len(retried) != 1 || retried[0].ID != first.ID {
+		t.Fatalf("nova tentativa = %+v, esperado a entrada %d", retried, first.ID)
+	}
+	if got := retried[0]; got.Attempts != 1 || got.LastError != "sink indisponível" || got.Pending("webhooks") || !got.Pending("integrations") {
+		t.Errorf("entrada após falha = %+v", got)
+	}
+
+	// Tentativas esgotadas: a entrada estacionada sai da fila e não bloqueia
+	// as seguintes do tenant
+	later := f.createEmail(t, f.email("Evento posterior", "work", entities.PriorityLow))
+	parked := retried[0]
+	parkedAt := t0.Add(10 * time.Minute)
+	parked.Attempts = 20
+	parked.NextAttemptAt = parkedAt
+	parked.ParkedAt = &parkedAt
+	if err := r.Outbox.RecordFailure(f.ctx, parked); err != nil {
+		t.Fatal(err)
+	}
+	next := claim(t0.Add(time.Hour))
+	if len(next) != 1 || next[0].ID == parked.ID || next[0].ParkedAt != nil || !strings.Contains(string(next[0].Event.Data), later.ID) {
+		t.Fatalf("entradas após estacionar = %+v, esperado apenas o evento do email %s", next, later.ID)
+	}
+	if err := r.Outbox.Complete(f.ctx, next[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if rest := claim(t0.Add(2 * time.Hour)); len(rest) != 0 {
+		t.Errorf("%d entradas após Complete, incluindo a estacionada", len(rest))
+	}
+}
+
+func testWebhooks(t *testing.T, r *Repositories) {
+	a := newFixture(t, r, "webhooks-a")
+	b := newFixture(t, r, "webhooks-b")
+
+	hook := &entities.Webhook{
+		TenantID: a.tenant.ID,
+		URL:      "https://hooks.example.com/a",
+		Secret:   "segredo",
+		Events:   []entities.EventType{entities.EventEmailClassified, entities.EventTaskCreated},
+		Active:   true,
+	}
+	if err := r.Webhooks.Create(a.ctx, hook); err != nil {
+		t.Fatal(err)
+	}
+	other := &entities.Webhook{TenantID: b.tenant.ID, URL: "https://hooks.example.com/b", Secret: "b", Events: []entities.EventType{entities.EventEmailClassified}, Active: true}
+	if err := r.Webhooks.Create(b.ctx, other); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.Webhooks.GetByID(a.ctx, hook.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.URL != hook.URL || got.Secret != "segredo" || !slices.Equal(got.Events, hook.Events) || !got.Active {
+		t.Errorf("webhook lido = %+v", got)
+	}
+	if _, err := r.Webhooks.GetByID(b.ctx, hook.ID); err == nil {
+		t.Error("tenant B leu o webhook do tenant A")
+	}
+	if list, err := r.Webhooks.ListByTenant(a.ctx, a.tenant.ID); err != nil || len(list) != 1 || list[0].ID != hook.ID {
+		t.Errorf("ListByTenant = %v, %v", list, err)
+	}
+
+	subscribers := func(event entities.EventType) []string {
+		t.Helper()
+		list, err := r.Webhooks.Subscribers(a.ctx, a.tenant.ID, event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ids(list, func(w *entities.Webhook) string { return w.ID })
+	}
+	if got := subscribers(entities.EventTaskCreated); !slices.Equal(got, []string{hook.ID}) {
+		t.Errorf("Subscribers(task.created) = %v", got)
+	}
+	if got := subscribers(entities.EventTaskOverdue); len(got) != 0 {
+		t.Errorf("Subscribers(task.overdue) = %v", got)
+	}
+
+	hook.Active = false
+	hook.URL = "https://hooks.example.com/a2"
+	if err := r.Webhooks.Update(a.ctx, hook); err != nil {
+		t.Fatal(err)
+	}
+	if got := subscribers(entities.EventTaskCreated); len(got) != 0 {
+		t.Errorf("webhook inativo listado em Subscribers: %v", got)
+	}
+	if err := r.Webhooks.Update(b.ctx, hook); err == nil {
+		t.Error("tenant B alterou o webhook do tenant A")
+	}
+
+	// Entregas
+	payload := json.RawMessage(`{"id":"evt-1","type":"email.classified"}`)
+	now := time.Now().UTC().Truncate(time.Second)
+	var deliveries []*entities.WebhookDelivery
+	for i := 0; i < 3; i++ {
+		d := &entities.WebhookDelivery{
+			WebhookID: hook.ID, TenantID: a.tenant.ID, EventID: "evt-1", Event: entities.EventEmailClassified,
+			Payload: payload, Status: entities.DeliveryPending, NextAttemptAt: &now,
+		}
+		if err := r.Webhooks.CreateDelivery(a.ctx, d); err != nil {
+			t.Fatal(err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	d, err := r.Webhooks.GetDelivery(a.ctx, deliveries[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotPayload, wantPayload interface{}
+	json.Unmarshal(d.Payload, &gotPayload)
+	json.Unmarshal(payload, &wantPayload)
+	if d.WebhookID != hook.ID || d.Status != entities.DeliveryPending || !reflect.DeepEqual(gotPayload, wantPayload) {
+		t.Errorf("entrega lida = %+v", d)
+	}
+	if _, err := r.Webhooks.GetDelivery(b.ctx, deliveries[0].ID); err == nil {
+		t.Error("tenant B leu a entrega do tenant A")
+	}
+
+	// Mais recentes primeiro, com o id como desempate para entregas criadas no mesmo instante
+	newest := slices.Clone(deliveries)
+	slices.SortFunc(newest, func(x, y *entities.WebhookDelivery) int {
+		if c := y.CreatedAt.Compare(x.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(y.ID, x.ID)
+	})
+	page, err := r.Webhooks.ListDeliveries(a.ctx, hook.ID, entities.Page{PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(page.Items, func(d *entities.WebhookDelivery) string { return d.ID }); !slices.Equal(got, []string{newest[0].ID, newest[1].ID}) || page.NextCursor == "" {
+		t.Errorf("ListDeliveries = %v (próxima %q)", got, page.NextCursor)
+	}
+
+	// ClaimDue adia as entregas reservadas; RecordAttempt grava o resultado
+	claimed, err := r.Webhooks.ClaimDue(a.ctx, now, time.Minute, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var own []*entities.WebhookDelivery
+	for _, d := range claimed {
+		if d.TenantID == a.tenant.ID {
+			own = append(own, d)
+		}
+	}
+	if len(own) != 3 {
+		t.Fatalf("ClaimDue reservou %d entregas do tenant, esperado 3", len(own))
+	}
+	if again, _ := r.Webhooks.ClaimDue(a.ctx, now, time.Minute, 1000); slices.ContainsFunc(again, func(d *entities.WebhookDelivery) bool { return d.TenantID == a.tenant.ID }) {
+		t.Error("entrega reservada de novo dentro do prazo")
+	}
+
+	attempt := own[0]
+	attempt.Status = entities.DeliverySucceeded
+	attempt.Attempts = 1
+	attempt.NextAttemptAt = nil
+	attempt.LastAttemptAt = &now
+	attempt.ResponseStatus = 204
+	if err := r.Webhooks.RecordAttempt(a.ctx, attempt); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := r.Webhooks.GetDelivery(a.ctx, attempt.ID); err != nil || got.Status != entities.DeliverySucceeded || got.ResponseStatus != 204 || got.NextAttemptAt != nil {
+		t.Errorf("entrega após RecordAttempt = %+v, %v", got, err)
+	}
+
+	// A remoção do webhook remove as entregas
+	if err := r.Webhooks.Delete(b.ctx, hook.ID); err == nil {
+		t.Error("tenant B removeu o webhook do tenant A")
+	}
+	if err := r.Webhooks.Delete(a.ctx, hook.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Webhooks.GetDelivery(a.ctx, deliveries[0].ID); err == nil {
+		t.Error("entrega do webhook removido ainda encontrada")
+	}
+}
+
+func testIntegrations(t *testing.T, r *Repositories) {
+	a := newFixture(t, r, "integrations-a")
+	b := newFixture(t, r, "integrations-b")
+
+	slack := &entities.Integration{
+		TenantID:   a.tenant.ID,
+		Kind:       entities.IntegrationSlack,
+		Name:       "Alertas",
+		WebhookURL: "https://hooks.slack.com/services/T000/B000/XXX",
+		Criteria:   entities.IntegrationCriteria{Priorities: []entities.Priority{entities.PriorityHigh}},
+		Active:     true,
+	}
+	teams := &entities.Integration{
+		TenantID:   a.tenant.ID,
+		Kind:       entities.IntegrationTeams,
+		Name:       "Suporte",
+		WebhookURL: "https://example.webhook.office.com/webhookb2/x",
+		Criteria:   entities.IntegrationCriteria{Categories: []string{"suporte"}},
+		Active:     false,
+	}
+	for _, i := range []*entities.Integration{slack, teams} {
+		if err := r.Integrations.Create(a.ctx, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := r.Integrations.GetByID(a.ctx, slack.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Kind != entities.IntegrationSlack || got.Name != "Alertas" || got.WebhookURL != slack.WebhookURL ||
+		!slices.Equal(got.Criteria.Priorities, slack.Criteria.Priorities) || len(got.Criteria.Categories) != 0 {
+		t.Errorf("integração lida = %+v", got)
+	}
+	if _, err := r.Integrations.GetByID(b.ctx, slack.ID); err == nil {
+		t.Error("tenant B leu a integração do tenant A")
+	}
+
+	list, err := r.Integrations.ListByTenant(a.ctx, a.tenant.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(list, func(i *entities.Integration) string { return i.ID }); !sameSet(got, []string{slack.ID, teams.ID}) {
+		t.Errorf("ListByTenant = %v", got)
+	}
+	active, err := r.Integrations.ListActive(a.ctx, a.tenant.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(active, func(i *entities.Integration) string { return i.ID }); !slices.Equal(got, []string{slack.ID}) {
+		t.Errorf("ListActive = %v", got)
+	}
+
+	teams.Active = true
+	teams.Criteria = entities.IntegrationCriteria{Categories: []string{"suporte", "vendas"}}
+	if err := r.Integrations.Update(a.ctx, teams); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := r.Integrations.GetByID(a.ctx, teams.ID); err != nil || !got.Active || !slices.Equal(got.Criteria.Categories, []string{"suporte", "vendas"}) {
+		t.Errorf("integração após Update = %+v, %v", got, err)
+	}
+	if err := r.Integrations.Update(b.ctx, teams); err == nil {
+		t.Error("tenant B alterou a integração do tenant A")
+	}
+
+	if err := r.Integrations.Delete(b.ctx, slack.ID); err == nil {
+		t.Error("tenant B removeu a integração do tenant A")
+	}
+	if err := r.Integrations.Delete(a.ctx, slack.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Integrations.GetByID(a.ctx, slack.ID); err == nil {
+		t.Error("integração removida ainda encontrada")
+	}
+}
@@ -0,0 +1,111 @@
+// Package safehttp protege as requisições feitas às URLs cadastradas pelos
+// tenants (webhooks e integrações) contra SSRF: destinos na rede interna, em
+// loopback ou link-local, como o serviço de metadados da nuvem, são recusados
+// no cadastro e de novo no momento da conexão, já com o endereço resolvido.
+package safehttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress indica destino fora da internet pública
+var ErrForbiddenAddress = errors.New("destino em rede interna não permitido")
+
+// blockedNetworks faixas reservadas não cobertas pelos métodos de net.IP
+var blockedNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // "Esta rede"
+	mustParseCIDR("100.64.0.0/10"), // NAT de operadora, usada por metadados de alguns provedores
+	mustParseCIDR("192.0.0.0/24"),  // Atribuições de protocolo da IETF
+	mustParseCIDR("198.18.0.0/15"), // Testes de desempenho
+	mustParseCIDR("240.0.0.0/4"),   // Reservada, inclui broadcast
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// Allowed indica se o endereço pode ser destino das requisições
+func Allowed(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsMulticast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, n := range blockedNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL confere se a URL é http ou https e se o host resolve apenas para
+// endereços permitidos
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("url deve ser um endereço http ou https")
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !Allowed(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("não foi possível resolver o host %s", host)
+	}
+	for _, addr := range addrs {
+		if !Allowed(addr.IP) {
+			return fmt.Errorf("%w: %s resolve para %s", ErrForbiddenAddress, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// control recusa a conexão a endereços não permitidos. Roda depois da
+// resolução de nomes, então também cobre hosts que passaram a apontar para a
+// rede interna depois do cadastro e redirecionamentos.
+func control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !Allowed(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// NewClient cria um http.Client que só conecta a endereços permitidos. Proxies
+// do ambiente são ignorados, já que a conexão com eles escaparia da verificação.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second, Control: control}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
@@ -0,0 +1,66 @@
+package safehttp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.5", false},
+		{"172.16.3.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00:ec2::254", false},
+		{"100.100.100.200", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+	}
+	for _, tt := range tests {
+		if got := Allowed(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("Allowed(%s) = %v, esperado %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		url       string
+		forbidden bool
+		invalid   bool
+	}{
+		{url: "https://93.184.216.34/hook"},
+		{url: "http://169.254.169.254/latest/meta-data/", forbidden: true},
+		{url: "http://127.0.0.1:8080/", forbidden: true},
+		{url: "https://[::1]/hook", forbidden: true},
+		{url: "http://10.1.2.3/", forbidden: true},
+		{url: "ftp://93.184.216.34/", invalid: true},
+		{url: "https:///sem-host", invalid: true},
+		{url: "://", invalid: true},
+	}
+	for _, tt := range tests {
+		err := CheckURL(context.Background(), tt.url)
+		switch {
+		case tt.forbidden && !errors.Is(err, ErrForbiddenAddress):
+			t.Errorf("CheckURL(%s) = %v, esperado ErrForbiddenAddress", tt.url, err)
+		case tt.invalid && (err == nil || errors.Is(err, ErrForbiddenAddress)):
+			t.Errorf("CheckURL(%s) = %v, esperado url inválida", tt.url, err)
+		case !tt.forbidden && !tt.invalid && err != nil:
+			t.Errorf("CheckURL(%s) = %v", tt.url, err)
+		}
+	}
+}
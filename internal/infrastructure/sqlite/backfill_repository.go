@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.BackfillRepository = (*BackfillRepository)(nil)
+
+type BackfillRepository struct {
+	db *Database
+}
+
+func NewBackfillRepository(db *Database) *BackfillRepository {
+	return &BackfillRepository{db: db}
+}
+
+const backfillColumns = `
+	id, tenant_id, user_id, folder, since, until, cursor, status,
+	scanned, classified, failed, last_error, created_at, updated_at`
+
+func (r *BackfillRepository) Create(ctx context.Context, job *entities.BackfillJob) error {
+	job.ID = newID()
+	job.CreatedAt = now()
+	job.UpdatedAt = job.CreatedAt
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO backfill_jobs (
+				id, tenant_id, user_id, folder, since, until, cursor, status,
+				created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			job.ID, job.TenantID, job.UserID, job.Folder,
+			formatTime(job.Since), formatTime(job.Until), formatTime(job.Cursor), job.Status,
+			formatTime(job.CreatedAt), formatTime(job.UpdatedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao criar job de backfill: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *BackfillRepository) GetByID(ctx context.Context, id string) (*entities.BackfillJob, error) {
+	row := r.db.db.QueryRowContext(ctx, "SELECT"+backfillColumns+" FROM backfill_jobs WHERE id = ?", id)
+	job, err := scanBackfillJob(row)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar job de backfill: %v", err)
+	}
+	return job, nil
+}
+
+func (r *BackfillRepository) UpdateProgress(ctx context.Context, job *entities.BackfillJob) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		updatedAt := now()
+		result, err := tx.ExecContext(ctx, `
+			UPDATE backfill_jobs SET
+				cursor = ?, scanned = ?, classified = ?, failed = ?, last_error = ?, updated_at = ?
+			WHERE id = ?`,
+			formatTime(job.Cursor), job.Scanned, job.Classified,
+			job.Failed, job.LastError, formatTime(updatedAt), job.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar progresso do backfill: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("job de backfill não encontrado com id: %s", job.ID)
+		}
+		job.UpdatedAt = updatedAt
+		return nil
+	})
+}
+
+func (r *BackfillRepository) UpdateStatus(ctx context.Context, id string, status entities.BackfillStatus) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			"UPDATE backfill_jobs SET status = ?, updated_at = ? WHERE id = ?",
+			status, formatTime(now()), id,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar status do backfill: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("job de backfill não encontrado com id: %s", id)
+		}
+		return nil
+	})
+}
+
+func (r *BackfillRepository) ListUnfinished(ctx context.Context, tenantID, userID string) ([]*entities.BackfillJob, error) {
+	rows, err := r.db.db.QueryContext(ctx, `SELECT`+backfillColumns+`
+		FROM backfill_jobs
+		WHERE tenant_id = ? AND user_id = ? AND status IN ('pending', 'running')
+		ORDER BY created_at ASC`,
+		tenantID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar jobs de backfill: %v", err)
+	}
+	defer rows.Close()
+
+	var jobs []*entities.BackfillJob
+	for rows.Next() {
+		job, err := scanBackfillJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler job de backfill: %v", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func scanBackfillJob(row rowScanner) (*entities.BackfillJob, error) {
+	var job entities.BackfillJob
+	err := row.Scan(
+		&job.ID, &job.TenantID, &job.UserID, &job.Folder,
+		scanTime(&job.Since), scanTime(&job.Until), scanTime(&job.Cursor), &job.Status,
+		&job.Scanned, &job.Classified, &job.Failed, &job.LastError,
+		scanTime(&job.CreatedAt), scanTime(&job.UpdatedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/enzo010/email-filter/internal/infrastructure/repotest"
+)
+
+func TestRepositoryConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Repositories {
+		db, err := NewDatabase(context.Background(), &Config{Path: filepath.Join(t.TempDir(), "email_filter.db")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(db.Close)
+		return &repotest.Repositories{
+			Tenants:      NewTenantRepository(db),
+			Users:        NewUserRepository(db),
+			Emails:       NewEmailRepository(db),
+			Tasks:        NewTaskRepository(db),
+			Reminders:    NewReminderRepository(db),
+			Outbox:       NewOutboxRepository(db),
+			Webhooks:     NewWebhookRepository(db),
+			Integrations: NewIntegrationRepository(db),
+			Backfill:     NewBackfillRepository(db),
+			FolderStates: NewFolderStateRepository(db),
+		}
+	})
+}
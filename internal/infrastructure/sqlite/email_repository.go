@@ -0,0 +1,720 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.EmailRepository = (*EmailRepository)(nil)
+
+type EmailRepository struct {
+	db *Database
+}
+
+func NewEmailRepository(db *Database) *EmailRepository {
+	return &EmailRepository{db: db}
+}
+
+const emailColumns = `
+	e.id, e.tenant_id, e.user_id, COALESCE(e.message_id, ''), e.content_hash,
+	e.subject, e.from_address, e.to_address, COALESCE(e.content, ''), e.folder,
+	e.priority, e.category, e.processed_at, e.created_at, e.updated_at`
+
+func (r *EmailRepository) Create(ctx context.Context, email *entities.Email) (bool, error) {
+	created, err := r.CreateAll(ctx, []*entities.Email{email})
+	if err != nil {
+		return false, err
+	}
+	return created[0], nil
+}
+
+func (r *EmailRepository) CreateAll(ctx context.Context, emails []*entities.Email) ([]bool, error) {
+	scope := entities.TenantFromContext(ctx)
+	for _, email := range emails {
+		if email.TenantID == "" || email.UserID == "" {
+			return nil, entities.ErrEmailOwnerRequired
+		}
+		if scope != "" && scope != email.TenantID {
+			return nil, fmt.Errorf("erro ao inserir email: tenant %s fora do escopo", email.TenantID)
+		}
+		if email.ContentHash == "" {
+			email.ContentHash = email.ComputeContentHash()
+		}
+	}
+
+	created := make([]bool, len(emails))
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		for i, email := range emails {
+			var err error
+			if created[i], err = insertEmail(ctx, tx, email); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// insertEmail grava o email com labels, tarefas, reunião e eventos do outbox.
+// Retorna false sem erro se a mensagem já estava armazenada no tenant.
+func insertEmail(ctx context.Context, tx *sql.Tx, email *entities.Email) (bool, error) {
+	// Inserir email, ignorando mensagens já armazenadas no tenant
+	id, createdAt := newID(), now()
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO emails (
+			id, tenant_id, user_id, message_id, content_hash, subject, from_address,
+			to_address, content, folder, priority, category, processed_at,
+			created_at, updated_at
+		) VALUES (?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING`,
+		id, email.TenantID, email.UserID, email.MessageID, email.ContentHash,
+		email.Subject, email.From, email.To, email.Content, email.Folder,
+		email.Priority, email.Category, formatTime(email.ProcessedAt),
+		formatTime(createdAt), formatTime(createdAt),
+	)
+	if err != nil {
+		return false, fmt.Errorf("erro ao inserir email: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		// Conflito: carregar o registro existente
+		return false, findExisting(ctx, tx, email)
+	}
+	email.ID, email.CreatedAt, email.UpdatedAt = id, createdAt, createdAt
+
+	if err := insertLabels(ctx, tx, email.ID, email.Labels); err != nil {
+		return false, err
+	}
+	for i := range email.Tasks {
+		task := &email.Tasks[i]
+		task.EmailID = email.ID
+		if err := insertTask(ctx, tx, task); err != nil {
+			return false, err
+		}
+	}
+	if err := insertMeeting(ctx, tx, email); err != nil {
+		return false, err
+	}
+
+	// Eventos publicados pelo dispatcher do outbox após o commit
+	events, err := entities.EmailEvents(email)
+	if err != nil {
+		return false, err
+	}
+	return true, insertOutbox(ctx, tx, events)
+}
+
+// findExisting preenche email com o registro que causou o conflito na inserção
+func findExisting(ctx context.Context, tx *sql.Tx, email *entities.Email) error {
+	query := `SELECT id, created_at, updated_at FROM emails WHERE tenant_id = ? AND message_id = ?`
+	args := []interface{}{email.TenantID, email.MessageID}
+	if email.MessageID == "" {
+		query = `SELECT id, created_at, updated_at FROM emails
+			WHERE tenant_id = ? AND message_id IS NULL AND content_hash = ?`
+		args = []interface{}{email.TenantID, email.ContentHash}
+	}
+
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&email.ID, scanTime(&email.CreatedAt), scanTime(&email.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("erro ao buscar email existente: %v", err)
+	}
+	return nil
+}
+
+// insertLabels grava as labels do email
+func insertLabels(ctx context.Context, tx *sql.Tx, emailID string, labels []string) error {
+	for _, label := range labels {
+		_, err := tx.ExecContext(ctx,
+			"INSERT OR IGNORE INTO email_labels (email_id, label) VALUES (?, ?)",
+			emailID, label,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir label: %v", err)
+		}
+	}
+	return nil
+}
+
+// insertTask grava uma nova tarefa do email task.EmailID
+func insertTask(ctx context.Context, tx *sql.Tx, task *entities.Task) error {
+	task.ID = newID()
+	task.CreatedAt = now()
+	task.UpdatedAt = task.CreatedAt
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO tasks (
+			id, email_id, description, due_date, priority, status,
+			assignee_id, snoozed_until, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?)`,
+		task.ID, task.EmailID, task.Description, formatTime(task.DueDate),
+		task.Priority, task.Status, task.AssigneeID, formatNullTime(task.SnoozedUntil),
+		formatTime(task.CreatedAt), formatTime(task.UpdatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir tarefa: %v", err)
+	}
+	return nil
+}
+
+func (r *EmailRepository) GetByID(ctx context.Context, id string) (*entities.Email, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var email *entities.Email
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		email, err = scanEmail(tx.QueryRowContext(ctx,
+			"SELECT"+emailColumns+" FROM emails e WHERE e.id = ? AND e.tenant_id = ?",
+			id, tenantID,
+		))
+		if err != nil {
+			return fmt.Errorf("erro ao buscar email: %v", err)
+		}
+		return loadDetails(ctx, tx, []*entities.Email{email})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return email, nil
+}
+
+// loadDetails carrega labels, tarefas e reuniões dos emails
+func loadDetails(ctx context.Context, tx *sql.Tx, emails []*entities.Email) error {
+	if len(emails) == 0 {
+		return nil
+	}
+	byID := make(map[string]*entities.Email, len(emails))
+	ids := make([]string, len(emails))
+	for i, e := range emails {
+		byID[e.ID] = e
+		ids[i] = e.ID
+	}
+	in := placeholders(len(ids))
+
+	rows, err := tx.QueryContext(ctx,
+		"SELECT email_id, label FROM email_labels WHERE email_id IN ("+in+") ORDER BY label",
+		stringArgs(ids)...,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar labels: %v", err)
+	}
+	for rows.Next() {
+		var emailID, label string
+		if err := rows.Scan(&emailID, &label); err != nil {
+			rows.Close()
+			return fmt.Errorf("erro ao ler label: %v", err)
+		}
+		byID[emailID].Labels = append(byID[emailID].Labels, label)
+	}
+	rows.Close()
+
+	rows, err = tx.QueryContext(ctx,
+		"SELECT"+taskColumns+" FROM tasks t WHERE t.email_id IN ("+in+") ORDER BY t.created_at, t.id",
+		stringArgs(ids)...,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar tarefas: %v", err)
+	}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("erro ao ler tarefa: %v", err)
+		}
+		e := byID[task.EmailID]
+		e.Tasks = append(e.Tasks, *task)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = tx.QueryContext(ctx, `
+		SELECT email_id, uid, sequence, method, status, summary, organizer,
+			location, starts_at, ends_at, attendees
+		FROM email_meetings WHERE email_id IN (`+in+`)`,
+		stringArgs(ids)...,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar reuniões: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var emailID, attendees string
+		m := &entities.Meeting{}
+		if err := rows.Scan(
+			&emailID, &m.UID, &m.Sequence, &m.Method, &m.Status, &m.Summary, &m.Organizer,
+			&m.Location, scanTime(&m.Start), scanTime(&m.End), &attendees,
+		); err != nil {
+			return fmt.Errorf("erro ao ler reunião: %v", err)
+		}
+		if err := json.Unmarshal([]byte(attendees), &m.Attendees); err != nil {
+			return fmt.Errorf("erro ao decodificar participantes: %v", err)
+		}
+		byID[emailID].Meeting = m
+	}
+	return rows.Err()
+}
+
+// insertMeeting grava o convite de reunião do email. Um cancelamento também
+// marca como canceladas as versões anteriores da mesma reunião no tenant.
+func insertMeeting(ctx context.Context, tx *sql.Tx, email *entities.Email) error {
+	m := email.Meeting
+	if m == nil {
+		return nil
+	}
+	attendees, err := json.Marshal(append([]string{}, m.Attendees...))
+	if err != nil {
+		return fmt.Errorf("erro ao codificar participantes: %v", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO email_meetings (
+			email_id, uid, sequence, method, status, summary,
+			organizer, location, starts_at, ends_at, attendees
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		email.ID, m.UID, m.Sequence, m.Method, m.Status, m.Summary,
+		m.Organizer, m.Location, formatTime(m.Start), formatTime(m.End), string(attendees),
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir reunião: %v", err)
+	}
+
+	if m.Cancelled() {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE email_meetings SET status = ?
+			WHERE uid = ? AND sequence <= ?
+			  AND email_id IN (SELECT id FROM emails WHERE tenant_id = ?)`,
+			entities.MeetingCancelled, m.UID, m.Sequence, email.TenantID,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao cancelar reunião: %v", err)
+		}
+	}
+	return cancelPrepTasks(ctx, tx, email)
+}
+
+// cancelPrepTasks cancela as tarefas de preparação abertas das versões
+// anteriores da reunião do email, registrando a mudança no histórico
+func cancelPrepTasks(ctx context.Context, tx *sql.Tx, email *entities.Email) error {
+	m := email.Meeting
+	rows, err := tx.QueryContext(ctx, `
+		SELECT t.id, t.status, t.description FROM tasks t
+		JOIN emails e ON e.id = t.email_id
+		JOIN email_meetings m ON m.email_id = t.email_id
+		WHERE e.tenant_id = ? AND m.uid = ? AND m.sequence <= ? AND t.email_id <> ?
+		  AND t.status IN ('pending', 'in_progress', 'snoozed')`,
+		email.TenantID, m.UID, m.Sequence, email.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao listar tarefas da reunião: %v", err)
+	}
+	var stale []entities.TaskStatusChange
+	for rows.Next() {
+		var c entities.TaskStatusChange
+		var description string
+		if err := rows.Scan(&c.TaskID, &c.FromStatus, &description); err != nil {
+			rows.Close()
+			return fmt.Errorf("erro ao ler tarefa da reunião: %v", err)
+		}
+		if strings.HasPrefix(description, entities.MeetingPrepTask) {
+			stale = append(stale, c)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("erro ao listar tarefas da reunião: %v", err)
+	}
+
+	updatedAt := formatTime(now())
+	for _, c := range stale {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE tasks SET status = 'cancelled', snoozed_until = NULL, updated_at = ? WHERE id = ?`,
+			updatedAt, c.TaskID,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao cancelar tarefa da reunião: %v", err)
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO task_status_history (id, task_id, from_status, to_status, note, created_at)
+			VALUES (?, ?, ?, 'cancelled', ?, ?)`,
+			newID(), c.TaskID, c.FromStatus, m.SupersededNote(), updatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar histórico da tarefa: %v", err)
+		}
+	}
+	return nil
+}
+
+func (r *EmailRepository) ListByTenant(ctx context.Context, tenantID string, filter *entities.EmailFilter) (*entities.PageResult[*entities.Email], error) {
+	b := &queryBuilder{}
+	b.where("e.tenant_id = ?", tenantID)
+	return r.list(ctx, b, filter, true)
+}
+
+func (r *EmailRepository) ListByUser(ctx context.Context, userID string, filter *entities.EmailFilter) (*entities.PageResult[*entities.Email], error) {
+	// Como no PostgreSQL, a listagem por usuário não inclui labels e tarefas
+	b := &queryBuilder{}
+	b.where("e.user_id = ?", userID)
+	return r.list(ctx, b, filter, false)
+}
+
+// list filtra, ordena e pagina os emails que atendem às condições de b
+func (r *EmailRepository) list(ctx context.Context, b *queryBuilder, filter *entities.EmailFilter, details bool) (*entities.PageResult[*entities.Email], error) {
+	if filter == nil {
+		filter = &entities.EmailFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	p, err := entities.NewPager(filter.Page, filter.Sort == entities.EmailSortCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	scopeTenant(ctx, b, "e.tenant_id")
+	applyEmailFilter(b, filter, "e.")
+	countQuery, countArgs := b.count("emails e")
+	b.cursorWhere(p, filter.Order, "e.")
+
+	query := "SELECT" + emailColumns + " FROM emails e" + b.whereClause() +
+		orderBy(emailSortExpr(filter.Sort, "e."), p.Order(filter.Order), "e.") +
+		b.limit(p)
+
+	var emails []*entities.Email
+	var total *int64
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if total, err = countTotal(ctx, tx, filter.Page, countQuery, countArgs); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, query, b.args...)
+		if err != nil {
+			return fmt.Errorf("erro ao listar emails: %v", err)
+		}
+		for rows.Next() {
+			email, err := scanEmail(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("erro ao ler email: %v", err)
+			}
+			emails = append(emails, email)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if details {
+			return loadDetails(ctx, tx, emails)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := entities.Paginate(p, emails, emailKey)
+	result.Total = total
+	return result, nil
+}
+
+func (r *EmailRepository) Update(ctx context.Context, email *entities.Email) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		b := &queryBuilder{}
+		b.where("id = ?", email.ID)
+		b.where("tenant_id = ?", email.TenantID)
+		b.where("user_id = ?", email.UserID)
+		scopeTenant(ctx, b, "tenant_id")
+
+		updatedAt := now()
+		args := append([]interface{}{
+			email.Subject, email.From, email.To, email.Content, email.Folder,
+			email.Priority, email.Category, formatTime(email.ProcessedAt), formatTime(updatedAt),
+		}, b.args...)
+		result, err := tx.ExecContext(ctx, `
+			UPDATE emails SET
+				subject = ?, from_address = ?, to_address = ?, content = ?, folder = ?,
+				priority = ?, category = ?, processed_at = ?, updated_at = ?`+b.whereClause(),
+			args...,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar email: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("erro ao atualizar email: email não encontrado com id: %s", email.ID)
+		}
+		email.UpdatedAt = updatedAt
+
+		// Substituir labels
+		if _, err := tx.ExecContext(ctx, "DELETE FROM email_labels WHERE email_id = ?", email.ID); err != nil {
+			return fmt.Errorf("erro ao remover labels antigas: %v", err)
+		}
+		if err := insertLabels(ctx, tx, email.ID, email.Labels); err != nil {
+			return err
+		}
+
+		// Atualizar tarefas existentes e adicionar novas
+		for i := range email.Tasks {
+			task := &email.Tasks[i]
+			task.EmailID = email.ID
+			if task.ID == "" {
+				if err := insertTask(ctx, tx, task); err != nil {
+					return err
+				}
+				continue
+			}
+			task.UpdatedAt = updatedAt
+			result, err := tx.ExecContext(ctx, `
+				UPDATE tasks SET description = ?, due_date = ?, priority = ?, status = ?, updated_at = ?
+				WHERE id = ? AND email_id = ?`,
+				task.Description, formatTime(task.DueDate), task.Priority, task.Status,
+				formatTime(task.UpdatedAt), task.ID, email.ID,
+			)
+			if err != nil {
+				return fmt.Errorf("erro ao atualizar tarefa: %v", err)
+			}
+			if n, _ := result.RowsAffected(); n == 0 {
+				return fmt.Errorf("erro ao atualizar tarefa: tarefa não encontrada com id: %s", task.ID)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *EmailRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		// Labels e tarefas são removidas em cascata
+		result, err := tx.ExecContext(ctx, "DELETE FROM emails WHERE id = ? AND tenant_id = ?", id, tenantID)
+		if err != nil {
+			return fmt.Errorf("erro ao deletar email: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("email não encontrado com id: %s", id)
+		}
+		return nil
+	})
+}
+
+func (r *EmailRepository) Search(ctx context.Context, tenantID string, query *entities.EmailSearchQuery, page entities.Page) (*entities.PageResult[*entities.EmailSearchResult], error) {
+	// Resultados por relevância são paginados por deslocamento
+	if err := page.Normalize(); err != nil {
+		return nil, err
+	}
+	p, err := entities.NewPager(page, false)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &queryBuilder{}
+
+	// Sem termos de busca (apenas from:/label:), ordenar pelos mais recentes
+	selectRank := `0.0 AS rank, e.subject, substr(COALESCE(e.content, ''), 1, 200)`
+	from := "emails e"
+	order := "e.created_at DESC"
+
+	if match := ftsQuery(query.Text); match != "" {
+		// bm25 é menor para os mais relevantes; o assunto pesa mais que o conteúdo
+		// Marcadores de controle no lugar de <mark>: o texto é escapado antes de
+		// recebê-los de volta (ver highlightHTML)
+		selectRank = `-bm25(emails_fts, 10.0, 5.0, 1.0) AS rank,
+			highlight(emails_fts, 0, char(2), char(3)),
+			snippet(emails_fts, 2, char(2), char(3), ' … ', 30)`
+		from = "emails_fts JOIN emails e ON e.seq = emails_fts.rowid"
+		b.where("emails_fts MATCH ?", match)
+		order = "rank DESC, e.created_at DESC"
+	}
+
+	b.where("e.tenant_id = ?", tenantID)
+	scopeTenant(ctx, b, "e.tenant_id")
+	for _, sender := range query.From {
+		b.where(`e.from_address LIKE ? ESCAPE '\'`, "%"+escapeLike(sender)+"%")
+	}
+	for _, label := range query.Labels {
+		b.where("EXISTS (SELECT 1 FROM email_labels el WHERE el.email_id = e.id AND el.label = ?)", label)
+	}
+	countQuery, countArgs := b.count(from)
+
+	sqlQuery := "SELECT" + emailColumns + ", " + selectRank + " FROM " + from + b.whereClause() +
+		" ORDER BY " + order + ", e.id" + b.limit(p)
+
+	var results []*entities.EmailSearchResult
+	var total *int64
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if total, err = countTotal(ctx, tx, page, countQuery, countArgs); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, sqlQuery, b.args...)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar emails: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			email := &entities.Email{}
+			result := &entities.EmailSearchResult{Email: email}
+			err := rows.Scan(append(emailFields(email), &result.Rank, &result.Subject, &result.Highlight)...)
+			if err != nil {
+				return fmt.Errorf("erro ao ler email: %v", err)
+			}
+			result.Subject = highlightHTML(result.Subject)
+			result.Highlight = highlightHTML(result.Highlight)
+			results = append(results, result)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := entities.Paginate(p, results, func(r *entities.EmailSearchResult) (time.Time, string) {
+		return emailKey(r.Email)
+	})
+	result.Total = total
+	return result, nil
+}
+
+// highlightMarkers troca os marcadores de highlight/snippet por <mark>
+var highlightMarkers = strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>")
+
+// highlightHTML escapa o trecho retornado pelo FTS5 e aplica as marcações <mark>
+func highlightHTML(s string) string {
+	return highlightMarkers.Replace(html.EscapeString(s))
+}
+
+// ftsQuery converte a busca no estilo do websearch_to_tsquery (termos, "frases",
+// OR e -exclusão) para a sintaxe do FTS5, com cada termo entre aspas
+func ftsQuery(text string) string {
+	var terms, excluded []string
+	pendingOr := false
+
+	for _, token := range searchTokens(text) {
+		negated := strings.HasPrefix(token, "-") && len(token) > 1
+		if negated {
+			token = token[1:]
+		}
+		if !strings.HasPrefix(token, `"`) && strings.EqualFold(token, "or") {
+			pendingOr = len(terms) > 0
+			continue
+		}
+		phrase := strings.Trim(token, `"`)
+		if strings.IndexFunc(phrase, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsNumber(r) }) < 0 {
+			continue
+		}
+		quoted := `"` + strings.ReplaceAll(phrase, `"`, `""`) + `"`
+
+		if negated {
+			excluded = append(excluded, quoted)
+			continue
+		}
+		if pendingOr {
+			terms[len(terms)-1] += " OR " + quoted
+			pendingOr = false
+			continue
+		}
+		terms = append(terms, quoted)
+	}
+
+	// O FTS5 não aceita apenas exclusões
+	if len(terms) == 0 {
+		return ""
+	}
+	query := "(" + strings.Join(terms, ") AND (") + ")"
+	for _, term := range excluded {
+		query += " NOT " + term
+	}
+	return query
+}
+
+// searchTokens separa o texto em palavras, mantendo frases entre aspas juntas
+func searchTokens(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			current.WriteRune(r)
+			inQuotes = !inQuotes
+		case unicode.IsSpace(r) && !inQuotes:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// escapeLike escapa os curingas do LIKE em valores informados pelo usuário
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// emailFields destinos da leitura de emailColumns
+func emailFields(email *entities.Email) []interface{} {
+	return []interface{}{
+		&email.ID, &email.TenantID, &email.UserID, &email.MessageID, &email.ContentHash,
+		&email.Subject, &email.From, &email.To, &email.Content, &email.Folder,
+		&email.Priority, &email.Category, scanTime(&email.ProcessedAt),
+		scanTime(&email.CreatedAt), scanTime(&email.UpdatedAt),
+	}
+}
+
+// rowScanner linha de *sql.Row ou *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEmail(row rowScanner) (*entities.Email, error) {
+	email := &entities.Email{}
+	if err := row.Scan(emailFields(email)...); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
+
+// emailKey chave de paginação de um email
+func emailKey(e *entities.Email) (time.Time, string) {
+	return e.CreatedAt, e.ID
+}
+
+// countTotal executa a contagem quando a página pede o total
+func countTotal(ctx context.Context, tx *sql.Tx, page entities.Page, query string, args []interface{}) (*int64, error) {
+	if !page.WithTotal {
+		return nil, nil
+	}
+	var total int64
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("erro ao contar registros: %v", err)
+	}
+	return &total, nil
+}
@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.FolderStateRepository = (*FolderStateRepository)(nil)
+
+type FolderStateRepository struct {
+	db *Database
+}
+
+func NewFolderStateRepository(db *Database) *FolderStateRepository {
+	return &FolderStateRepository{db: db}
+}
+
+func (r *FolderStateRepository) GetFolderState(ctx context.Context, account, folder string) (*entities.FolderState, error) {
+	state := &entities.FolderState{Account: account, Folder: folder}
+	err := r.db.db.QueryRowContext(ctx,
+		"SELECT uid_validity, last_uid FROM folder_states WHERE account = ? AND folder = ?",
+		account, folder,
+	).Scan(&state.UIDValidity, &state.LastUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar estado da pasta: %v", err)
+	}
+	return state, nil
+}
+
+func (r *FolderStateRepository) SaveFolderState(ctx context.Context, state *entities.FolderState) error {
+	_, err := r.db.db.ExecContext(ctx, `
+		INSERT INTO folder_states (account, folder, uid_validity, last_uid, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (account, folder) DO UPDATE SET
+			uid_validity = excluded.uid_validity,
+			last_uid = excluded.last_uid,
+			updated_at = excluded.updated_at`,
+		state.Account, state.Folder, state.UIDValidity, state.LastUID, formatTime(now()),
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar estado da pasta: %v", err)
+	}
+	return nil
+}
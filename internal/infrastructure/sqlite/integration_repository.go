@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.IntegrationRepository = (*IntegrationRepository)(nil)
+
+type IntegrationRepository struct {
+	db *Database
+}
+
+func NewIntegrationRepository(db *Database) *IntegrationRepository {
+	return &IntegrationRepository{db: db}
+}
+
+const integrationColumns = `
+	id, tenant_id, kind, name, webhook_url, priorities, categories, active, created_at, updated_at`
+
+func (r *IntegrationRepository) Create(ctx context.Context, integration *entities.Integration) error {
+	if scope := entities.TenantFromContext(ctx); scope != "" && scope != integration.TenantID {
+		return fmt.Errorf("erro ao criar integração: tenant %s fora do escopo", integration.TenantID)
+	}
+	priorities, categories, err := encodeCriteria(integration.Criteria)
+	if err != nil {
+		return err
+	}
+
+	id, createdAt := newID(), now()
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO integrations (
+				id, tenant_id, kind, name, webhook_url, priorities, categories, active, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, integration.TenantID, integration.Kind, integration.Name, integration.WebhookURL,
+			priorities, categories, integration.Active, formatTime(createdAt), formatTime(createdAt),
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao criar integração: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	integration.ID, integration.CreatedAt, integration.UpdatedAt = id, createdAt, createdAt
+	return nil
+}
+
+func (r *IntegrationRepository) GetByID(ctx context.Context, id string) (*entities.Integration, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	row := r.db.db.QueryRowContext(ctx,
+		"SELECT"+integrationColumns+" FROM integrations WHERE id = ? AND tenant_id = ?",
+		id, tenantID,
+	)
+	integration, err := scanIntegration(row)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar integração: %v", err)
+	}
+	return integration, nil
+}
+
+func (r *IntegrationRepository) ListByTenant(ctx context.Context, tenantID string) ([]*entities.Integration, error) {
+	b := &queryBuilder{}
+	b.where("tenant_id = ?", tenantID)
+	return r.list(ctx, b)
+}
+
+func (r *IntegrationRepository) ListActive(ctx context.Context, tenantID string) ([]*entities.Integration, error) {
+	b := &queryBuilder{}
+	b.where("tenant_id = ?", tenantID)
+	b.where("active = 1")
+	return r.list(ctx, b)
+}
+
+// list retorna as integrações que atendem às condições de b
+func (r *IntegrationRepository) list(ctx context.Context, b *queryBuilder) ([]*entities.Integration, error) {
+	scopeTenant(ctx, b, "tenant_id")
+	rows, err := r.db.db.QueryContext(ctx,
+		"SELECT"+integrationColumns+" FROM integrations"+b.whereClause()+" ORDER BY created_at, id",
+		b.args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar integrações: %v", err)
+	}
+	defer rows.Close()
+
+	var integrations []*entities.Integration
+	for rows.Next() {
+		integration, err := scanIntegration(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler integração: %v", err)
+		}
+		integrations = append(integrations, integration)
+	}
+	return integrations, rows.Err()
+}
+
+func (r *IntegrationRepository) Update(ctx context.Context, integration *entities.Integration) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+	priorities, categories, err := encodeCriteria(integration.Criteria)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		updatedAt := now()
+		result, err := tx.ExecContext(ctx, `
+			UPDATE integrations SET
+				name = ?, webhook_url = ?, priorities = ?, categories = ?, active = ?, updated_at = ?
+			WHERE id = ? AND tenant_id = ?`,
+			integration.Name, integration.WebhookURL, priorities, categories, integration.Active,
+			formatTime(updatedAt), integration.ID, tenantID,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar integração: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("erro ao atualizar integração: integração não encontrada com id: %s", integration.ID)
+		}
+		integration.UpdatedAt = updatedAt
+		return nil
+	})
+}
+
+func (r *IntegrationRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM integrations WHERE id = ? AND tenant_id = ?", id, tenantID)
+		if err != nil {
+			return fmt.Errorf("erro ao deletar integração: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("integração não encontrada com id: %s", id)
+		}
+		return nil
+	})
+}
+
+// encodeCriteria codifica as listas de critérios como arrays JSON
+func encodeCriteria(c entities.IntegrationCriteria) (string, string, error) {
+	priorities, err := json.Marshal(append([]entities.Priority{}, c.Priorities...))
+	if err != nil {
+		return "", "", fmt.Errorf("erro ao codificar prioridades: %v", err)
+	}
+	categories, err := json.Marshal(append([]string{}, c.Categories...))
+	if err != nil {
+		return "", "", fmt.Errorf("erro ao codificar categorias: %v", err)
+	}
+	return string(priorities), string(categories), nil
+}
+
+func scanIntegration(row rowScanner) (*entities.Integration, error) {
+	var integration entities.Integration
+	var priorities, categories string
+	err := row.Scan(
+		&integration.ID, &integration.TenantID, &integration.Kind, &integration.Name,
+		&integration.WebhookURL, &priorities, &categories, &integration.Active,
+		scanTime(&integration.CreatedAt), scanTime(&integration.UpdatedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(priorities), &integration.Criteria.Priorities); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar prioridades: %v", err)
+	}
+	if err := json.Unmarshal([]byte(categories), &integration.Criteria.Categories); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar categorias: %v", err)
+	}
+	return &integration, nil
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS backfill_jobs;
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS email_labels;
+DROP TABLE IF EXISTS emails;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS tenants;
@@ -0,0 +1,90 @@
+-- Schema inicial do backend SQLite, equivalente às migrações 001 a 005 do
+-- PostgreSQL. Datas são gravadas como texto UTC de largura fixa
+-- (AAAA-MM-DDTHH:MM:SS.ffffffZ), o que preserva a ordenação.
+
+CREATE TABLE tenants (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    plan TEXT NOT NULL,
+    active INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE users (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    email TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    role TEXT NOT NULL,
+    active INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+-- seq é a chave usada pelo índice FTS5: o rowid implícito pode mudar no VACUUM
+CREATE TABLE emails (
+    seq INTEGER PRIMARY KEY,
+    id TEXT NOT NULL UNIQUE,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message_id TEXT,
+    content_hash TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    from_address TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    content TEXT,
+    folder TEXT NOT NULL DEFAULT 'INBOX',
+    priority TEXT NOT NULL,
+    category TEXT NOT NULL,
+    processed_at TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE email_labels (
+    email_id TEXT NOT NULL REFERENCES emails(id) ON DELETE CASCADE,
+    label TEXT NOT NULL,
+    PRIMARY KEY (email_id, label)
+);
+
+CREATE TABLE tasks (
+    id TEXT PRIMARY KEY,
+    email_id TEXT NOT NULL REFERENCES emails(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    due_date TEXT NOT NULL,
+    priority TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE backfill_jobs (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    folder TEXT NOT NULL DEFAULT 'INBOX',
+    since TEXT NOT NULL,
+    until TEXT NOT NULL,
+    cursor TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    scanned INTEGER NOT NULL DEFAULT 0,
+    classified INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_users_tenant ON users(tenant_id);
+CREATE INDEX idx_emails_tenant_created ON emails(tenant_id, created_at, id);
+CREATE INDEX idx_emails_user ON emails(user_id);
+CREATE INDEX idx_tasks_email ON tasks(email_id);
+CREATE INDEX idx_backfill_jobs_owner ON backfill_jobs(tenant_id, user_id, status);
+
+-- Deduplicação por tenant: Message-ID ou, na falta dele, hash do conteúdo
+CREATE UNIQUE INDEX emails_tenant_message_id_key
+    ON emails(tenant_id, message_id) WHERE message_id IS NOT NULL;
+CREATE UNIQUE INDEX emails_tenant_content_hash_key
+    ON emails(tenant_id, content_hash) WHERE message_id IS NULL;
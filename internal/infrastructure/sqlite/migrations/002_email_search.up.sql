@@ -0,0 +1,26 @@
+-- Busca textual em emails com FTS5 sobre assunto, remetente e conteúdo.
+-- O tokenizador porter aplica stemming apenas em inglês; acentos são ignorados.
+CREATE VIRTUAL TABLE emails_fts USING fts5(
+    subject, from_address, content,
+    content = 'emails', content_rowid = 'seq',
+    tokenize = 'porter unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER emails_fts_insert AFTER INSERT ON emails BEGIN
+    INSERT INTO emails_fts (rowid, subject, from_address, content)
+    VALUES (new.seq, new.subject, new.from_address, new.content);
+END;
+
+CREATE TRIGGER emails_fts_delete AFTER DELETE ON emails BEGIN
+    INSERT INTO emails_fts (emails_fts, rowid, subject, from_address, content)
+    VALUES ('delete', old.seq, old.subject, old.from_address, old.content);
+END;
+
+CREATE TRIGGER emails_fts_update AFTER UPDATE OF subject, from_address, content ON emails BEGIN
+    INSERT INTO emails_fts (emails_fts, rowid, subject, from_address, content)
+    VALUES ('delete', old.seq, old.subject, old.from_address, old.content);
+    INSERT INTO emails_fts (rowid, subject, from_address, content)
+    VALUES (new.seq, new.subject, new.from_address, new.content);
+END;
+
+INSERT INTO emails_fts (emails_fts) VALUES ('rebuild');
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS task_status_history;
+
+DROP INDEX IF EXISTS idx_tasks_status;
+DROP INDEX IF EXISTS idx_tasks_assignee;
+
+UPDATE tasks SET status = 'pending' WHERE status IN ('in_progress', 'snoozed');
+UPDATE tasks SET status = 'completed' WHERE status = 'cancelled';
+
+ALTER TABLE tasks DROP COLUMN snoozed_until;
+ALTER TABLE tasks DROP COLUMN assignee_id;
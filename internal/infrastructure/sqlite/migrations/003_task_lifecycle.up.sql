@@ -0,0 +1,21 @@
+-- Ciclo de vida das tarefas, equivalente à migração 008 do PostgreSQL.
+-- assignee_id não declara chave estrangeira porque o SQLite não permite remover
+-- colunas com REFERENCES; a exclusão de usuários limpa a coluna explicitamente.
+
+ALTER TABLE tasks ADD COLUMN assignee_id TEXT;
+ALTER TABLE tasks ADD COLUMN snoozed_until TEXT;
+
+CREATE INDEX idx_tasks_assignee ON tasks(assignee_id);
+CREATE INDEX idx_tasks_status ON tasks(status);
+
+CREATE TABLE task_status_history (
+    id TEXT PRIMARY KEY,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    actor_id TEXT,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_task_status_history_task ON task_status_history(task_id, created_at);
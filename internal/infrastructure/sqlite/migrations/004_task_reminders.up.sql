@@ -0,0 +1,15 @@
+-- Lembretes de prazo das tarefas, equivalente à migração 009 do PostgreSQL.
+
+ALTER TABLE tasks ADD COLUMN overdue_at TEXT;
+
+CREATE INDEX idx_tasks_open_due_date ON tasks(due_date)
+    WHERE status IN ('pending', 'in_progress');
+
+CREATE TABLE task_reminders (
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    due_date TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    sent_at TEXT NOT NULL,
+    PRIMARY KEY (task_id, kind, due_date, channel)
+);
@@ -0,0 +1,18 @@
+-- Convites de reunião, equivalente à migração 010 do PostgreSQL. Os
+-- participantes são armazenados como array JSON.
+
+CREATE TABLE email_meetings (
+    email_id TEXT PRIMARY KEY REFERENCES emails(id) ON DELETE CASCADE,
+    uid TEXT NOT NULL,
+    sequence INTEGER NOT NULL DEFAULT 0,
+    method TEXT NOT NULL,
+    status TEXT NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    organizer TEXT NOT NULL DEFAULT '',
+    location TEXT NOT NULL DEFAULT '',
+    starts_at TEXT NOT NULL,
+    ends_at TEXT NOT NULL,
+    attendees TEXT NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX idx_email_meetings_uid ON email_meetings(uid);
@@ -0,0 +1,17 @@
+-- Integrações de notificações, equivalente à migração 012 do PostgreSQL. Os
+-- critérios são armazenados como arrays JSON.
+
+CREATE TABLE integrations (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    webhook_url TEXT NOT NULL,
+    priorities TEXT NOT NULL DEFAULT '[]',
+    categories TEXT NOT NULL DEFAULT '[]',
+    active INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_integrations_tenant ON integrations(tenant_id);
@@ -0,0 +1,10 @@
+-- Progresso de sincronização das pastas IMAP, equivalente à migração 014 do
+-- PostgreSQL
+CREATE TABLE folder_states (
+    account TEXT NOT NULL,
+    folder TEXT NOT NULL,
+    uid_validity INTEGER NOT NULL,
+    last_uid INTEGER NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (account, folder)
+);
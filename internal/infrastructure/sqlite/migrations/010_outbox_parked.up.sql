@@ -0,0 +1,2 @@
+-- Entradas estacionadas do outbox, equivalente à migração 017 do PostgreSQL.
+ALTER TABLE outbox ADD COLUMN parked_at TEXT;
@@ -0,0 +1,2 @@
+-- Versão do token do feed de calendário, equivalente à migração 019 do PostgreSQL.
+ALTER TABLE users ADD COLUMN feed_token_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,129 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.OutboxRepository = (*OutboxRepository)(nil)
+
+type OutboxRepository struct {
+	db *Database
+}
+
+func NewOutboxRepository(db *Database) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+const outboxColumns = `
+	id, tenant_id, event_id, event_type, data, delivered, attempts, last_error, next_attempt_at, created_at, parked_at`
+
+// insertOutbox grava os eventos no outbox, na transação da alteração que os
+// originou; não faz nada com os eventos desativados no contexto
+func insertOutbox(ctx context.Context, tx *sql.Tx, events []*entities.Event) error {
+	if entities.EventsDisabled(ctx) {
+		return nil
+	}
+	for _, event := range events {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO outbox (tenant_id, event_id, event_type, data, next_attempt_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			event.TenantID, event.ID, event.Type, string(event.Data),
+			formatTime(event.CreatedAt), formatTime(event.CreatedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao gravar evento %s no outbox: %v", event.Type, err)
+		}
+	}
+	return nil
+}
+
+func (r *OutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.OutboxEntry, error) {
+	// A transação de escrita do SQLite é exclusiva: instâncias concorrentes no
+	// mesmo arquivo não reservam o mesmo tenant
+	query := `
+		UPDATE outbox SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT o.id FROM outbox o
+			WHERE o.parked_at IS NULL AND o.tenant_id IN (
+				SELECT h.tenant_id FROM outbox h
+				WHERE h.id IN (SELECT MIN(id) FROM outbox WHERE parked_at IS NULL GROUP BY tenant_id)
+				  AND h.next_attempt_at <= ?
+			)
+			ORDER BY o.id
+			LIMIT ?
+		)
+		RETURNING` + outboxColumns
+
+	var entries []*entities.OutboxEntry
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, formatTime(now.Add(lease)), formatTime(now), limit)
+		if err != nil {
+			return fmt.Errorf("erro ao reservar entradas do outbox: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			entry, err := scanOutboxEntry(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao ler entrada do outbox: %v", err)
+			}
+			entries = append(entries, entry)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING não garante a ordem
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
+
+func (r *OutboxRepository) Complete(ctx context.Context, id int64) error {
+	if _, err := r.db.db.ExecContext(ctx, "DELETE FROM outbox WHERE id = ?", id); err != nil {
+		return fmt.Errorf("erro ao remover entrada do outbox: %v", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) RecordFailure(ctx context.Context, entry *entities.OutboxEntry) error {
+	delivered, err := json.Marshal(append([]string{}, entry.Delivered...))
+	if err != nil {
+		return fmt.Errorf("erro ao codificar sinks: %v", err)
+	}
+	_, err = r.db.db.ExecContext(ctx, `
+		UPDATE outbox SET delivered = ?, attempts = ?, last_error = ?, next_attempt_at = ?, parked_at = ?
+		WHERE id = ?`,
+		string(delivered), entry.Attempts, entry.LastError, formatTime(entry.NextAttemptAt),
+		formatNullTime(entry.ParkedAt), entry.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar falha do outbox: %v", err)
+	}
+	return nil
+}
+
+func scanOutboxEntry(row rowScanner) (*entities.OutboxEntry, error) {
+	entry := entities.OutboxEntry{Event: &entities.Event{}}
+	var data, delivered string
+	err := row.Scan(
+		&entry.ID, &entry.Event.TenantID, &entry.Event.ID, &entry.Event.Type, &data, &delivered,
+		&entry.Attempts, &entry.LastError, scanTime(&entry.NextAttemptAt), scanTime(&entry.Event.CreatedAt),
+		scanNullTime(&entry.ParkedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(delivered), &entry.Delivered); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar sinks: %v", err)
+	}
+	entry.Event.Data = []byte(data)
+	return &entry, nil
+}
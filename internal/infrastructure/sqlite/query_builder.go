@@ -0,0 +1,153 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+// queryBuilder monta cláusulas WHERE, ORDER BY e LIMIT com parâmetros "?",
+// compartilhado pelos repositórios
+type queryBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// where adiciona uma condição com os valores dos seus parâmetros
+func (b *queryBuilder) where(condition string, values ...interface{}) {
+	b.conditions = append(b.conditions, condition)
+	b.args = append(b.args, values...)
+}
+
+// whereIn filtra column por qualquer um dos valores; lista vazia não filtra
+func (b *queryBuilder) whereIn(column string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	b.where(column+" IN ("+placeholders(len(values))+")", stringArgs(values)...)
+}
+
+// whereClause retorna a cláusula WHERE com todas as condições
+func (b *queryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// count retorna a contagem das linhas de from com as condições atuais; deve ser
+// chamado antes de adicionar a condição do cursor
+func (b *queryBuilder) count(from string) (string, []interface{}) {
+	args := make([]interface{}, len(b.args))
+	copy(args, b.args)
+	return "SELECT COUNT(*) FROM " + from + b.whereClause(), args
+}
+
+// cursorWhere adiciona a condição do cursor keyset (colunas com o prefixo alias)
+func (b *queryBuilder) cursorWhere(p *entities.Pager, order entities.SortOrder, alias string) {
+	if !p.Keyset || p.Cursor == nil {
+		return
+	}
+	op := "<"
+	if p.Order(order) == entities.SortAsc {
+		op = ">"
+	}
+	b.where(fmt.Sprintf("(%screated_at, %sid) %s (?, ?)", alias, alias, op),
+		formatTime(p.Cursor.CreatedAt), p.Cursor.ID)
+}
+
+// limit retorna LIMIT/OFFSET com um item extra para detectar a próxima página
+func (b *queryBuilder) limit(p *entities.Pager) string {
+	b.args = append(b.args, p.Size+1, p.Offset)
+	return " LIMIT ? OFFSET ?"
+}
+
+// orderBy retorna a ordenação pela expressão, com o id como desempate
+func orderBy(expr string, order entities.SortOrder, alias string) string {
+	direction := "DESC"
+	if order == entities.SortAsc {
+		direction = "ASC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, %sid %s", expr, direction, alias, direction)
+}
+
+// placeholders retorna n parâmetros separados por vírgula
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// stringArgs converte valores para argumentos da consulta
+func stringArgs(values []string) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
+
+// priorityRank ordena prioridades por importância em vez da ordem alfabética
+func priorityRank(column string) string {
+	return fmt.Sprintf("CASE %s WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END", column)
+}
+
+// priorityStrings converte prioridades para o filtro IN
+func priorityStrings(priorities []entities.Priority) []string {
+	values := make([]string, len(priorities))
+	for i, p := range priorities {
+		values[i] = string(p)
+	}
+	return values
+}
+
+// emailSortExpr expressão SQL do campo de ordenação de emails
+func emailSortExpr(sort, alias string) string {
+	if sort == entities.EmailSortPriority {
+		return priorityRank(alias + "priority")
+	}
+	return alias + sort
+}
+
+// taskSortExpr expressão SQL do campo de ordenação de tarefas
+func taskSortExpr(sort, alias string) string {
+	if sort == entities.TaskSortPriority {
+		return priorityRank(alias + "priority")
+	}
+	return alias + sort
+}
+
+// applyEmailFilter adiciona as condições do filtro de emails
+func applyEmailFilter(b *queryBuilder, f *entities.EmailFilter, alias string) {
+	b.whereIn(alias+"id", f.IDs)
+	b.whereIn(alias+"category", f.Categories)
+	b.whereIn(alias+"priority", priorityStrings(f.Priorities))
+	b.whereIn(alias+"folder", f.Folders)
+	if len(f.Labels) > 0 {
+		b.where("EXISTS (SELECT 1 FROM email_labels fl WHERE fl.email_id = "+alias+"id AND fl.label IN ("+
+			placeholders(len(f.Labels))+"))", stringArgs(f.Labels)...)
+	}
+	if f.StartDate != nil {
+		b.where(alias+"created_at >= ?", formatTime(*f.StartDate))
+	}
+	if f.EndDate != nil {
+		b.where(alias+"created_at <= ?", formatTime(*f.EndDate))
+	}
+}
+
+// applyTaskFilter adiciona as condições do filtro de tarefas
+func applyTaskFilter(b *queryBuilder, f *entities.TaskFilter, alias string) {
+	b.whereIn(alias+"priority", priorityStrings(f.Priorities))
+	b.whereIn(alias+"status", f.Statuses)
+	if f.AssigneeID != "" {
+		b.where(alias+"assignee_id = ?", f.AssigneeID)
+	}
+	if f.UserID != "" {
+		b.where("COALESCE("+alias+"assignee_id, e.user_id) = ?", f.UserID)
+	}
+	if f.StartDate != nil {
+		b.where(alias+"due_date >= ?", formatTime(*f.StartDate))
+	}
+	if f.EndDate != nil {
+		b.where(alias+"due_date <= ?", formatTime(*f.EndDate))
+	}
+}
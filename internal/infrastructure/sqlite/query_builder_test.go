@@ -0,0 +1,163 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+func TestApplyEmailFilterSQL(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name   string
+		filter entities.EmailFilter
+		where  string
+		args   []interface{}
+	}{
+		{
+			name:  "sem filtros",
+			where: "",
+		},
+		{
+			name: "vários valores por campo",
+			filter: entities.EmailFilter{
+				Categories: []string{"work", "finance"},
+				Priorities: []entities.Priority{entities.PriorityHigh},
+				Folders:    []string{"INBOX", "Archive"},
+			},
+			where: " WHERE e.category IN (?, ?) AND e.priority IN (?) AND e.folder IN (?, ?)",
+			args:  []interface{}{"work", "finance", "high", "INBOX", "Archive"},
+		},
+		{
+			name:   "labels e período",
+			filter: entities.EmailFilter{Labels: []string{"a fazer", "urgente"}, StartDate: &start, EndDate: &end},
+			where: " WHERE EXISTS (SELECT 1 FROM email_labels fl WHERE fl.email_id = e.id AND fl.label IN (?, ?))" +
+				" AND e.created_at >= ? AND e.created_at <= ?",
+			args: []interface{}{"a fazer", "urgente", formatTime(start), formatTime(end)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &queryBuilder{}
+			applyEmailFilter(b, &tt.filter, "e.")
+			if got := b.whereClause(); got != tt.where {
+				t.Errorf("where = %q, esperado %q", got, tt.where)
+			}
+			if !reflect.DeepEqual(b.args, tt.args) {
+				t.Errorf("args = %#v, esperado %#v", b.args, tt.args)
+			}
+		})
+	}
+}
+
+func TestApplyTaskFilterSQL(t *testing.T) {
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	b := &queryBuilder{}
+	b.where("e.tenant_id = ?", "tenant")
+	applyTaskFilter(b, &entities.TaskFilter{
+		Priorities: []entities.Priority{entities.PriorityLow},
+		Statuses:   []string{"pending", "in_progress"},
+		AssigneeID: "user",
+		UserID:     "owner",
+		StartDate:  &due,
+	}, "t.")
+
+	want := " WHERE e.tenant_id = ? AND t.priority IN (?) AND t.status IN (?, ?) AND t.assignee_id = ? AND COALESCE(t.assignee_id, e.user_id) = ? AND t.due_date >= ?"
+	if got := b.whereClause(); got != want {
+		t.Errorf("where = %q, esperado %q", got, want)
+	}
+	args := []interface{}{"tenant", "low", "pending", "in_progress", "user", "owner", formatTime(due)}
+	if !reflect.DeepEqual(b.args, args) {
+		t.Errorf("args = %#v, esperado %#v", b.args, args)
+	}
+}
+
+func TestOrderBySQL(t *testing.T) {
+	tests := []struct {
+		expr  string
+		order entities.SortOrder
+		alias string
+		want  string
+	}{
+		{emailSortExpr(entities.EmailSortProcessedAt, "e."), entities.SortAsc, "e.", " ORDER BY e.processed_at ASC, e.id ASC"},
+		{
+			emailSortExpr(entities.EmailSortPriority, ""), entities.SortDesc, "",
+			" ORDER BY CASE priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END DESC, id DESC",
+		},
+		{taskSortExpr(entities.TaskSortCreatedAt, "t."), entities.SortDesc, "t.", " ORDER BY t.created_at DESC, t.id DESC"},
+	}
+	for _, tt := range tests {
+		if got := orderBy(tt.expr, tt.order, tt.alias); got != tt.want {
+			t.Errorf("orderBy(%q) = %q, esperado %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestPaginationSQL(t *testing.T) {
+	created := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	cursor := &entities.Cursor{CreatedAt: created, ID: "id-1"}
+
+	tests := []struct {
+		name  string
+		pager entities.Pager
+		order entities.SortOrder
+		sql   string
+		args  []interface{}
+	}{
+		{
+			name:  "primeira página",
+			pager: entities.Pager{Size: 20, Keyset: true},
+			order: entities.SortDesc,
+			sql:   " WHERE e.tenant_id = ? LIMIT ? OFFSET ?",
+			args:  []interface{}{"tenant", 21, 0},
+		},
+		{
+			name:  "próxima página keyset",
+			pager: entities.Pager{Size: 20, Keyset: true, Cursor: cursor},
+			order: entities.SortAsc,
+			sql:   " WHERE e.tenant_id = ? AND (e.created_at, e.id) > (?, ?) LIMIT ? OFFSET ?",
+			args:  []interface{}{"tenant", formatTime(created), "id-1", 21, 0},
+		},
+		{
+			name:  "página anterior keyset inverte o sentido",
+			pager: entities.Pager{Size: 20, Keyset: true, Cursor: cursor, Backward: true},
+			order: entities.SortAsc,
+			sql:   " WHERE e.tenant_id = ? AND (e.created_at, e.id) < (?, ?) LIMIT ? OFFSET ?",
+			args:  []interface{}{"tenant", formatTime(created), "id-1", 21, 0},
+		},
+		{
+			name:  "deslocamento ignora o cursor",
+			pager: entities.Pager{Size: 10, Cursor: cursor, Offset: 30},
+			order: entities.SortDesc,
+			sql:   " WHERE e.tenant_id = ? LIMIT ? OFFSET ?",
+			args:  []interface{}{"tenant", 11, 30},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &queryBuilder{}
+			b.where("e.tenant_id = ?", "tenant")
+			countSQL, countArgs := b.count("emails e")
+			b.cursorWhere(&tt.pager, tt.order, "e.")
+			sql := b.whereClause() + b.limit(&tt.pager)
+
+			if sql != tt.sql {
+				t.Errorf("sql = %q, esperado %q", sql, tt.sql)
+			}
+			if !reflect.DeepEqual(b.args, tt.args) {
+				t.Errorf("args = %#v, esperado %#v", b.args, tt.args)
+			}
+			// A contagem não inclui o cursor nem a paginação
+			if want := "SELECT COUNT(*) FROM emails e WHERE e.tenant_id = ?"; countSQL != want {
+				t.Errorf("count = %q, esperado %q", countSQL, want)
+			}
+			if !reflect.DeepEqual(countArgs, []interface{}{"tenant"}) {
+				t.Errorf("count args = %#v", countArgs)
+			}
+		})
+	}
+}
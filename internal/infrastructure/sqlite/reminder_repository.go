@@ -0,0 +1,202 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.ReminderRepository = (*ReminderRepository)(nil)
+
+type ReminderRepository struct {
+	db *Database
+}
+
+func NewReminderRepository(db *Database) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+func (r *ReminderRepository) Due(ctx context.Context, now time.Time, lead time.Duration, channels []string, limit int) ([]*entities.Reminder, error) {
+	nowArg := formatTime(now)
+	args := []interface{}{formatTime(now.Add(lead)), nowArg}
+	args = append(args, stringArgs(channels)...)
+	args = append(args, len(channels), limit)
+
+	// O destinatário é o responsável ou, na falta dele, o dono do email
+	rows, err := r.db.db.QueryContext(ctx, `
+		SELECT`+taskColumns+`,
+			   e.tenant_id, u.id, u.email, e.subject
+		FROM tasks t
+		JOIN emails e ON t.email_id = e.id
+		JOIN users u ON u.id = COALESCE(t.assignee_id, e.user_id)
+		WHERE t.status IN ('pending', 'in_progress') AND t.due_date <= ? AND u.active
+		  AND (
+			SELECT COUNT(*) FROM task_reminders r
+			WHERE r.task_id = t.id AND r.due_date = t.due_date
+			  AND r.kind = CASE WHEN t.due_date <= ? THEN 'overdue' ELSE 'due_soon' END
+			  AND r.channel IN (`+placeholders(len(channels))+`)
+		  ) < ?
+		ORDER BY t.due_date, t.id
+		LIMIT ?`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar lembretes: %v", err)
+	}
+	defer rows.Close()
+
+	var reminders []*entities.Reminder
+	for rows.Next() {
+		var rem entities.Reminder
+		t := &rem.Task
+		err := rows.Scan(
+			&t.ID, &t.EmailID, &t.Description, scanTime(&t.DueDate),
+			&t.Priority, &t.Status, &t.AssigneeID, scanNullTime(&t.SnoozedUntil), scanNullTime(&t.OverdueAt),
+			scanTime(&t.CreatedAt), scanTime(&t.UpdatedAt),
+			&rem.TenantID, &rem.UserID, &rem.Recipient, &rem.EmailSubject,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler lembrete: %v", err)
+		}
+		rem.Kind = entities.ReminderDueSoon
+		if !t.DueDate.After(now) {
+			rem.Kind = entities.ReminderOverdue
+		}
+		reminders = append(reminders, &rem)
+	}
+	return reminders, rows.Err()
+}
+
+func (r *ReminderRepository) Claim(ctx context.Context, reminder *entities.Reminder, channel string) (bool, error) {
+	result, err := r.db.db.ExecContext(ctx, `
+		INSERT INTO task_reminders (task_id, kind, due_date, channel, sent_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING`,
+		reminder.Task.ID, reminder.Kind, formatTime(reminder.Task.DueDate), channel, formatTime(now()),
+	)
+	if err != nil {
+		return false, fmt.Errorf("erro ao registrar lembrete: %v", err)
+	}
+	n, _ := result.RowsAffected()
+	return n == 1, nil
+}
+
+func (r *ReminderRepository) Release(ctx context.Context, reminder *entities.Reminder, channel string) error {
+	_, err := r.db.db.ExecContext(ctx, `
+		DELETE FROM task_reminders
+		WHERE task_id = ? AND kind = ? AND due_date = ? AND channel = ?`,
+		reminder.Task.ID, reminder.Kind, formatTime(reminder.Task.DueDate), channel,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao remover registro de lembrete: %v", err)
+	}
+	return nil
+}
+
+func (r *ReminderRepository) MarkOverdue(ctx context.Context, now time.Time) (int64, error) {
+	var marked int64
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		// O RETURNING do SQLite não acessa as tabelas do FROM: as tarefas e os
+		// dados dos eventos são lidos antes da atualização, na mesma transação
+		rows, err := tx.QueryContext(ctx, `
+			SELECT`+taskColumns+`, e.tenant_id, e.subject
+			FROM tasks t
+			JOIN emails e ON t.email_id = e.id
+			WHERE t.status IN ('pending', 'in_progress') AND t.due_date <= ? AND t.overdue_at IS NULL
+			ORDER BY t.due_date, t.id`,
+			formatTime(now),
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao listar tarefas atrasadas: %v", err)
+		}
+
+		var events []*entities.Event
+		var ids []interface{}
+		for rows.Next() {
+			var t entities.Task
+			var tenantID, subject string
+			err := rows.Scan(
+				&t.ID, &t.EmailID, &t.Description, scanTime(&t.DueDate),
+				&t.Priority, &t.Status, &t.AssigneeID, scanNullTime(&t.SnoozedUntil), scanNullTime(&t.OverdueAt),
+				scanTime(&t.CreatedAt), scanTime(&t.UpdatedAt), &tenantID, &subject,
+			)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("erro ao ler tarefa atrasada: %v", err)
+			}
+			at := now
+			t.OverdueAt = &at
+			event, err := entities.NewTaskEvent(tenantID, entities.EventTaskOverdue, t, subject)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			events = append(events, event)
+			ids = append(ids, t.ID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("erro ao listar tarefas atrasadas: %v", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE tasks SET overdue_at = ? WHERE id IN ("+placeholders(len(ids))+")",
+			append([]interface{}{formatTime(now)}, ids...)...,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao marcar tarefas atrasadas: %v", err)
+		}
+		marked = int64(len(ids))
+		return insertOutbox(ctx, tx, events)
+	})
+	return marked, err
+}
+
+func (r *ReminderRepository) WakeSnoozed(ctx context.Context, at time.Time) (int64, error) {
+	var woken int64
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		updatedAt := formatTime(now())
+		rows, err := tx.QueryContext(ctx, `
+			UPDATE tasks SET status = 'pending', snoozed_until = NULL, updated_at = ?
+			WHERE status = 'snoozed' AND snoozed_until <= ?
+			RETURNING id`,
+			updatedAt, formatTime(at),
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao reabrir tarefas adiadas: %v", err)
+		}
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return fmt.Errorf("erro ao ler tarefa adiada: %v", err)
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("erro ao reabrir tarefas adiadas: %v", err)
+		}
+
+		for _, id := range ids {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO task_status_history (id, task_id, from_status, to_status, note, created_at)
+				VALUES (?, ?, 'snoozed', 'pending', ?, ?)`,
+				newID(), id, entities.SnoozeExpiredNote, updatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("erro ao registrar histórico da tarefa: %v", err)
+			}
+		}
+		woken = int64(len(ids))
+		return nil
+	})
+	return woken, err
+}
@@ -0,0 +1,184 @@
+// Package sqlite implementa os repositórios sobre SQLite (driver em Go puro,
+// sem cgo), para instalações de um único nó sem PostgreSQL. Sem row-level
+// security, o isolamento entre tenants depende dos filtros dos repositórios.
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	_ "modernc.org/sqlite"
+)
+
+// Database representa a conexão com o arquivo SQLite
+type Database struct {
+	db *sql.DB
+}
+
+// Config configurações do banco SQLite
+type Config struct {
+	Path string // Ex: "email_filter.db"; ":memory:" para um banco temporário
+}
+
+// NewDatabase abre o banco e aplica as migrações pendentes
+func NewDatabase(ctx context.Context, cfg *Config) (*Database, error) {
+	if cfg == nil {
+		cfg = &Config{Path: os.Getenv("SQLITE_PATH")}
+	}
+	if cfg.Path == "" {
+		cfg.Path = "email_filter.db"
+	}
+
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)", cfg.Path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir banco SQLite: %v", err)
+	}
+
+	// Uma única conexão serializa as escritas e mantém bancos ":memory:"
+	db.SetMaxOpenConns(1)
+
+	d := &Database{db: db}
+	if _, err := d.MigrateUp(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// Close fecha o banco
+func (d *Database) Close() {
+	if d.db != nil {
+		d.db.Close()
+	}
+}
+
+// ExecuteInTransaction executa uma função dentro de uma transação
+func (d *Database) ExecuteInTransaction(ctx context.Context, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+
+	if err := fn(ctx, tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("erro no rollback após erro: %v (erro original: %v)", rbErr, err)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao commit da transação: %v", err)
+	}
+	return nil
+}
+
+// requireTenant retorna o tenant do contexto ou ErrNoTenantContext
+func requireTenant(ctx context.Context) (string, error) {
+	tenantID := entities.TenantFromContext(ctx)
+	if tenantID == "" {
+		return "", entities.ErrNoTenantContext
+	}
+	return tenantID, nil
+}
+
+// scopeTenant restringe a consulta ao tenant do contexto, como as políticas de
+// RLS do PostgreSQL
+func scopeTenant(ctx context.Context, b *queryBuilder, column string) {
+	if tenantID := entities.TenantFromContext(ctx); tenantID != "" {
+		b.where(column+" = ?", tenantID)
+	}
+}
+
+// newID gera um UUID v4, no mesmo formato dos IDs gerados pelo PostgreSQL
+func newID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// timeLayout formato de largura fixa das datas gravadas, ordenável como texto
+const timeLayout = "2006-01-02T15:04:05.000000Z"
+
+// now retorna o horário atual com a precisão das datas gravadas
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// formatTime converte a data para o formato gravado
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// timeColumn lê uma data gravada com formatTime
+type timeColumn struct {
+	dst *time.Time
+}
+
+func (c timeColumn) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*c.dst = time.Time{}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case time.Time:
+		*c.dst = v.UTC()
+		return nil
+	default:
+		return fmt.Errorf("tipo de data não suportado: %T", src)
+	}
+
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return fmt.Errorf("data inválida %q: %v", s, err)
+	}
+	*c.dst = t
+	return nil
+}
+
+// scanTime adapta o destino para leitura de datas
+func scanTime(dst *time.Time) timeColumn {
+	return timeColumn{dst: dst}
+}
+
+// formatNullTime converte a data opcional para o formato gravado (NULL se nil)
+func formatNullTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return formatTime(*t)
+}
+
+// nullTimeColumn lê uma data opcional gravada com formatNullTime
+type nullTimeColumn struct {
+	dst **time.Time
+}
+
+func (c nullTimeColumn) Scan(src interface{}) error {
+	if src == nil {
+		*c.dst = nil
+		return nil
+	}
+	var t time.Time
+	if err := scanTime(&t).Scan(src); err != nil {
+		return err
+	}
+	*c.dst = &t
+	return nil
+}
+
+// scanNullTime adapta o destino para leitura de datas opcionais
+func scanNullTime(dst **time.Time) nullTimeColumn {
+	return nullTimeColumn{dst: dst}
+}
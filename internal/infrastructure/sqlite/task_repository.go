@@ -0,0 +1,350 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.TaskRepository = (*TaskRepository)(nil)
+
+type TaskRepository struct {
+	db *Database
+}
+
+func NewTaskRepository(db *Database) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+const taskColumns = `
+	t.id, t.email_id, t.description, t.due_date, t.priority, t.status,
+	COALESCE(t.assignee_id, ''), t.snoozed_until, t.overdue_at,
+	t.created_at, t.updated_at`
+
+func (r *TaskRepository) Create(ctx context.Context, task *entities.Task) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+	if !task.Status.Valid() {
+		return fmt.Errorf("status inválido: %s", task.Status)
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		// Verificar se o email existe no tenant; o assunto vai no evento
+		var subject string
+		err := tx.QueryRowContext(ctx,
+			"SELECT subject FROM emails WHERE id = ? AND tenant_id = ?",
+			task.EmailID, tenantID,
+		).Scan(&subject)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("email não encontrado com id: %s", task.EmailID)
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao verificar email: %v", err)
+		}
+		if err := checkAssignee(ctx, tx, tenantID, task.AssigneeID); err != nil {
+			return err
+		}
+
+		if err := insertTask(ctx, tx, task); err != nil {
+			return err
+		}
+		event, err := entities.NewTaskEvent(tenantID, entities.EventTaskCreated, *task, subject)
+		if err != nil {
+			return err
+		}
+		return insertOutbox(ctx, tx, []*entities.Event{event})
+	})
+}
+
+func (r *TaskRepository) GetByID(ctx context.Context, id string) (*entities.Task, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := scanTask(r.db.db.QueryRowContext(ctx, `
+		SELECT`+taskColumns+`
+		FROM tasks t JOIN emails e ON t.email_id = e.id
+		WHERE t.id = ? AND e.tenant_id = ?`,
+		id, tenantID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar tarefa: %v", err)
+	}
+	return task, nil
+}
+
+func (r *TaskRepository) Update(ctx context.Context, task *entities.Task) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+	if task.Priority != entities.PriorityHigh &&
+		task.Priority != entities.PriorityMedium &&
+		task.Priority != entities.PriorityLow {
+		return fmt.Errorf("prioridade inválida: %s", task.Priority)
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if err := checkAssignee(ctx, tx, tenantID, task.AssigneeID); err != nil {
+			return err
+		}
+
+		updatedAt := now()
+		err := tx.QueryRowContext(ctx, `
+			UPDATE tasks SET
+				description = ?, due_date = ?, priority = ?, assignee_id = NULLIF(?, ''),
+				overdue_at = CASE WHEN due_date = ? THEN overdue_at END, updated_at = ?
+			WHERE id = ? AND email_id IN (SELECT id FROM emails WHERE tenant_id = ?)
+			RETURNING overdue_at`,
+			task.Description, formatTime(task.DueDate), task.Priority, task.AssigneeID,
+			formatTime(task.DueDate), formatTime(updatedAt), task.ID, tenantID,
+		).Scan(scanNullTime(&task.OverdueAt))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("tarefa não encontrada com id: %s", task.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar tarefa: %v", err)
+		}
+		task.UpdatedAt = updatedAt
+		return nil
+	})
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			DELETE FROM tasks
+			WHERE id = ? AND email_id IN (SELECT id FROM emails WHERE tenant_id = ?)`,
+			id, tenantID,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao deletar tarefa: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("tarefa não encontrada com id: %s", id)
+		}
+		return nil
+	})
+}
+
+func (r *TaskRepository) ListByTenant(ctx context.Context, tenantID string, filter *entities.TaskFilter) (*entities.PageResult[*entities.Task], error) {
+	if filter == nil {
+		filter = &entities.TaskFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	b := &queryBuilder{}
+	b.where("e.tenant_id = ?", tenantID)
+	applyTaskFilter(b, filter, "t.")
+	return r.list(ctx, b, filter)
+}
+
+func (r *TaskRepository) ListByEmail(ctx context.Context, emailID string, filter *entities.TaskFilter) (*entities.PageResult[*entities.Task], error) {
+	if filter == nil {
+		filter = &entities.TaskFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	b := &queryBuilder{}
+	b.where("t.email_id = ?", emailID)
+	applyTaskFilter(b, filter, "t.")
+	return r.list(ctx, b, filter)
+}
+
+func (r *TaskRepository) ListPendingTasks(ctx context.Context, userID string, filter *entities.TaskFilter) (*entities.PageResult[*entities.Task], error) {
+	if filter == nil {
+		filter = &entities.TaskFilter{}
+	}
+	// Por padrão, as tarefas com prazo mais próximo primeiro
+	if filter.Sort == "" {
+		filter.Sort = entities.TaskSortDueDate
+		if filter.Order == "" {
+			filter.Order = entities.SortAsc
+		}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	b := &queryBuilder{}
+	b.where("t.status IN ('pending', 'in_progress', 'snoozed')")
+	applyTaskFilter(b, &entities.TaskFilter{
+		UserID:     userID,
+		Priorities: filter.Priorities,
+		StartDate:  filter.StartDate,
+		EndDate:    filter.EndDate,
+	}, "t.")
+	return r.list(ctx, b, filter)
+}
+
+// list ordena e pagina as tarefas que atendem às condições de b
+func (r *TaskRepository) list(ctx context.Context, b *queryBuilder, filter *entities.TaskFilter) (*entities.PageResult[*entities.Task], error) {
+	p, err := entities.NewPager(filter.Page, filter.Sort == entities.TaskSortCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	const from = "tasks t JOIN emails e ON t.email_id = e.id"
+	scopeTenant(ctx, b, "e.tenant_id")
+	countQuery, countArgs := b.count(from)
+	b.cursorWhere(p, filter.Order, "t.")
+
+	query := "SELECT" + taskColumns + " FROM " + from + b.whereClause() +
+		orderBy(taskSortExpr(filter.Sort, "t."), p.Order(filter.Order), "t.") +
+		b.limit(p)
+
+	var tasks []*entities.Task
+	var total *int64
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if total, err = countTotal(ctx, tx, filter.Page, countQuery, countArgs); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, query, b.args...)
+		if err != nil {
+			return fmt.Errorf("erro ao listar tarefas: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			task, err := scanTask(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao ler tarefa: %v", err)
+			}
+			tasks = append(tasks, task)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := entities.Paginate(p, tasks, taskKey)
+	result.Total = total
+	return result, nil
+}
+
+func (r *TaskRepository) Transition(ctx context.Context, task *entities.Task, change *entities.TaskStatusChange) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		updatedAt := now()
+		// A condição sobre o status atual descarta mudanças concorrentes
+		result, err := tx.ExecContext(ctx, `
+			UPDATE tasks SET status = ?, snoozed_until = ?, updated_at = ?
+			WHERE id = ? AND status = ? AND email_id IN (SELECT id FROM emails WHERE tenant_id = ?)`,
+			change.ToStatus, formatNullTime(task.SnoozedUntil), formatTime(updatedAt),
+			task.ID, change.FromStatus, tenantID,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar status da tarefa: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("%w: tarefa %s não está mais em %s", entities.ErrInvalidTransition, task.ID, change.FromStatus)
+		}
+
+		change.ID = newID()
+		change.TaskID = task.ID
+		change.CreatedAt = updatedAt
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO task_status_history (id, task_id, from_status, to_status, actor_id, note, created_at)
+			VALUES (?, ?, ?, ?, NULLIF(?, ''), ?, ?)`,
+			change.ID, change.TaskID, change.FromStatus, change.ToStatus,
+			change.ActorID, change.Note, formatTime(change.CreatedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar histórico da tarefa: %v", err)
+		}
+		task.UpdatedAt = updatedAt
+		return nil
+	})
+}
+
+func (r *TaskRepository) History(ctx context.Context, taskID string) ([]*entities.TaskStatusChange, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.db.QueryContext(ctx, `
+		SELECT h.id, h.task_id, h.from_status, h.to_status, COALESCE(h.actor_id, ''), h.note, h.created_at
+		FROM task_status_history h
+		JOIN tasks t ON h.task_id = t.id
+		JOIN emails e ON t.email_id = e.id
+		WHERE h.task_id = ? AND e.tenant_id = ?
+		ORDER BY h.created_at, h.id`,
+		taskID, tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar histórico da tarefa: %v", err)
+	}
+	defer rows.Close()
+
+	var history []*entities.TaskStatusChange
+	for rows.Next() {
+		var c entities.TaskStatusChange
+		err := rows.Scan(&c.ID, &c.TaskID, &c.FromStatus, &c.ToStatus, &c.ActorID, &c.Note, scanTime(&c.CreatedAt))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler histórico da tarefa: %v", err)
+		}
+		history = append(history, &c)
+	}
+	return history, rows.Err()
+}
+
+// checkAssignee confirma que o responsável, se informado, pertence ao tenant
+func checkAssignee(ctx context.Context, tx *sql.Tx, tenantID, assigneeID string) error {
+	if assigneeID == "" {
+		return nil
+	}
+	var exists bool
+	err := tx.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM users WHERE id = ? AND tenant_id = ?)",
+		assigneeID, tenantID,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar responsável: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("usuário não encontrado com id: %s", assigneeID)
+	}
+	return nil
+}
+
+func scanTask(row rowScanner) (*entities.Task, error) {
+	var t entities.Task
+	err := row.Scan(
+		&t.ID, &t.EmailID, &t.Description, scanTime(&t.DueDate),
+		&t.Priority, &t.Status, &t.AssigneeID, scanNullTime(&t.SnoozedUntil), scanNullTime(&t.OverdueAt),
+		scanTime(&t.CreatedAt), scanTime(&t.UpdatedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// taskKey chave de paginação de uma tarefa
+func taskKey(t *entities.Task) (time.Time, string) {
+	return t.CreatedAt, t.ID
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.TenantRepository = (*TenantRepository)(nil)
+
+type TenantRepository struct {
+	db *Database
+}
+
+func NewTenantRepository(db *Database) *TenantRepository {
+	return &TenantRepository{db: db}
+}
+
+const tenantColumns = `id, name, plan, active, created_at, updated_at`
+
+func (r *TenantRepository) Create(ctx context.Context, tenant *entities.Tenant) error {
+	if tenant.ID == "" {
+		tenant.ID = newID()
+	}
+	if tenant.Plan == "" {
+		tenant.Plan = "free"
+	}
+	tenant.CreatedAt = now()
+	tenant.UpdatedAt = tenant.CreatedAt
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO tenants (id, name, plan, active, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			tenant.ID, tenant.Name, tenant.Plan, tenant.Active,
+			formatTime(tenant.CreatedAt), formatTime(tenant.UpdatedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("error creating tenant: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *TenantRepository) GetByID(ctx context.Context, id string) (*entities.Tenant, error) {
+	row := r.db.db.QueryRowContext(ctx, "SELECT "+tenantColumns+" FROM tenants WHERE id = ?", id)
+	return scanTenant(row)
+}
+
+func (r *TenantRepository) Update(ctx context.Context, tenant *entities.Tenant) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		updatedAt := now()
+		result, err := tx.ExecContext(ctx,
+			"UPDATE tenants SET name = ?, plan = ?, active = ?, updated_at = ? WHERE id = ?",
+			tenant.Name, tenant.Plan, tenant.Active, formatTime(updatedAt), tenant.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("error updating tenant: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("error updating tenant: tenant not found: %s", tenant.ID)
+		}
+		tenant.UpdatedAt = updatedAt
+		return nil
+	})
+}
+
+// Delete remove o tenant; usuários, emails, tarefas e jobs de backfill são
+// removidos em cascata
+func (r *TenantRepository) Delete(ctx context.Context, id string) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM tenants WHERE id = ?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting tenant: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("tenant not found: %s", id)
+		}
+		return nil
+	})
+}
+
+func (r *TenantRepository) List(ctx context.Context) ([]*entities.Tenant, error) {
+	rows, err := r.db.db.QueryContext(ctx, "SELECT "+tenantColumns+" FROM tenants ORDER BY created_at ASC")
+	if err != nil {
+		return nil, fmt.Errorf("error listing tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenants []*entities.Tenant
+	for rows.Next() {
+		tenant, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
+
+func scanTenant(row rowScanner) (*entities.Tenant, error) {
+	var t entities.Tenant
+	err := row.Scan(
+		&t.ID,
+		&t.Name,
+		&t.Plan,
+		&t.Active,
+		scanTime(&t.CreatedAt),
+		scanTime(&t.UpdatedAt),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning tenant: %w", err)
+	}
+	return &t, nil
+}
@@ -0,0 +1,182 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.UserRepository = (*UserRepository)(nil)
+
+type UserRepository struct {
+	db *Database
+}
+
+func NewUserRepository(db *Database) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+const userColumns = `id, tenant_id, name, email, password_hash, role, active, feed_token_version, created_at, updated_at`
+
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	if scope := entities.TenantFromContext(ctx); scope != "" && scope != user.TenantID {
+		return fmt.Errorf("error creating user: tenant %s out of scope", user.TenantID)
+	}
+	if user.ID == "" {
+		user.ID = newID()
+	}
+	if user.Role == "" {
+		user.Role = "user"
+	}
+	user.CreatedAt = now()
+	user.UpdatedAt = user.CreatedAt
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO users (`+userColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			user.ID, user.TenantID, user.Name, user.Email, user.PasswordHash,
+			user.Role, user.Active, user.FeedTokenVersion, formatTime(user.CreatedAt), formatTime(user.UpdatedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("error creating user: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*entities.User, error) {
+	b := &queryBuilder{}
+	b.where("id = ?", id)
+	scopeTenant(ctx, b, "tenant_id")
+
+	row := r.db.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users"+b.whereClause(), b.args...)
+	return scanUser(row)
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	// Usado no login, quando o tenant ainda não é conhecido
+	row := r.db.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE email = ?", email)
+	return scanUser(row)
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	b := &queryBuilder{}
+	b.where("id = ?", user.ID)
+	b.where("tenant_id = ?", user.TenantID)
+	scopeTenant(ctx, b, "tenant_id")
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		updatedAt := now()
+		args := append([]interface{}{
+			user.Name, user.Email, user.PasswordHash, user.Role, user.Active, formatTime(updatedAt),
+		}, b.args...)
+		result, err := tx.ExecContext(ctx, `
+			UPDATE users SET name = ?, email = ?, password_hash = ?, role = ?, active = ?, updated_at = ?`+
+			b.whereClause(),
+			args...,
+		)
+		if err != nil {
+			return fmt.Errorf("error updating user: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("error updating user: user not found: %s", user.ID)
+		}
+		user.UpdatedAt = updatedAt
+		return nil
+	})
+}
+
+func (r *UserRepository) RotateFeedToken(ctx context.Context, id string) (int, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			UPDATE users SET feed_token_version = feed_token_version + 1, updated_at = ?
+			WHERE id = ? AND tenant_id = ?
+			RETURNING feed_token_version`,
+			formatTime(now()), id, tenantID,
+		).Scan(&version)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user not found: %s", id)
+		}
+		if err != nil {
+			return fmt.Errorf("error rotating feed token: %w", err)
+		}
+		return nil
+	})
+	return version, err
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = ? AND tenant_id = ?", id, tenantID)
+		if err != nil {
+			return fmt.Errorf("error deleting user: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("user not found: %s", id)
+		}
+		// assignee_id não tem chave estrangeira (ver migração 003)
+		if _, err := tx.ExecContext(ctx, "UPDATE tasks SET assignee_id = NULL WHERE assignee_id = ?", id); err != nil {
+			return fmt.Errorf("error unassigning user tasks: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *UserRepository) ListByTenant(ctx context.Context, tenantID string) ([]*entities.User, error) {
+	b := &queryBuilder{}
+	b.where("tenant_id = ?", tenantID)
+	scopeTenant(ctx, b, "tenant_id")
+
+	rows, err := r.db.db.QueryContext(ctx,
+		"SELECT "+userColumns+" FROM users"+b.whereClause()+" ORDER BY created_at ASC",
+		b.args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error listing users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func scanUser(row rowScanner) (*entities.User, error) {
+	var u entities.User
+	err := row.Scan(
+		&u.ID,
+		&u.TenantID,
+		&u.Name,
+		&u.Email,
+		&u.PasswordHash,
+		&u.Role,
+		&u.Active,
+		&u.FeedTokenVersion,
+		scanTime(&u.CreatedAt),
+		scanTime(&u.UpdatedAt),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning user: %w", err)
+	}
+	return &u, nil
+}
@@ -0,0 +1,352 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+)
+
+var _ entities.WebhookRepository = (*WebhookRepository)(nil)
+
+type WebhookRepository struct {
+	db *Database
+}
+
+func NewWebhookRepository(db *Database) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const webhookColumns = `
+	id, tenant_id, url, secret, events, active, created_at, updated_at`
+
+const deliveryColumns = `
+	id, webhook_id, tenant_id, event_id, event, payload, status, attempts,
+	next_attempt_at, last_attempt_at, response_status, last_error, created_at, updated_at`
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *entities.Webhook) error {
+	if scope := entities.TenantFromContext(ctx); scope != "" && scope != webhook.TenantID {
+		return fmt.Errorf("erro ao criar webhook: tenant %s fora do escopo", webhook.TenantID)
+	}
+	events, err := json.Marshal(append([]entities.EventType{}, webhook.Events...))
+	if err != nil {
+		return fmt.Errorf("erro ao codificar eventos: %v", err)
+	}
+
+	id, createdAt := newID(), now()
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO webhooks (id, tenant_id, url, secret, events, active, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, webhook.TenantID, webhook.URL, webhook.Secret, string(events), webhook.Active,
+			formatTime(createdAt), formatTime(createdAt),
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao criar webhook: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	webhook.ID, webhook.CreatedAt, webhook.UpdatedAt = id, createdAt, createdAt
+	return nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*entities.Webhook, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	row := r.db.db.QueryRowContext(ctx,
+		"SELECT"+webhookColumns+" FROM webhooks WHERE id = ? AND tenant_id = ?",
+		id, tenantID,
+	)
+	webhook, err := scanWebhook(row)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar webhook: %v", err)
+	}
+	return webhook, nil
+}
+
+func (r *WebhookRepository) ListByTenant(ctx context.Context, tenantID string) ([]*entities.Webhook, error) {
+	b := &queryBuilder{}
+	b.where("tenant_id = ?", tenantID)
+	return r.list(ctx, b)
+}
+
+func (r *WebhookRepository) Subscribers(ctx context.Context, tenantID string, event entities.EventType) ([]*entities.Webhook, error) {
+	b := &queryBuilder{}
+	b.where("tenant_id = ?", tenantID)
+	b.where("active = 1")
+	b.where("EXISTS (SELECT 1 FROM json_each(events) WHERE value = ?)", string(event))
+	return r.list(ctx, b)
+}
+
+// list retorna os webhooks que atendem às condições de b
+func (r *WebhookRepository) list(ctx context.Context, b *queryBuilder) ([]*entities.Webhook, error) {
+	scopeTenant(ctx, b, "tenant_id")
+	rows, err := r.db.db.QueryContext(ctx,
+		"SELECT"+webhookColumns+" FROM webhooks"+b.whereClause()+" ORDER BY created_at, id",
+		b.args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar webhooks: %v", err)
+	}
+	defer rows.Close()
+
+	var webhooks []*entities.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler webhook: %v", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, webhook *entities.Webhook) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+	events, err := json.Marshal(append([]entities.EventType{}, webhook.Events...))
+	if err != nil {
+		return fmt.Errorf("erro ao codificar eventos: %v", err)
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		updatedAt := now()
+		result, err := tx.ExecContext(ctx, `
+			UPDATE webhooks SET url = ?, events = ?, active = ?, updated_at = ?
+			WHERE id = ? AND tenant_id = ?`,
+			webhook.URL, string(events), webhook.Active, formatTime(updatedAt), webhook.ID, tenantID,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar webhook: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("erro ao atualizar webhook: webhook não encontrado com id: %s", webhook.ID)
+		}
+		webhook.UpdatedAt = updatedAt
+		return nil
+	})
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM webhooks WHERE id = ? AND tenant_id = ?", id, tenantID)
+		if err != nil {
+			return fmt.Errorf("erro ao deletar webhook: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("webhook não encontrado com id: %s", id)
+		}
+		return nil
+	})
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	if scope := entities.TenantFromContext(ctx); scope != "" && scope != delivery.TenantID {
+		return fmt.Errorf("erro ao registrar entrega: tenant %s fora do escopo", delivery.TenantID)
+	}
+
+	id, createdAt := newID(), now()
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (
+				id, webhook_id, tenant_id, event_id, event, payload, status,
+				next_attempt_at, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, delivery.WebhookID, delivery.TenantID, delivery.EventID, delivery.Event,
+			string(delivery.Payload), delivery.Status, formatNullTime(delivery.NextAttemptAt),
+			formatTime(createdAt), formatTime(createdAt),
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar entrega: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	delivery.ID, delivery.CreatedAt, delivery.UpdatedAt = id, createdAt, createdAt
+	return nil
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id string) (*entities.WebhookDelivery, error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	row := r.db.db.QueryRowContext(ctx,
+		"SELECT"+deliveryColumns+" FROM webhook_deliveries WHERE id = ? AND tenant_id = ?",
+		id, tenantID,
+	)
+	delivery, err := scanDelivery(row)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar entrega: %v", err)
+	}
+	return delivery, nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID string, page entities.Page) (*entities.PageResult[*entities.WebhookDelivery], error) {
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := page.NormalizeKeyset(); err != nil {
+		return nil, err
+	}
+	p, err := entities.NewPager(page, true)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &queryBuilder{}
+	b.where("webhook_id = ?", webhookID)
+	b.where("tenant_id = ?", tenantID)
+	countQuery, countArgs := b.count("webhook_deliveries")
+	b.cursorWhere(p, entities.SortDesc, "")
+	query := "SELECT" + deliveryColumns + " FROM webhook_deliveries" + b.whereClause() +
+		orderBy("created_at", p.Order(entities.SortDesc), "") + b.limit(p)
+
+	var deliveries []*entities.WebhookDelivery
+	var total *int64
+	err = r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		if total, err = countTotal(ctx, tx, page, countQuery, countArgs); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, query, b.args...)
+		if err != nil {
+			return fmt.Errorf("erro ao listar entregas: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			delivery, err := scanDelivery(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao ler entrega: %v", err)
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := entities.Paginate(p, deliveries, deliveryKey)
+	result.Total = total
+	return result, nil
+}
+
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	// A transação de escrita do SQLite é exclusiva: instâncias concorrentes no
+	// mesmo arquivo não reservam as mesmas entregas
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= ?
+			ORDER BY next_attempt_at, id
+			LIMIT ?
+		)
+		RETURNING` + deliveryColumns
+
+	var deliveries []*entities.WebhookDelivery
+	err := r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query,
+			formatTime(now.Add(lease)), formatTime(now), formatTime(now), limit,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao reservar entregas: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			delivery, err := scanDelivery(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao ler entrega: %v", err)
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	return r.db.ExecuteInTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		updatedAt := now()
+		result, err := tx.ExecContext(ctx, `
+			UPDATE webhook_deliveries SET
+				status = ?, attempts = ?, next_attempt_at = ?, last_attempt_at = ?,
+				response_status = ?, last_error = ?, updated_at = ?
+			WHERE id = ?`,
+			delivery.Status, delivery.Attempts,
+			formatNullTime(delivery.NextAttemptAt), formatNullTime(delivery.LastAttemptAt),
+			delivery.ResponseStatus, delivery.LastError, formatTime(updatedAt), delivery.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar tentativa de entrega: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("erro ao registrar tentativa de entrega: entrega não encontrada com id: %s", delivery.ID)
+		}
+		delivery.UpdatedAt = updatedAt
+		return nil
+	})
+}
+
+func scanWebhook(row rowScanner) (*entities.Webhook, error) {
+	var webhook entities.Webhook
+	var events string
+	err := row.Scan(
+		&webhook.ID, &webhook.TenantID, &webhook.URL, &webhook.Secret, &events,
+		&webhook.Active, scanTime(&webhook.CreatedAt), scanTime(&webhook.UpdatedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(events), &webhook.Events); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar eventos: %v", err)
+	}
+	return &webhook, nil
+}
+
+func scanDelivery(row rowScanner) (*entities.WebhookDelivery, error) {
+	var d entities.WebhookDelivery
+	var payload string
+	err := row.Scan(
+		&d.ID, &d.WebhookID, &d.TenantID, &d.EventID, &d.Event, &payload, &d.Status, &d.Attempts,
+		scanNullTime(&d.NextAttemptAt), scanNullTime(&d.LastAttemptAt), &d.ResponseStatus, &d.LastError,
+		scanTime(&d.CreatedAt), scanTime(&d.UpdatedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = json.RawMessage(payload)
+	return &d, nil
+}
+
+// deliveryKey chave de paginação de uma entrega
+func deliveryKey(d *entities.WebhookDelivery) (time.Time, string) {
+	return d.CreatedAt, d.ID
+}
@@ -0,0 +1,114 @@
+// Package storage abre os repositórios do driver de armazenamento escolhido
+// em STORAGE_DRIVER, compartilhado pela API e pela importação de mbox/Maildir.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/database"
+	"github.com/enzo010/email-filter/internal/infrastructure/memory"
+	"github.com/enzo010/email-filter/internal/infrastructure/sqlite"
+)
+
+// Storage repositórios do driver de armazenamento configurado
+type Storage struct {
+	Emails       entities.EmailRepository
+	Tenants      entities.TenantRepository
+	Users        entities.UserRepository
+	Tasks        entities.TaskRepository
+	Reminders    entities.ReminderRepository
+	Backfill     entities.BackfillRepository
+	Webhooks     entities.WebhookRepository
+	Integrations entities.IntegrationRepository
+	Outbox       entities.OutboxRepository
+	FolderStates entities.FolderStateRepository
+	Close        func()
+}
+
+// Open cria os repositórios do driver: "postgres" (padrão), "sqlite"
+// para instalações de um único nó ou "memory", que não persiste dados e serve
+// para testes e demonstrações locais
+func Open(ctx context.Context, driver string) (*Storage, error) {
+	switch driver {
+	case "", "postgres":
+	case "sqlite":
+		// Configuração via SQLITE_PATH; as migrações são aplicadas ao abrir
+		db, err := sqlite.NewDatabase(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &Storage{
+			Emails:       sqlite.NewEmailRepository(db),
+			Tenants:      sqlite.NewTenantRepository(db),
+			Users:        sqlite.NewUserRepository(db),
+			Tasks:        sqlite.NewTaskRepository(db),
+			Reminders:    sqlite.NewReminderRepository(db),
+			Backfill:     sqlite.NewBackfillRepository(db),
+			Webhooks:     sqlite.NewWebhookRepository(db),
+			Integrations: sqlite.NewIntegrationRepository(db),
+			Outbox:       sqlite.NewOutboxRepository(db),
+			FolderStates: sqlite.NewFolderStateRepository(db),
+			Close:        db.Close,
+		}, nil
+	case "memory":
+		log.Printf("AVISO: usando armazenamento em memória; os dados serão perdidos ao encerrar o servidor")
+		store := memory.NewStore()
+		return &Storage{
+			Emails:       memory.NewEmailRepository(store),
+			Tenants:      memory.NewTenantRepository(store),
+			Users:        memory.NewUserRepository(store),
+			Tasks:        memory.NewTaskRepository(store),
+			Reminders:    memory.NewReminderRepository(store),
+			Backfill:     memory.NewBackfillRepository(store),
+			Webhooks:     memory.NewWebhookRepository(store),
+			Integrations: memory.NewIntegrationRepository(store),
+			Outbox:       memory.NewOutboxRepository(store),
+			FolderStates: memory.NewFolderStateRepository(store),
+			Close:        func() {},
+		}, nil
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER desconhecido: %s", driver)
+	}
+
+	// Inicializar banco de dados (configuração via variáveis DB_*)
+	db, err := database.NewDatabase(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recusar schema diferente do esperado por este binário
+	if err := db.CheckSchemaVersion(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Recusar usuários que ignoram as políticas de RLS (superusuário ou BYPASSRLS):
+	// o isolamento entre tenants não pode depender apenas dos filtros da aplicação
+	bypass, err := db.BypassesRowLevelSecurity(ctx)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("erro ao verificar isolamento de tenants: %v", err)
+	}
+	if bypass {
+		db.Close()
+		return nil, errors.New("o usuário do banco ignora row-level security (superusuário ou BYPASSRLS); conecte com um usuário sem esses privilégios")
+	}
+
+	return &Storage{
+		Emails:       database.NewEmailRepository(db),
+		Tenants:      database.NewTenantRepository(db),
+		Users:        database.NewUserRepository(db),
+		Tasks:        database.NewTaskRepository(db),
+		Reminders:    database.NewReminderRepository(db),
+		Backfill:     database.NewBackfillRepository(db),
+		Webhooks:     database.NewWebhookRepository(db),
+		Integrations: database.NewIntegrationRepository(db),
+		Outbox:       database.NewOutboxRepository(db),
+		FolderStates: database.NewFolderStateRepository(db),
+		Close:        db.Close,
+	}, nil
+}
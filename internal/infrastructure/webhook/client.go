@@ -0,0 +1,102 @@
+// Package webhook entrega eventos aos endpoints HTTP registrados pelos
+// tenants, com o payload assinado por HMAC-SHA256.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/safehttp"
+)
+
+// Cabeçalhos enviados em cada entrega
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// maxErrorBody trecho da resposta de erro guardado no registro de entregas
+const maxErrorBody = 512
+
+// NewSecret gera o segredo de assinatura de um novo webhook
+func NewSecret() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("erro ao gerar segredo do webhook: %v", err)
+	}
+	return "whsec_" + hex.EncodeToString(b[:]), nil
+}
+
+// Sign retorna a assinatura "sha256=<hex>" de HMAC-SHA256(secret,
+// "<timestamp>.<body>"). O timestamp assinado permite ao receptor recusar
+// entregas antigas reenviadas por terceiros.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify confere a assinatura de uma entrega, para uso pelos receptores
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Client envia as entregas via HTTP POST
+type Client struct {
+	http *http.Client
+}
+
+// NewClient cria o cliente com o tempo limite de cada requisição. Redirecionamentos
+// não são seguidos e contam como falha; conexões à rede interna são recusadas.
+func NewClient(timeout time.Duration) *Client {
+	client := safehttp.NewClient(timeout)
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &Client{http: client}
+}
+
+// Send envia o payload da entrega ao webhook, retornando o status HTTP da
+// resposta (0 quando não houve resposta). Respostas fora da faixa 2xx são erro.
+func (c *Client) Send(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("erro ao criar requisição do webhook: %v", err)
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "email-filter-webhooks/1.0")
+	req.Header.Set(EventHeader, string(delivery.Event))
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao enviar webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, fmt.Errorf("webhook respondeu com status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enzo010/email-filter/internal/domain/entities"
+	"github.com/enzo010/email-filter/internal/infrastructure/safehttp"
+)
+
+// receiver endpoint de testes que confere a assinatura como um receptor real
+type receiver struct {
+	secret string
+	status int
+
+	mu       sync.Mutex
+	received []http.Header
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+	if err != nil || !Verify(rc.secret, timestamp, body, r.Header.Get(SignatureHeader)) {
+		http.Error(w, "assinatura inválida", http.StatusUnauthorized)
+		return
+	}
+
+	rc.mu.Lock()
+	rc.received = append(rc.received, r.Header.Clone())
+	rc.bodies = append(rc.bodies, body)
+	rc.mu.Unlock()
+	if rc.status != 0 {
+		http.Error(w, "falha no receptor", rc.status)
+	}
+}
+
+// newLoopbackClient cliente com as mesmas regras de NewClient, exceto a
+// recusa de loopback, para alcançar o httptest.Server
+func newLoopbackClient() *Client {
+	return &Client{http: &http.Client{
+		Timeout: time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+func testDelivery() *entities.WebhookDelivery {
+	return &entities.WebhookDelivery{
+		ID:      "dlv-1",
+		EventID: "evt-1",
+		Event:   entities.EventEmailClassified,
+		Payload: json.RawMessage(`{"id":"evt-1","type":"email.classified"}`),
+	}
+}
+
+func TestClientSendSignsPayload(t *testing.T) {
+	rc := &receiver{secret: "whsec_teste"}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	hook := &entities.Webhook{URL: srv.URL, Secret: rc.secret}
+	delivery := testDelivery()
+	now := time.Unix(1760866800, 0)
+	status, err := newLoopbackClient().Send(context.Background(), hook, delivery, now)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Send = %d, %v", status, err)
+	}
+
+	if len(rc.received) != 1 {
+		t.Fatalf("receptor recebeu %d entregas, esperado 1", len(rc.received))
+	}
+	h := rc.received[0]
+	if h.Get(EventHeader) != string(entities.EventEmailClassified) || h.Get(DeliveryHeader) != "dlv-1" ||
+		h.Get(TimestampHeader) != "1760866800" || h.Get("Content-Type") != "application/json" {
+		t.Errorf("cabeçalhos = %v", h)
+	}
+	if string(rc.bodies[0]) != string(delivery.Payload) {
+		t.Errorf("corpo = %s, esperado %s", rc.bodies[0], delivery.Payload)
+	}
+}
+
+func TestClientSendRejectedSignature(t *testing.T) {
+	rc := &receiver{secret: "whsec_receptor"}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	// Segredo diferente do conhecido pelo receptor: a assinatura não confere
+	hook := &entities.Webhook{URL: srv.URL, Secret: "whsec_outro"}
+	status, err := newLoopbackClient().Send(context.Background(), hook, testDelivery(), time.Now())
+	if err == nil || status != http.StatusUnauthorized {
+		t.Errorf("Send = %d, %v; esperado 401 com erro", status, err)
+	}
+	if len(rc.received) != 0 {
+		t.Errorf("receptor aceitou %d entregas com assinatura inválida", len(rc.received))
+	}
+}
+
+func TestClientSendReceiverError(t *testing.T) {
+	rc := &receiver{secret: "whsec_teste", status: http.StatusServiceUnavailable}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	hook := &entities.Webhook{URL: srv.URL, Secret: rc.secret}
+	status, err := newLoopbackClient().Send(context.Background(), hook, testDelivery(), time.Now())
+	if err == nil || status != http.StatusServiceUnavailable {
+		t.Errorf("Send = %d, %v; esperado 503 com erro", status, err)
+	}
+}
+
+func TestClientRefusesInternalAddresses(t *testing.T) {
+	rc := &receiver{secret: "whsec_teste"}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	// O servidor escuta em loopback: NewClient recusa a conexão ao discar
+	hook := &entities.Webhook{URL: srv.URL, Secret: rc.secret}
+	status, err := NewClient(time.Second).Send(context.Background(), hook, testDelivery(), time.Now())
+	if !errors.Is(err, safehttp.ErrForbiddenAddress) || status != 0 {
+		t.Errorf("Send = %d, %v; esperado ErrForbiddenAddress", status, err)
+	}
+	if len(rc.received) != 0 {
+		t.Error("entrega feita a endereço de loopback")
+	}
+}